@@ -0,0 +1,279 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"project-initiator/internal/config"
+	"project-initiator/internal/domain"
+	"project-initiator/internal/flags"
+	"project-initiator/internal/scaffold"
+)
+
+// exitCancelled is returned when a batch is interrupted before finishing.
+const exitCancelled = 130
+
+// batchManifest lists the projects a batch run creates.
+type batchManifest struct {
+	Projects []batchProject `json:"projects"`
+}
+
+type batchProject struct {
+	Language  string   `json:"language"`
+	Framework string   `json:"framework"`
+	Name      string   `json:"name"`
+	Dir       string   `json:"dir,omitempty"`
+	Libraries []string `json:"libraries,omitempty"`
+}
+
+// Batch project outcomes.
+const (
+	statusCreated = "created"
+	statusFailed  = "failed"
+	statusSkipped = "skipped" // not started because the batch was cancelled
+)
+
+// batchResult is the outcome of one project in a batch.
+type batchResult struct {
+	Index    int           `json:"index"`
+	Name     string        `json:"name"`
+	Path     string        `json:"path,omitempty"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"-"`
+	Error    string        `json:"error,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// MarshalJSON reports the duration in milliseconds.
+func (r batchResult) MarshalJSON() ([]byte, error) {
+	type plain batchResult
+	out := struct {
+		plain
+		DurationMs int64 `json:"durationMs"`
+	}{plain: plain(r), DurationMs: r.Duration.Milliseconds()}
+	return json.Marshal(out)
+}
+
+// batchProgress receives events as a batch runs.
+type batchProgress interface {
+	Started(index int, total int, name string)
+	Finished(total int, result batchResult)
+	Summary(results []batchResult)
+}
+
+// batchRunner plans and applies projects one at a time. Cancelling the
+// context stops the batch between projects, never in the middle of one.
+type batchRunner struct {
+	planner *scaffold.Planner
+	// cfg supplies the defaults, disabled options and ports every project
+	// is built with, as for a --no-tui run.
+	cfg      config.Config
+	apply    func(domain.Plan) error
+	progress batchProgress
+}
+
+func (b batchRunner) run(ctx context.Context, projects []batchProject) []batchResult {
+	results := make([]batchResult, 0, len(projects))
+	for i, project := range projects {
+		result := batchResult{Index: i + 1, Name: project.Name}
+		if ctx.Err() != nil {
+			result.Status = statusSkipped
+			results = append(results, result)
+			b.progress.Finished(len(projects), result)
+			continue
+		}
+
+		b.progress.Started(i+1, len(projects), project.Name)
+		start := time.Now()
+		plan, err := b.runOne(project)
+		result.Duration = time.Since(start)
+		result.Path = plan.ProjectDir
+		result.Warnings = plan.Warnings
+		if err != nil {
+			result.Status = statusFailed
+			result.Error = err.Error()
+		} else {
+			result.Status = statusCreated
+		}
+		results = append(results, result)
+		b.progress.Finished(len(projects), result)
+	}
+	b.progress.Summary(results)
+	return results
+}
+
+// runOne builds the project's request the way a --no-tui run would, so
+// the manifest gets the same config checks and module path. The plan is
+// returned even when applying it fails, for its path and warnings.
+func (b batchRunner) runOne(project batchProject) (domain.Plan, error) {
+	cfg := b.cfg
+	req, _, err := buildRequest(flags.Options{
+		NoTUI:     true,
+		Language:  project.Language,
+		Framework: project.Framework,
+		Name:      project.Name,
+		Dir:       project.Dir,
+		Libraries: strings.Join(project.Libraries, ","),
+	}, &cfg)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	plan, err := b.planner.Plan(req)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	return plan, b.apply(plan)
+}
+
+func runBatch(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	opts, err := flags.ParseBatch(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	switch opts.Output {
+	case "", "text", "json":
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown --output %q (want text or json)\n", opts.Output)
+		return 2
+	}
+
+	cfg, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "config error:", err)
+		return 2
+	}
+
+	manifest, err := loadManifest(opts.Manifest)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	// Dirs are expanded per project by buildRequest; checking them here
+	// fails the batch before anything is created.
+	for i := range manifest.Projects {
+		manifest.Projects[i].Dir = firstNonEmpty(manifest.Projects[i].Dir, opts.Dir)
+		if _, err := expandDir(firstNonEmpty(manifest.Projects[i].Dir, cfg.DefaultDir)); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	// No one can answer a prompt between progress lines, so forced
+	// overwrites are reviewed as with --no-tui.
+	applyOpts := flags.Options{NoTUI: true, Force: opts.Force, Yes: opts.Yes}
+	applier := newApplier(applyOpts, cfg)
+	var progress batchProgress = &textProgress{w: stdout, stderr: stderr}
+	if opts.Output == "json" {
+		progress = &jsonProgress{enc: json.NewEncoder(stdout)}
+	}
+	runner := batchRunner{
+		planner: scaffold.DefaultPlanner(),
+		cfg:     cfg,
+		apply: func(plan domain.Plan) error {
+			if err := reviewOverwrites(applyOpts, applier, plan, stderr); err != nil {
+				return err
+			}
+			if _, err := applyPlan(plan, applier, &Timings{}, stderr, stderr); err != nil {
+				return err
+			}
+			plan.PostSteps = postSteps(plan.ProjectDir, defaultBranch, "")
+			setupGit(plan.PostSteps, defaultBranch, "", stderr)
+			return nil
+		},
+		progress: progress,
+	}
+
+	return batchExitCode(runner.run(ctx, manifest.Projects))
+}
+
+func loadManifest(path string) (batchManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return batchManifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+	var manifest batchManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return batchManifest{}, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if len(manifest.Projects) == 0 {
+		return batchManifest{}, errors.New("manifest lists no projects")
+	}
+	return manifest, nil
+}
+
+// batchExitCode is exitCancelled if any project was skipped, 1 if any
+// failed, and 0 otherwise.
+func batchExitCode(results []batchResult) int {
+	code := 0
+	for _, result := range results {
+		switch result.Status {
+		case statusSkipped:
+			return exitCancelled
+		case statusFailed:
+			code = 1
+		}
+	}
+	return code
+}
+
+// textProgress prints a line per project and a final table. A project's
+// warnings go to stderr, right after its line.
+type textProgress struct {
+	w      io.Writer
+	stderr io.Writer
+}
+
+func (p *textProgress) Started(index int, total int, name string) {
+	_, _ = fmt.Fprintf(p.w, "[%d/%d] %s ...\n", index, total, name)
+}
+
+func (p *textProgress) Finished(total int, result batchResult) {
+	switch result.Status {
+	case statusFailed:
+		_, _ = fmt.Fprintf(p.w, "[%d/%d] %s failed: %s\n", result.Index, total, result.Name, result.Error)
+	case statusSkipped:
+		_, _ = fmt.Fprintf(p.w, "[%d/%d] %s skipped\n", result.Index, total, result.Name)
+	default:
+		_, _ = fmt.Fprintf(p.w, "[%d/%d] %s created in %s\n", result.Index, total, result.Name, result.Duration.Round(time.Millisecond))
+	}
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintf(p.stderr, "[%d/%d] %s warning: %s\n", result.Index, total, result.Name, warning)
+	}
+}
+
+func (p *textProgress) Summary(results []batchResult) {
+	_, _ = fmt.Fprintln(p.w)
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tPATH\tSTATUS\tDURATION")
+	for _, result := range results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Name, result.Path, result.Status, result.Duration.Round(time.Millisecond))
+	}
+	_ = tw.Flush()
+}
+
+// jsonProgress writes one JSON object per finished project, then a summary.
+type jsonProgress struct {
+	enc *json.Encoder
+}
+
+func (p *jsonProgress) Started(int, int, string) {}
+
+func (p *jsonProgress) Finished(_ int, result batchResult) {
+	_ = p.enc.Encode(result)
+}
+
+func (p *jsonProgress) Summary(results []batchResult) {
+	summary := map[string]int{statusCreated: 0, statusFailed: 0, statusSkipped: 0}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+	_ = p.enc.Encode(map[string]any{"summary": summary})
+}
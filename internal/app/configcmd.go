@@ -0,0 +1,65 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"project-initiator/internal/config"
+	"project-initiator/internal/flags"
+)
+
+// runConfig lists the config keys with their values, prints one key, or
+// sets one and saves the config.
+func runConfig(args []string, stdout io.Writer, stderr io.Writer) int {
+	opts, err := flags.ParseConfig(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cfg, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "config error:", err)
+		return 2
+	}
+
+	if len(opts.Args) == 0 {
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		for _, key := range config.Keys {
+			value, _ := cfg.Get(key.Name)
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", key.Name, value, key.Description)
+		}
+		_ = tw.Flush()
+		return 0
+	}
+
+	key := opts.Args[1]
+	if !config.IsKey(key) {
+		_, _ = fmt.Fprintf(stderr, "unknown config key %q; run project-initiator config to list them\n", key)
+		return 2
+	}
+
+	if opts.Args[0] == "get" {
+		value, err := cfg.Get(key)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 2
+		}
+		_, _ = fmt.Fprintln(stdout, value)
+		return 0
+	}
+
+	if err := cfg.Set(key, opts.Args[2]); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	err = config.Update(opts.ConfigPath, func(saved *config.Config) error {
+		return saved.Set(key, opts.Args[2])
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "config error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"project-initiator/internal/domain"
+)
+
+// Result describes a single-project run, for Go programs that drive the
+// tool through Execute. Batch and --monorepo runs leave it empty and only
+// report failures through the returned error.
+type Result struct {
+	Language  string
+	Framework string
+	Name      string
+	Libraries []string
+	DryRun    bool
+
+	// Plan is the scaffolding plan, set once planning succeeds.
+	Plan domain.Plan
+	// Created lists the files written, in plan order. It is empty for dry
+	// runs and for generator-based frameworks, whose files the generator writes.
+	Created []string
+
+	GitInitialized bool
+	GitBranch      string
+	GitRemote      string
+
+	// Timings records how long each phase took.
+	Timings Timings
+}
+
+// ExitError reports a run that would have exited non-zero on the command line.
+type ExitError struct {
+	// Code is the exit code the CLI would return.
+	Code int
+	// Output is what the CLI would have printed to stderr.
+	Output string
+}
+
+func (e *ExitError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Output
+}
+
+// Execute runs the tool with CLI arguments and returns what it did instead
+// of printing it. Without --no-tui and the flags it needs, it still opens
+// the wizard. A non-zero exit is returned as an *ExitError alongside the
+// partial result.
+func Execute(args []string) (Result, error) {
+	var stderr bytes.Buffer
+	result, code := execute(args, io.Discard, &stderr)
+	if code != 0 {
+		return result, &ExitError{Code: code, Output: strings.TrimSpace(stderr.String())}
+	}
+	return result, nil
+}
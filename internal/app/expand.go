@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	apperrors "project-initiator/internal/errors"
+)
+
+// expandDir resolves a leading ~ or ~user and $VAR or ${VAR} references in
+// a --dir or defaultDir value, the way a shell would have if the value had
+// been typed on the command line. An unset variable is an error rather
+// than an empty string, which would quietly move the project elsewhere.
+// The config keeps the raw value, so the expansion follows the environment
+// of each run.
+func expandDir(dir string) (string, error) {
+	var unset []string
+	dir = os.Expand(dir, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return value
+	})
+	if len(unset) > 0 {
+		return "", apperrors.NewValidationError("dir", fmt.Sprintf("%s is not set", strings.Join(unset, ", ")))
+	}
+	return expandTilde(dir)
+}
+
+// expandTilde replaces a leading ~ with the current user's home directory
+// and ~name with that user's.
+func expandTilde(dir string) (string, error) {
+	if !strings.HasPrefix(dir, "~") {
+		return dir, nil
+	}
+	name, rest := dir[1:], ""
+	if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i+1:]
+	}
+
+	var home string
+	if name == "" {
+		h, err := os.UserHomeDir()
+		if err != nil {
+			return "", apperrors.NewValidationError("dir", fmt.Sprintf("expand ~: %v", err))
+		}
+		home = h
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", apperrors.NewValidationError("dir", fmt.Sprintf("expand ~%s: %v", name, err))
+		}
+		home = u.HomeDir
+	}
+	return filepath.Join(home, rest), nil
+}
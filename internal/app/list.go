@@ -0,0 +1,127 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"project-initiator/internal/domain"
+	"project-initiator/internal/scaffold"
+)
+
+// listSampleName is the project name --list --output json plans each
+// option with to find the files it creates.
+const listSampleName = "my-project"
+
+// optionJSON is one option in --list --output json.
+type optionJSON struct {
+	Language    string `json:"language"`
+	Framework   string `json:"framework"`
+	Description string `json:"description"`
+	Recommended bool   `json:"recommended"`
+	Deprecated  string `json:"deprecated,omitempty"`
+	ReplacedBy  string `json:"replacedBy,omitempty"`
+	// Generator is set for options created by an external tool rather than
+	// from templates; GeneratorBinary names the tool.
+	Generator       bool          `json:"generator"`
+	GeneratorBinary string        `json:"generatorBinary,omitempty"`
+	Libraries       []libraryJSON `json:"libraries"`
+	// Files are the paths, relative to the project dir, a project named
+	// listSampleName gets without libraries. Generators create their own.
+	Files     []string `json:"files"`
+	NextSteps []string `json:"nextSteps"`
+}
+
+type libraryJSON struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Deprecated  string `json:"deprecated,omitempty"`
+}
+
+func printOptions(w io.Writer, options []domain.Framework) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, opt := range options {
+		description := opt.Description
+		if opt.Deprecated != "" || opt.ReplacedBy != "" {
+			description = strings.TrimSpace(description + " " + deprecatedNote(opt))
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", opt.Language, opt.Name, description)
+	}
+	_ = tw.Flush()
+}
+
+// printOptionsJSON writes options with everything known about them before
+// a project is created, for generating docs.
+func printOptionsJSON(w io.Writer, options []domain.Framework) error {
+	planner := scaffold.DefaultPlanner()
+	out := make([]optionJSON, 0, len(options))
+	for _, opt := range options {
+		entry, err := newOptionJSON(planner, opt)
+		if err != nil {
+			return err
+		}
+		out = append(out, entry)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{"options": out})
+}
+
+func newOptionJSON(planner *scaffold.Planner, opt domain.Framework) (optionJSON, error) {
+	entry := optionJSON{
+		Language:    opt.Language,
+		Framework:   opt.Name,
+		Description: opt.Description,
+		Recommended: opt.Recommended,
+		Deprecated:  opt.Deprecated,
+		ReplacedBy:  opt.ReplacedBy,
+		Generator:   opt.Generator != "",
+		Libraries:   []libraryJSON{},
+		Files:       []string{},
+	}
+	if entry.Generator {
+		binary, _, err := generatorCommand(opt.Generator, listSampleName)
+		if err != nil {
+			return optionJSON{}, err
+		}
+		entry.GeneratorBinary = binary
+	}
+	for _, lib := range scaffold.OfferedLibraries(opt) {
+		entry.Libraries = append(entry.Libraries, libraryJSON{Name: lib.Name, Description: lib.Description, Deprecated: lib.Deprecated})
+	}
+
+	plan, err := planner.Plan(scaffold.Request{Language: opt.Language, Framework: opt.Name, Name: listSampleName, Dir: "."})
+	if err != nil {
+		return optionJSON{}, fmt.Errorf("plan %s/%s: %w", opt.Language, opt.Name, err)
+	}
+	for _, action := range plan.Actions {
+		rel, err := filepath.Rel(plan.ProjectDir, action.Path)
+		if err != nil {
+			return optionJSON{}, err
+		}
+		entry.Files = append(entry.Files, filepath.ToSlash(rel))
+	}
+	entry.NextSteps = planner.NextSteps(opt.Language, opt.Name, plan.Port)
+	if entry.NextSteps == nil {
+		entry.NextSteps = []string{}
+	}
+	return entry, nil
+}
+
+// deprecatedNote marks a deprecated option in --list, e.g.
+// "(deprecated: no longer maintained; replaced by Elysia)".
+func deprecatedNote(opt domain.Framework) string {
+	var parts []string
+	if opt.Deprecated != "" {
+		parts = append(parts, "deprecated: "+opt.Deprecated)
+	} else {
+		parts = append(parts, "deprecated")
+	}
+	if opt.ReplacedBy != "" {
+		parts = append(parts, "replaced by "+opt.ReplacedBy)
+	}
+	return "(" + strings.Join(parts, "; ") + ")"
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"project-initiator/internal/config"
+	"project-initiator/internal/flags"
+	"project-initiator/internal/manpage"
+)
+
+// exitCodes documents the codes execute returns.
+var exitCodes = []manpage.Entry{
+	{Name: "0", Description: "The project was created, or the dry run or subcommand succeeded."},
+	{Name: "1", Description: "Planning, writing, git setup or --verify failed, or a batch project failed."},
+	{Name: "2", Description: "Invalid flags, arguments or config."},
+	{Name: strconv.Itoa(exitExists), Description: "The project, or a file in it, already exists."},
+	{Name: strconv.Itoa(exitCancelled), Description: "A batch was interrupted before every project was created."},
+}
+
+// envVars documents the environment variables the tool reads. A test
+// checks that every os.Getenv and os.LookupEnv of a fixed name is listed.
+var envVars = []manpage.Entry{
+	{Name: "HOME", Description: "Locates the default config file and the default project directory."},
+	{Name: "TERM", Description: "The title falls back to plain text on the dumb and linux terminals."},
+	{Name: "LC_ALL, LC_CTYPE, LANG", Description: "The first one set must name a UTF-8 locale for the block-letter title."},
+	{Name: "ACCESSIBLE", Description: "Any value but 0 or false turns on accessible mode, as --accessible does."},
+	{Name: "NO_ANIMATION", Description: "Any value but 0 or false turns wizard animation off unless --transitions is given."},
+	{Name: "PAGER", Description: "Pages the --force diff before asking to overwrite; less is used when unset."},
+}
+
+// runMan prints the man page, in roff or Markdown.
+func runMan(args []string, stdout io.Writer, stderr io.Writer) int {
+	opts, err := flags.ParseMan(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	page := manPage()
+	switch opts.Format {
+	case "", "roff":
+		err = manpage.Roff(stdout, page)
+	case "md", "markdown":
+		err = manpage.Markdown(stdout, page)
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown --format %q (want roff or md)\n", opts.Format)
+		return 2
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// manPage assembles the man page from the flag, config key, environment
+// and exit code tables.
+func manPage() manpage.Page {
+	keys := make([]manpage.Entry, 0, len(config.Keys))
+	for _, key := range config.Keys {
+		keys = append(keys, manpage.Entry{Name: key.Name, Description: key.Description})
+	}
+
+	return manpage.Page{
+		Name:    "project-initiator",
+		Section: 1,
+		Summary: "scaffold new projects from templates",
+		Synopsis: []string{
+			"project-initiator [flags]",
+			"project-initiator batch [flags] <manifest>",
+			"project-initiator config [flags] [get <key> | set <key> <value>]",
+			"project-initiator man [flags]",
+			"project-initiator serve --stdio [flags]",
+		},
+		Description: "project-initiator creates a project from a language and framework template, " +
+			"initializes a git repository in it and remembers your choices. " +
+			"Without --name, --lang and --framework it asks for them in an interactive wizard.",
+		Sections: []manpage.Section{
+			{Title: "Options", Entries: flagEntries(flags.Definitions())},
+			{
+				Title:   "Batch options",
+				Text:    "batch creates every project listed in a JSON manifest without prompting.",
+				Entries: flagEntries(flags.BatchDefinitions()),
+			},
+			{
+				Title:   "Config options",
+				Text:    "config lists every config key, prints one with get, or changes one with set.",
+				Entries: flagEntries(flags.ConfigDefinitions()),
+			},
+			{
+				Title:   "Man options",
+				Text:    "man prints this page.",
+				Entries: flagEntries(flags.ManDefinitions()),
+			},
+			{
+				Title: "Serve options",
+				Text: "serve --stdio answers newline-delimited JSON requests on stdin, one response line each, " +
+					"for editor integrations. The methods are listOptions, validateName, plan and apply.",
+				Entries: flagEntries(flags.ServeDefinitions()),
+			},
+			{
+				Title:   "Configuration",
+				Text:    "Settings are stored as JSON in ~/.project-initiator.json, or the file given by --config.",
+				Entries: keys,
+			},
+			{Title: "Environment", Entries: envVars},
+			{Title: "Exit status", Entries: exitCodes},
+		},
+	}
+}
+
+func flagEntries(defs []flags.Flag) []manpage.Entry {
+	entries := make([]manpage.Entry, 0, len(defs))
+	for _, def := range defs {
+		entries = append(entries, manpage.Entry{
+			Name:        "--" + def.Name,
+			Arg:         def.Arg,
+			Default:     def.Default,
+			Description: def.Usage,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+
+	"project-initiator/internal/diff"
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+	"project-initiator/internal/flags"
+	"project-initiator/internal/scaffold"
+	"project-initiator/internal/ui"
+)
+
+// reviewOverwrites shows what --force is about to change in files that
+// already exist, such as go.mod and main.go when libraries are added to a
+// project, and asks before going on. With --no-tui or without a terminal
+// there is no one to ask, so it prints the diff and fails unless --yes was
+// given. Declining fails the run before anything is written.
+func reviewOverwrites(opts flags.Options, applier *scaffold.Applier, plan domain.Plan, stderr io.Writer) error {
+	if !opts.Force || opts.Yes || plan.Generator != "" {
+		return nil
+	}
+	text, err := overwriteDiff(applier, plan)
+	if err != nil || text == "" {
+		return err
+	}
+	if opts.NoTUI || !canPrompt() {
+		_, _ = fmt.Fprint(stderr, text)
+		return apperrors.NewValidationError("force", "existing files would change; pass --yes to overwrite them without a prompt")
+	}
+	if !confirmOverwrite(text, stderr) {
+		return apperrors.NewValidationError("force", "existing files were not overwritten")
+	}
+	return nil
+}
+
+// overwriteDiff is a unified diff from each existing file Apply would
+// overwrite to its planned content, with paths relative to the project.
+func overwriteDiff(applier *scaffold.Applier, plan domain.Plan) (string, error) {
+	paths, err := applier.Conflicts(plan)
+	if err != nil {
+		return "", err
+	}
+	planned := make(map[string]string, len(plan.Actions))
+	for _, action := range plan.Actions {
+		planned[action.Path] = action.Content
+	}
+
+	var b strings.Builder
+	for _, path := range paths {
+		existing, err := os.ReadFile(path)
+		if err != nil {
+			// Directories in the way are reported by Apply itself.
+			continue
+		}
+		rel := path
+		if r, err := filepath.Rel(plan.ProjectDir, path); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+		b.WriteString(diff.Unified("a/"+rel, "b/"+rel, string(existing), planned[path]))
+	}
+	return b.String(), nil
+}
+
+// canPrompt reports whether stdin is a terminal that can answer
+// confirmOverwrite. It is a variable so tests can stand in for one.
+var canPrompt = func() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// confirmOverwrite shows the diff and asks on the terminal whether to
+// overwrite, defaulting to no. It is a variable so tests can answer
+// without one.
+var confirmOverwrite = func(text string, stderr io.Writer) bool {
+	page(colorDiff(text), stderr)
+	_, _ = fmt.Fprint(stderr, "Overwrite these files? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// page shows text through $PAGER, or less, falling back to writing it to
+// w when neither runs.
+func page(text string, w io.Writer) {
+	var cmd *exec.Cmd
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		cmd = exec.Command(pager[0], pager[1:]...)
+	} else if less, err := exec.LookPath("less"); err == nil {
+		// Keep colors, and skip paging when the diff fits on one screen.
+		cmd = exec.Command(less, "-R", "-F", "-X")
+	}
+	if cmd != nil {
+		cmd.Stdin = strings.NewReader(text)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if cmd.Run() == nil {
+			return
+		}
+	}
+	_, _ = fmt.Fprint(w, text)
+}
+
+// colorDiff colors a unified diff's lines in the wizard's palette.
+func colorDiff(text string) string {
+	header := lipgloss.NewStyle().Bold(true).Foreground(ui.Text)
+	hunk := lipgloss.NewStyle().Foreground(ui.Accent)
+	added := lipgloss.NewStyle().Foreground(ui.Green)
+	removed := lipgloss.NewStyle().Foreground(ui.Red)
+	context := lipgloss.NewStyle().Foreground(ui.Muted)
+
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		body := strings.TrimSuffix(line, "\n")
+		if body == "" {
+			continue
+		}
+		style := context
+		switch {
+		case strings.HasPrefix(body, "--- "), strings.HasPrefix(body, "+++ "):
+			style = header
+		case strings.HasPrefix(body, "@@"):
+			style = hunk
+		case strings.HasPrefix(body, "+"):
+			style = added
+		case strings.HasPrefix(body, "-"):
+			style = removed
+		}
+		lines[i] = style.Render(body) + strings.TrimPrefix(line, body)
+	}
+	return strings.Join(lines, "")
+}
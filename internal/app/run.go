@@ -1,107 +1,708 @@
 package app
 
 import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 
 	"project-initiator/internal/config"
 	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
 	"project-initiator/internal/flags"
 	"project-initiator/internal/scaffold"
 	"project-initiator/internal/ui"
 )
 
+// Run executes the CLI with the given arguments and returns the process exit code.
 func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	_, code := execute(args, stdout, stderr)
+	return code
+}
+
+// execute runs the CLI, printing as it goes, and returns what it did along
+// with the exit code.
+func execute(args []string, stdout io.Writer, stderr io.Writer) (Result, int) {
+	var result Result
+	if len(args) > 0 && args[0] == "batch" {
+		// Ctrl-C lets the in-flight project finish, then stops the batch.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		return result, runBatch(ctx, args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "man" {
+		return result, runMan(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "config" {
+		return result, runConfig(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "serve" {
+		return result, runServe(args[1:], os.Stdin, stdout, stderr)
+	}
+
 	opts, err := flags.Parse(args)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		return 2
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, 2
+	}
+
+	if opts.ProfileExport != "" || opts.ProfileImport != "" {
+		return result, runProfile(opts, stdout, stderr)
+	}
+
+	switch opts.Output {
+	case "", "text", "json":
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown --output %q (want text or json)\n", opts.Output)
+		return result, 2
+	}
+
+	if opts.Remote != "" {
+		if err := validateRemote(opts.Remote); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return result, 2
+		}
+	}
+
+	if opts.Branch != "" {
+		if err := validateBranch(opts.Branch); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return result, 2
+		}
+	}
+
+	if err := config.ValidateLayout(opts.Layout); err != nil {
+		_, _ = fmt.Fprintln(stderr, apperrors.NewValidationError("layout", err.Error()))
+		return result, 2
+	}
+
+	if opts.Transitions, err = config.ParseTransitions(opts.Transitions); err != nil {
+		_, _ = fmt.Fprintln(stderr, apperrors.NewValidationError("transitions", err.Error()))
+		return result, 2
 	}
 
 	cfg, err := config.Load(opts.ConfigPath)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "config error:", err)
-		return 2
+		_, _ = fmt.Fprintln(stderr, "config error:", err)
+		return result, 2
+	}
+	// Diagnostics go to stderr so stdout stays parseable for --quiet and JSON.
+	if opts.Verbose {
+		_, _ = fmt.Fprintln(stderr, "Config:", strings.Join(config.Paths(opts.ConfigPath), ", "))
+	}
+
+	if opts.List {
+		options := scaffold.ListOptions(scaffold.Frameworks, disabledOptions(cfg))
+		if opts.Output == "json" {
+			if err := printOptionsJSON(stdout, options); err != nil {
+				_, _ = fmt.Fprintln(stderr, "list error:", err)
+				return result, 1
+			}
+			return result, 0
+		}
+		printOptions(stdout, options)
+		return result, 0
+	}
+
+	if opts.Monorepo != "" {
+		return result, runMonorepo(opts, cfg, stdout, stderr)
 	}
 
-	request, err := buildRequest(opts, cfg)
+	pinned := slices.Clone(cfg.Pinned)
+	request, wizardSteps, err := buildRequest(opts, &cfg)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		return 2
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, 2
+	}
+	setup := summarizeSetup(opts, cfg, request, wizardSteps)
+
+	result.Language = request.Language
+	result.Framework = request.Framework
+	result.Name = request.Name
+	result.Libraries = request.Libraries
+	result.DryRun = request.DryRun
+
+	// Pins toggled in the wizard are kept even if the run stops short of applying.
+	if !slices.Equal(pinned, cfg.Pinned) {
+		saveConfig(opts.ConfigPath, stderr, func(saved *config.Config) error {
+			saved.Pinned = cfg.Pinned
+			return nil
+		})
+	}
+
+	if opts.PrintDir {
+		dir, err := scaffold.DefaultPlanner().ProjectPath(request)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return result, 1
+		}
+		_, _ = fmt.Fprintln(stdout, dir)
+		return result, 0
 	}
 
+	planStart := time.Now()
 	plan, err := scaffold.DefaultPlanner().Plan(request)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		return 1
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, 1
+	}
+	plan.PostSteps = postSteps(plan.ProjectDir, gitBranch(opts), opts.Remote)
+	result.Plan = plan
+	result.Timings.Plan = time.Since(planStart)
+	if opts.Verbose {
+		printDuration(stderr, "Plan", result.Timings.Plan)
+	}
+	// A dir such as "~/work/{slug}" asks for the repetition.
+	if scaffold.DoubleNested(plan.ProjectDir, request.Layout) && !scaffold.NamedByPlaceholder(request.Dir) {
+		_, _ = fmt.Fprintf(stderr, "warning: %s repeats the project name in its path; use --flatten to collapse it\n", plan.ProjectDir)
+	}
+	printWarnings(stderr, plan)
+
+	if opts.EmitScript != "" {
+		return result, emitScript(opts.EmitScript, plan, stdout, stderr)
 	}
 
 	if opts.DryRun {
-		printPlan(plan)
-		return 0
+		return result, dryRun(opts, newApplier(opts, cfg), plan, stdout, stderr)
 	}
 
-	if plan.Generator != "" {
-		if err := runGenerator(plan.Generator, plan.ProjectDir); err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err)
+	if opts.CheckSpace {
+		if err := checkSpace(plan.ProjectDir, []domain.Plan{plan}, stderr); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return result, 1
+		}
+	}
+
+	applyStart := time.Now()
+	applier := newApplier(opts, cfg)
+	if err := ensureBaseDir(opts, applier, plan.BaseDir, stderr); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, 1
+	}
+	if err := reviewOverwrites(opts, applier, plan, stderr); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, 1
+	}
+	// Per-file lines would break --quiet's single path line and JSON output.
+	if (opts.ShowFiles || opts.Verbose) && !opts.Quiet && opts.Output != "json" {
+		applier.Progress = newFileProgress(stdout, plan.ProjectDir, opts.ASCII || !ui.SupportsBlockGlyphs()).report
+	}
+	created, err := applyPlan(plan, applier, &result.Timings, stdout, stderr)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return result, applyExitCode(err)
+	}
+	result.Created = created
+	if opts.Verbose {
+		printDuration(stderr, "Apply", time.Since(applyStart))
+	}
+
+	gitStart := time.Now()
+	git := setupGit(plan.PostSteps, gitBranch(opts), opts.Remote, stderr)
+	result.Timings.Git = time.Since(gitStart)
+	result.GitInitialized = git.initialized
+	result.GitBranch = git.branch
+	result.GitRemote = git.remote
+
+	code := 0
+	if opts.Verify {
+		verifyStart := time.Now()
+		code = verifyProject(request.Language, plan.ProjectDir, stderr)
+		result.Timings.Verify = time.Since(verifyStart)
+	}
+
+	saveConfig(opts.ConfigPath, stderr, func(saved *config.Config) error {
+		saved.DefaultLanguage = request.Language
+		saved.DefaultFramework = request.Framework
+		saved.RememberFramework(request.Language, request.Framework)
+		// Save the dir as given, before ~ and $VAR expansion.
+		saved.DefaultDir = firstNonEmpty(opts.Dir, saved.DefaultDir)
+		saved.RememberName(request.Name)
+		return nil
+	})
+
+	if opts.Quiet {
+		// stdout carries only the path for scripts; the name goes to stderr
+		// so logs can be correlated without polluting it.
+		_, _ = fmt.Fprintln(stdout, plan.ProjectDir)
+		_, _ = fmt.Fprintln(stderr, "name:", request.Name)
+		return result, code
+	}
+
+	printSuccess(stdout, request, plan, created, git, setup, result.Timings)
+	return result, code
+}
+
+// runMonorepo scaffolds every project queued in the wizard into
+// subdirectories of a shared root, which gets a single git repository.
+func runMonorepo(opts flags.Options, cfg config.Config, stdout io.Writer, stderr io.Writer) int {
+	if opts.NoTUI {
+		_, _ = fmt.Fprintln(stderr, "--monorepo needs the wizard and cannot be used with --no-tui")
+		return 2
+	}
+	rootName := strings.TrimSpace(opts.Monorepo)
+	if rootName == "" || strings.ContainsAny(rootName, `/\`) {
+		_, _ = fmt.Fprintln(stderr, apperrors.NewValidationError("monorepo", "root must be a single directory name"))
+		return 2
+	}
+	// One module path cannot name several projects; each Go project takes
+	// the origin remote's prefix instead.
+	if strings.TrimSpace(opts.Module) != "" {
+		_, _ = fmt.Fprintln(stderr, apperrors.NewValidationError("module", "--module names a single Go module and cannot be used with --monorepo"))
+		return 2
+	}
+
+	base, err := expandDir(firstNonEmpty(opts.Dir, cfg.DefaultDir))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	// Projects are planned beneath root, so placeholders in the dir are
+	// filled in for the monorepo itself rather than for each project.
+	root := filepath.Join(scaffold.ExpandDir(base, rootName), rootName)
+	projectBase := baseRequest(opts, cfg, root)
+	disabled := disabledOptions(cfg)
+	language := firstNonEmpty(opts.Language, cfg.DefaultLanguage)
+	framework, askFramework := frameworkDefault(opts, cfg)
+	result, err := startWizard(opts, language, framework, root, ui.Options{
+		Frameworks:       scaffold.ListOptions(scaffold.Frameworks, disabled),
+		DefaultLanguage:  language,
+		DefaultFramework: framework,
+		AskFramework:     askFramework,
+		Pinned:           cfg.Pinned,
+		Monorepo:         true,
+		Preview:          previewPlan(projectBase, cfg, ""),
+		ASCII:            opts.ASCII || !ui.SupportsBlockGlyphs(),
+		Transitions:      transitionSpeed(opts, cfg),
+		Accessible:       opts.Accessible || ui.AccessibleRequested(),
+		SkipSplash:       cfg.SkipSplash,
+		ConfigFiles:      config.Paths(opts.ConfigPath),
+		Clipboard:        wizardClipboard(opts),
+		NameHistory:      cfg.NameHistory,
+		LastFrameworks:   cfg.LastFrameworkByLang,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	cfg.Pinned = result.Pinned
+
+	requests := make([]scaffold.Request, 0, len(result.Projects))
+	plans := make([]domain.Plan, 0, len(result.Projects))
+	planner := scaffold.DefaultPlanner()
+	var timings Timings
+	planStart := time.Now()
+	// Plan everything up front so a bad selection fails before anything is written.
+	for _, project := range result.Projects {
+		request, err := selectRequest(projectBase, cfg, "", project)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 2
+		}
+		plan, err := planner.Plan(request)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+		printWarnings(stderr, plan)
+		requests = append(requests, request)
+		plans = append(plans, plan)
+	}
+	timings.Plan = time.Since(planStart)
+	// Git runs once at the root after every project is written, so its
+	// steps belong to the monorepo rather than to any one project.
+	steps := postSteps(root, gitBranch(opts), opts.Remote)
+
+	if opts.DryRun {
+		code := 0
+		for _, plan := range plans {
+			code = max(code, dryRun(opts, newApplier(opts, cfg), plan, stdout, stderr))
+		}
+		if err := printRootSteps(stdout, opts.Output, root, steps); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return code
+	}
+
+	if opts.CheckSpace {
+		if err := checkSpace(root, plans, stderr); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
 			return 1
 		}
-	} else if err := scaffold.NewApplier().Apply(plan, false); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
+	}
+	if err := ensureBaseDir(opts, newApplier(opts, cfg), filepath.Dir(root), stderr); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
 		return 1
 	}
+	// Every project is reviewed before any is written, so declining one
+	// leaves the monorepo untouched.
+	for _, plan := range plans {
+		if err := reviewOverwrites(opts, newApplier(opts, cfg), plan, stderr); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	created := make([][]string, len(plans))
+	for i, plan := range plans {
+		files, err := applyPlan(plan, newApplier(opts, cfg), &timings, stdout, stderr)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return applyExitCode(err)
+		}
+		created[i] = files
+	}
+
+	gitStart := time.Now()
+	git := setupGit(steps, gitBranch(opts), opts.Remote, stderr)
+	timings.Git = time.Since(gitStart)
+
+	saveConfig(opts.ConfigPath, stderr, func(saved *config.Config) error {
+		if len(requests) > 0 {
+			saved.DefaultLanguage = requests[0].Language
+			saved.DefaultFramework = requests[0].Framework
+		}
+		saved.DefaultDir = firstNonEmpty(opts.Dir, saved.DefaultDir)
+		saved.Pinned = cfg.Pinned
+		for _, request := range requests {
+			saved.RememberName(request.Name)
+			saved.RememberFramework(request.Language, request.Framework)
+		}
+		return nil
+	})
+
+	for i, request := range requests {
+		printSuccess(stdout, request, plans[i], created[i], gitResult{}, setupSummary{}, Timings{})
+	}
+	printMonorepoSuccess(stdout, root, len(requests), git, timings)
+	return 0
+}
 
-	gitOk := gitInit(plan.ProjectDir)
+func printWarnings(w io.Writer, plan domain.Plan) {
+	for _, warning := range plan.Warnings {
+		_, _ = fmt.Fprintln(w, "warning:", warning)
+	}
+}
 
-	if err := config.Save(opts.ConfigPath, config.Config{
-		DefaultLanguage:  request.Language,
-		DefaultFramework: request.Framework,
-		DefaultDir:       request.Dir,
-	}); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "config save error:", err)
+// transitionSpeed picks the wizard's transition speed: the flag, then
+// NO_ANIMATION, then the config.
+func transitionSpeed(opts flags.Options, cfg config.Config) string {
+	if opts.Transitions == "" && ui.AnimationDisabled() {
+		return "off"
 	}
+	return firstNonEmpty(opts.Transitions, cfg.Transitions)
+}
+
+// exitExists is returned when the project, or a file in it, already exists.
+const exitExists = 3
 
-	printSuccess(request, plan, gitOk)
+// newApplier configures an applier from flags and the config's ignore list.
+func newApplier(opts flags.Options, cfg config.Config) *scaffold.Applier {
+	applier := scaffold.NewApplier()
+	applier.Force = opts.Force
+	applier.SkipExisting = opts.SkipExisting
+	applier.Ignore = scaffold.DefaultApplyIgnore
+	if cfg.ApplyIgnore != nil {
+		applier.Ignore = cfg.ApplyIgnore
+	}
+	// config.Load has already rejected malformed permissions.
+	applier.FileMode, applier.DirMode, _ = cfg.Permissions()
+	applier.AllowSymlinkedDirs = cfg.AllowSymlinkedDirs
+	applier.DurableWrites = cfg.DurableWrites
+	applier.Atomic = cfg.AtomicWrites
+	return applier
+}
+
+// ensureBaseDir makes sure the base directory projects are created in
+// exists. A missing one is created unless --create-dir=false is set; outside
+// --no-tui the user is asked first.
+func ensureBaseDir(opts flags.Options, applier *scaffold.Applier, dir string, stderr io.Writer) error {
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return apperrors.NewValidationError("dir", dir+" is not a directory")
+		}
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if opts.NoCreateDir {
+		return apperrors.NewValidationError("dir", dir+" does not exist (--create-dir=false)")
+	}
+	if !opts.NoTUI && !confirmCreateDir(dir, stderr) {
+		return apperrors.NewValidationError("dir", dir+" does not exist and was not created")
+	}
+	if err := applier.MkdirAll(dir); err != nil {
+		return apperrors.NewScaffoldError("create base dir", err)
+	}
+	return nil
+}
+
+// confirmCreateDir asks on the terminal whether to create a missing base
+// directory, defaulting to yes. Without a terminal it answers yes. It is a
+// variable so tests can answer without one.
+var confirmCreateDir = func(dir string, stderr io.Writer) bool {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return true
+	}
+	_, _ = fmt.Fprintf(stderr, "%s does not exist. Create it? [Y/n] ", dir)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true
+	}
+	return false
+}
+
+// applyPlan writes a plan to disk, or hands it to its external generator,
+// and returns the files Apply wrote; a generator's files are not listed.
+// The time spent is added to timings.
+func applyPlan(plan domain.Plan, applier *scaffold.Applier, timings *Timings, stdout io.Writer, stderr io.Writer) ([]string, error) {
+	start := time.Now()
+	if plan.Generator != "" {
+		// The generator creates the project dir itself, but not the
+		// language folder above it.
+		if err := applier.MkdirAll(filepath.Dir(plan.ProjectDir)); err != nil {
+			return nil, apperrors.NewScaffoldError("create project parent dir", err)
+		}
+		timings.Files += time.Since(start)
+
+		start = time.Now()
+		err := runGenerator(plan.Generator, plan.ProjectDir, stdout, stderr)
+		timings.Generator += time.Since(start)
+		if name, _, cmdErr := generatorCommand(plan.Generator, plan.ProjectDir); cmdErr == nil {
+			timings.GeneratorTool = name
+		}
+		return nil, err
+	}
+	created, err := applier.Apply(plan, false)
+	timings.Files += time.Since(start)
+	return created, err
+}
+
+func applyExitCode(err error) int {
+	if errors.Is(err, apperrors.ErrProjectExists) {
+		return exitExists
+	}
+	return 1
+}
+
+// dryRun prints the plan along with any files Apply would refuse to
+// overwrite. Conflicts fail the run with exitExists unless --force is set, so
+// CI can use a dry run as a preflight check.
+// --skip-existing also succeeds, since Apply would leave those files alone.
+func dryRun(opts flags.Options, applier *scaffold.Applier, plan domain.Plan, stdout io.Writer, stderr io.Writer) int {
+	conflicts, err := applier.Conflicts(plan)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, path := range conflicts {
+		_, _ = fmt.Fprintln(stderr, "warning: already exists:", path)
+	}
+
+	if opts.Output == "json" {
+		if err := printPlanJSON(stdout, plan, conflicts); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+	} else {
+		printPlan(stdout, plan)
+	}
+
+	if len(conflicts) > 0 && !opts.Force && !opts.SkipExisting {
+		return exitExists
+	}
 	return 0
 }
 
-func buildRequest(opts flags.Options, cfg config.Config) (scaffold.Request, error) {
+// gitBranch is the branch a new repository starts on.
+func gitBranch(opts flags.Options) string {
+	return firstNonEmpty(opts.Branch, defaultBranch)
+}
+
+// postSteps returns the commands run in dir once its files are written:
+// git init on branch, then adding remote as origin when it is set. Git
+// older than 2.28 has no "init -b", so init falls back to pointing HEAD at
+// the branch after a plain init. Dry runs print the steps and setupGit
+// runs them, so what is printed is what runs.
+func postSteps(dir string, branch string, remote string) []domain.CommandSpec {
+	steps := []domain.CommandSpec{{
+		Kind: domain.CommandGitInit,
+		Name: "git",
+		Args: []string{"init", "-b", branch},
+		Dir:  dir,
+		Fallback: []domain.CommandSpec{
+			{Name: "git", Args: []string{"init"}, Dir: dir},
+			{Name: "git", Args: []string{"symbolic-ref", "HEAD", "refs/heads/" + branch}, Dir: dir},
+		},
+	}}
+	if remote != "" {
+		steps = append(steps, domain.CommandSpec{
+			Kind: domain.CommandGitRemote,
+			Name: "git",
+			Args: []string{"remote", "add", "origin", remote},
+			Dir:  dir,
+		})
+	}
+	return steps
+}
+
+// setupGit runs the post-create steps postSteps built for branch and
+// remote. When git init fails, a warning is printed and the steps after it
+// are skipped.
+func setupGit(steps []domain.CommandSpec, branch string, remote string, stderr io.Writer) gitResult {
+	git := gitResult{}
+	for _, step := range steps {
+		err := runStep(step)
+		switch step.Kind {
+		case domain.CommandGitInit:
+			if err != nil {
+				_, _ = fmt.Fprintln(stderr, "warning: git init error:", err)
+				return git
+			}
+			git.initialized, git.branch = true, branch
+		case domain.CommandGitRemote:
+			if err != nil {
+				_, _ = fmt.Fprintln(stderr, "git remote error:", err)
+			} else {
+				git.remote = remote
+			}
+		default:
+			if err != nil {
+				_, _ = fmt.Fprintf(stderr, "%s: %v\n", step, err)
+			}
+		}
+	}
+	return git
+}
+
+// runStep runs a post-create command, then its fallback if it fails.
+// Only git commands are planned, and they go through runGit.
+func runStep(step domain.CommandSpec) error {
+	if step.Name != "git" {
+		return fmt.Errorf("unsupported post-create command: %s", step)
+	}
+	err := runGit(step.Dir, step.Args...)
+	if err == nil || len(step.Fallback) == 0 {
+		return err
+	}
+	for _, fallback := range step.Fallback {
+		if err := runStep(fallback); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runProfile(opts flags.Options, stdout io.Writer, stderr io.Writer) int {
+	if opts.ProfileExport != "" && opts.ProfileImport != "" {
+		_, _ = fmt.Fprintln(stderr, "--profile-export and --profile-import cannot be used together")
+		return 2
+	}
+
+	if opts.ProfileExport != "" {
+		if err := config.Export(opts.ConfigPath, opts.ProfileExport); err != nil {
+			_, _ = fmt.Fprintln(stderr, "profile export error:", err)
+			return 1
+		}
+		_, _ = fmt.Fprintln(stdout, "Config exported to", opts.ProfileExport)
+		return 0
+	}
+
+	if err := config.Import(opts.ProfileImport, opts.ConfigPath); err != nil {
+		_, _ = fmt.Fprintln(stderr, "profile import error:", err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(stdout, "Config imported from", opts.ProfileImport)
+	return 0
+}
+
+// buildRequest resolves the scaffold request from flags, config defaults and,
+// when needed, the wizard. Wizard preferences such as pins are written to cfg.
+// It also returns how many wizard steps were answered, 0 without the wizard.
+func buildRequest(opts flags.Options, cfg *config.Config) (scaffold.Request, int, error) {
 	language := firstNonEmpty(opts.Language, cfg.DefaultLanguage)
-	framework := firstNonEmpty(opts.Framework, cfg.DefaultFramework)
+	framework, askFramework := frameworkDefault(opts, *cfg)
+	if askFramework {
+		if opts.NoTUI {
+			return scaffold.Request{}, 0, apperrors.NewValidationError("framework", "--framework ? needs the wizard and cannot be combined with --no-tui")
+		}
+		opts.Framework = ""
+	}
+	if err := validateModule(opts.Module); err != nil {
+		return scaffold.Request{}, 0, err
+	}
 	name := opts.Name
-	dir := firstNonEmpty(opts.Dir, cfg.DefaultDir)
+	libraries := splitLibraries(opts.Libraries)
+	dir, err := expandDir(firstNonEmpty(opts.Dir, cfg.DefaultDir))
+	if err != nil {
+		return scaffold.Request{}, 0, err
+	}
+	base := baseRequest(opts, *cfg, dir)
+
+	disabled := disabledOptions(*cfg)
+	if !opts.IgnoreDisabled {
+		if err := checkDisabled(opts, language, framework, disabled); err != nil {
+			return scaffold.Request{}, 0, err
+		}
+	}
 
 	if opts.NoTUI {
 		if name == "" {
-			return scaffold.Request{}, errors.New("name is required when --no-tui is set")
+			return scaffold.Request{}, 0, errors.New("name is required when --no-tui is set")
 		}
-		return scaffold.Request{
-			Language:  language,
-			Framework: framework,
-			Name:      name,
-			Dir:       dir,
-			DryRun:    opts.DryRun,
-		}, nil
+		request, err := selectRequest(base, *cfg, opts.Module, ui.Project{Language: language, Framework: framework, Name: name, Libraries: libraries})
+		return request, 0, err
 	}
 
-	if name == "" || opts.Language == "" || opts.Framework == "" {
-		wizard := ui.NewWizard(language, framework)
-		program := tea.NewProgram(wizard, tea.WithAltScreen())
-		finalModel, err := program.Run()
+	// With the language, framework and name all given there is nothing to
+	// ask, so the project is created without the wizard even without
+	// --no-tui.
+	if usesWizard(opts) {
+		result, err := startWizard(opts, language, framework, opts.Dir, ui.Options{
+			Frameworks:       scaffold.ListOptions(scaffold.Frameworks, disabled),
+			DefaultLanguage:  language,
+			DefaultFramework: framework,
+			AskFramework:     askFramework,
+			Pinned:           cfg.Pinned,
+			Preview:          previewPlan(base, *cfg, opts.Module),
+			ASCII:            opts.ASCII || !ui.SupportsBlockGlyphs(),
+			Transitions:      transitionSpeed(opts, *cfg),
+			Accessible:       opts.Accessible || ui.AccessibleRequested(),
+			SkipSplash:       cfg.SkipSplash,
+			ConfigFiles:      config.Paths(opts.ConfigPath),
+			Clipboard:        wizardClipboard(opts),
+			NameHistory:      cfg.NameHistory,
+			LastFrameworks:   cfg.LastFrameworkByLang,
+			DefaultLibraries: libraries,
+		})
 		if err != nil {
-			return scaffold.Request{}, err
+			return scaffold.Request{}, 0, err
 		}
 
-		result, err := ui.ResultFromModel(finalModel)
-		if err != nil {
-			return scaffold.Request{}, err
-		}
+		cfg.Pinned = result.Pinned
 
 		if name == "" {
 			name = result.Name
@@ -112,30 +713,148 @@ func buildRequest(opts flags.Options, cfg config.Config) (scaffold.Request, erro
 		if opts.Framework == "" {
 			framework = result.Framework
 		}
-		libs := result.Libraries
-		return scaffold.Request{
-			Language:  language,
-			Framework: framework,
-			Name:      name,
-			Dir:       dir,
-			DryRun:    opts.DryRun,
-			Libraries: libs,
-		}, nil
+		request, err := selectRequest(base, *cfg, opts.Module, ui.Project{Language: language, Framework: framework, Name: name, Libraries: result.Libraries})
+		return request, result.Steps, err
 	}
 
 	name = strings.TrimSpace(name)
 	if name == "" {
-		return scaffold.Request{}, errors.New("project name is required")
+		return scaffold.Request{}, 0, errors.New("project name is required")
 	}
 
+	request, err := selectRequest(base, *cfg, opts.Module, ui.Project{Language: language, Framework: framework, Name: name, Libraries: libraries})
+	return request, 0, err
+}
+
+// baseRequest holds the request fields taken from flags and config rather
+// than from a selection, with dir as the base directory.
+func baseRequest(opts flags.Options, cfg config.Config, dir string) scaffold.Request {
 	return scaffold.Request{
-		Language:  language,
-		Framework: framework,
-		Name:      name,
-		Dir:       dir,
-		DryRun:    opts.DryRun,
-		Libraries: nil,
-	}, nil
+		Dir:             dir,
+		DryRun:          opts.DryRun,
+		Flatten:         opts.Flatten,
+		Layout:          firstNonEmpty(opts.Layout, cfg.Layout),
+		Port:            opts.Port,
+		OpenAPI:         opts.OpenAPI,
+		NPMScope:        cfg.NPMScope,
+		GeneratedHeader: cfg.GeneratedHeader,
+		Seed:            opts.Seed,
+		CollapseDupes:   opts.CollapseDupes,
+	}
+}
+
+// selectRequest completes base with one selection: its option, name and
+// libraries, cfg's port for the option when base has none, and the Go
+// module path for module. Final requests and the wizard's preview both go
+// through it, so the confirm screen plans what will be created.
+func selectRequest(base scaffold.Request, cfg config.Config, module string, selection ui.Project) (scaffold.Request, error) {
+	req := base
+	req.Language = selection.Language
+	req.Framework = selection.Framework
+	req.Name = selection.Name
+	req.Libraries = selection.Libraries
+	req.Port = cmp.Or(req.Port, cfg.PortFor(selection.Language, selection.Framework))
+	if err := resolveModule(&req, module); err != nil {
+		return scaffold.Request{}, err
+	}
+	return req, nil
+}
+
+// splitLibraries splits the --libraries list, dropping blanks.
+func splitLibraries(value string) []string {
+	var libraries []string
+	for _, lib := range strings.Split(value, ",") {
+		if lib = strings.TrimSpace(lib); lib != "" {
+			libraries = append(libraries, lib)
+		}
+	}
+	return libraries
+}
+
+// wizardClipboard is the clipboard the wizard suggests a name from, or nil
+// with --no-clipboard.
+func wizardClipboard(opts flags.Options) ui.Clipboard {
+	if opts.NoClipboard {
+		return nil
+	}
+	return ui.SystemClipboard{}
+}
+
+// previewPlan plans a wizard selection as selectRequest completes it from
+// base, so the confirm screen can summarise the files it would write.
+func previewPlan(base scaffold.Request, cfg config.Config, module string) func(ui.Result) (domain.Plan, error) {
+	return func(result ui.Result) (domain.Plan, error) {
+		req, err := selectRequest(base, cfg, module, ui.Project{
+			Language:  result.Language,
+			Framework: result.Framework,
+			Name:      result.Name,
+			Libraries: result.Libraries,
+		})
+		if err != nil {
+			return domain.Plan{}, err
+		}
+		return scaffold.DefaultPlanner().Plan(req)
+	}
+}
+
+// runWizard runs the interactive wizard to completion. It is a variable so
+// tests can supply a result without a terminal.
+var runWizard = func(opts ui.Options) (ui.Result, error) {
+	if dumbTerminal() {
+		return ui.Result{}, errDumbTerminal
+	}
+	program := tea.NewProgram(ui.NewWizard(opts), tea.WithAltScreen())
+	finalModel, err := program.Run()
+	if err != nil {
+		return ui.Result{}, err
+	}
+	return ui.ResultFromModel(finalModel)
+}
+
+func disabledOptions(cfg config.Config) scaffold.Disabled {
+	return scaffold.Disabled{
+		Languages:  cfg.Disabled.Languages,
+		Frameworks: cfg.Disabled.Frameworks,
+	}
+}
+
+// frameworkDefault returns the framework to preselect, and whether
+// --framework ? asked for the wizard to offer every framework instead of
+// the configured default.
+func frameworkDefault(opts flags.Options, cfg config.Config) (string, bool) {
+	if opts.Framework == flags.AskFramework {
+		return "", true
+	}
+	return firstNonEmpty(opts.Framework, cfg.DefaultFramework), false
+}
+
+// checkDisabled rejects a language or framework passed explicitly via flags
+// when the config disables it.
+func checkDisabled(opts flags.Options, language string, framework string, disabled scaffold.Disabled) error {
+	if opts.Language != "" && disabled.Has(language, "") {
+		return apperrors.NewValidationError("lang", fmt.Sprintf("%s is disabled by config (use --ignore-disabled to override)", language))
+	}
+	if opts.Framework != "" && disabled.Has(language, framework) {
+		return apperrors.NewValidationError("framework", fmt.Sprintf("%s/%s is disabled by config (use --ignore-disabled to override)", language, framework))
+	}
+	return nil
+}
+
+// saveConfig remembers this run's choices via config.Update. The project is
+// already created by then, so a failed save is a warning that leaves the
+// exit code alone; read-only homes would otherwise hit it on every run.
+func saveConfig(configPath string, stderr io.Writer, change func(saved *config.Config) error) {
+	err := config.Update(configPath, change)
+	if err == nil {
+		return
+	}
+	paths := config.Paths(configPath)
+	path := paths[len(paths)-1]
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	_, _ = fmt.Fprintf(stderr, "warning: settings not saved to %s: %v; pass --config with a writable file to keep them\n", path, err)
 }
 
 func firstNonEmpty(values ...string) string {
@@ -149,18 +868,140 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
-func printPlan(plan domain.Plan) {
-	_, _ = fmt.Fprintln(os.Stdout, "Plan:")
-	_, _ = fmt.Fprintln(os.Stdout, "Project:", plan.ProjectDir)
+func printDuration(w io.Writer, phase string, elapsed time.Duration) {
+	_, _ = fmt.Fprintf(w, "%s took %s\n", phase, elapsed.Round(time.Microsecond))
+}
+
+// planJSON is the --output json form of a dry run.
+type planJSON struct {
+	ProjectDir string        `json:"projectDir"`
+	Generator  string        `json:"generator,omitempty"`
+	Files      []string      `json:"files"`
+	Conflicts  []string      `json:"conflicts"`
+	PostSteps  []commandJSON `json:"postSteps,omitempty"`
+}
+
+type commandJSON struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+	Dir  string   `json:"dir"`
+}
+
+func commandsJSON(steps []domain.CommandSpec) []commandJSON {
+	var out []commandJSON
+	for _, step := range steps {
+		out = append(out, commandJSON{Name: step.Name, Args: step.Args, Dir: step.Dir})
+	}
+	return out
+}
+
+func newPlanJSON(plan domain.Plan, conflicts []string) planJSON {
+	out := planJSON{
+		ProjectDir: plan.ProjectDir,
+		Generator:  plan.Generator,
+		Files:      make([]string, 0, len(plan.Actions)),
+		Conflicts:  conflicts,
+	}
+	if out.Conflicts == nil {
+		out.Conflicts = []string{}
+	}
+	for _, action := range plan.Actions {
+		out.Files = append(out.Files, action.Path)
+	}
+	out.PostSteps = commandsJSON(plan.PostSteps)
+	return out
+}
+
+func printPlanJSON(w io.Writer, plan domain.Plan, conflicts []string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newPlanJSON(plan, conflicts))
+}
+
+// fileProgress echoes each file Apply handles with a status glyph. Plain
+// mode swaps the glyphs for ASCII on terminals that cannot draw them;
+// colors are dropped automatically when the output is not a terminal.
+type fileProgress struct {
+	w       io.Writer
+	root    string
+	glyphs  [3]string // created, skipped, failed
+	created lipgloss.Style
+	skipped lipgloss.Style
+	failed  lipgloss.Style
+}
+
+func newFileProgress(w io.Writer, root string, plain bool) fileProgress {
+	glyphs := [3]string{"✓", "↷", "✗"}
+	if plain {
+		glyphs = [3]string{"+", "~", "x"}
+	}
+	return fileProgress{
+		w:       w,
+		root:    root,
+		glyphs:  glyphs,
+		created: lipgloss.NewStyle().Foreground(ui.Green),
+		skipped: lipgloss.NewStyle().Foreground(ui.Yellow),
+		failed:  lipgloss.NewStyle().Foreground(ui.Red),
+	}
+}
+
+func (p fileProgress) report(event scaffold.FileEvent) {
+	path := event.Path
+	if rel, err := filepath.Rel(p.root, event.Path); err == nil {
+		path = filepath.ToSlash(rel)
+	}
+	switch event.Status {
+	case scaffold.FileCreated:
+		_, _ = fmt.Fprintln(p.w, p.created.Render(p.glyphs[0]+" "+path))
+	case scaffold.FileSkipped:
+		_, _ = fmt.Fprintln(p.w, p.skipped.Render(fmt.Sprintf("%s %s skipped (%s)", p.glyphs[1], path, event.Reason)))
+	case scaffold.FileFailed:
+		_, _ = fmt.Fprintln(p.w, p.failed.Render(fmt.Sprintf("%s %s: %v", p.glyphs[2], path, event.Err)))
+	}
+}
+
+func printPlan(w io.Writer, plan domain.Plan) {
+	_, _ = fmt.Fprintln(w, "Plan:")
+	_, _ = fmt.Fprintln(w, "Project:", plan.ProjectDir)
 	if plan.Generator != "" {
-		_, _ = fmt.Fprintln(os.Stdout, "Generator:", plan.Generator)
+		_, _ = fmt.Fprintln(w, "Generator:", plan.Generator)
+		if name, args, err := generatorCommand(plan.Generator, plan.ProjectDir); err == nil {
+			_, _ = fmt.Fprintln(w, "Command:", name, strings.Join(args, " "))
+		}
 	}
 	for _, action := range plan.Actions {
-		_, _ = fmt.Fprintln(os.Stdout, "-", action.Path)
+		_, _ = fmt.Fprintln(w, "-", action.Path)
+	}
+	printPostSteps(w, plan.PostSteps)
+}
+
+func printPostSteps(w io.Writer, steps []domain.CommandSpec) {
+	if len(steps) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Post-create steps:")
+	for _, step := range steps {
+		_, _ = fmt.Fprintf(w, "- %s (in %s)\n", step, step.Dir)
 	}
 }
 
-func printSuccess(request scaffold.Request, plan domain.Plan, gitOk bool) {
+// printRootSteps prints the steps a monorepo dry run would take at its
+// root once every project's plan is written.
+func printRootSteps(w io.Writer, output string, root string, steps []domain.CommandSpec) error {
+	if output != "json" {
+		_, _ = fmt.Fprintln(w, "Monorepo:", root)
+		printPostSteps(w, steps)
+		return nil
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Root      string        `json:"root"`
+		PostSteps []commandJSON `json:"postSteps"`
+	}{Root: root, PostSteps: commandsJSON(steps)})
+}
+
+func printSuccess(w io.Writer, request scaffold.Request, plan domain.Plan, created []string, git gitResult, setup setupSummary, timings Timings) {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.Green)
 	labelStyle := lipgloss.NewStyle().Foreground(ui.Muted)
 	valueStyle := lipgloss.NewStyle().Foreground(ui.Text)
@@ -180,66 +1021,284 @@ func printSuccess(request scaffold.Request, plan domain.Plan, gitOk bool) {
 		lines = append(lines, labelStyle.Render("  Libraries   ")+valueStyle.Render(strings.Join(request.Libraries, ", ")))
 	}
 
-	fileCount := len(plan.Actions)
+	// Count what Apply wrote: --skip-existing and identical files leave
+	// some planned files untouched.
+	fileCount := len(created)
 	noun := "files"
 	if fileCount == 1 {
 		noun = "file"
 	}
-	lines = append(lines, labelStyle.Render("  Files       ")+valueStyle.Render(fmt.Sprintf("%d %s created", fileCount, noun)))
+	files := fmt.Sprintf("%d %s created", fileCount, noun)
+	if skipped := len(plan.Actions) - fileCount; skipped > 0 {
+		files += fmt.Sprintf(", %d left as they were", skipped)
+	}
+	lines = append(lines, labelStyle.Render("  Files       ")+valueStyle.Render(files))
 
-	if gitOk {
-		lines = append(lines, labelStyle.Render("  Git         ")+valueStyle.Render("initialized"))
+	if git.initialized {
+		status := "initialized"
+		if git.branch != "" {
+			status += " on " + git.branch
+		}
+		lines = append(lines, labelStyle.Render("  Git         ")+valueStyle.Render(status))
+	}
+	if git.remote != "" {
+		lines = append(lines, labelStyle.Render("  Remote      ")+valueStyle.Render("origin → "+git.remote))
+	}
+	if setup.WizardSteps > 0 {
+		lines = append(lines, labelStyle.Render("  Wizard      ")+valueStyle.Render(fmt.Sprintf("%d steps completed", setup.WizardSteps)))
+	}
+	if len(setup.Defaults) > 0 {
+		lines = append(lines, labelStyle.Render("  Defaults    ")+valueStyle.Render(strings.Join(setup.Defaults, ", ")))
 	}
 
 	lines = append(lines, "")
 	lines = append(lines, hintStyle.Render("  Next steps:"))
 	lines = append(lines, cmdStyle.Render("    cd "+plan.ProjectDir))
 
-	nextCmd := nextStepCommand(request.Language)
-	if nextCmd != "" {
-		lines = append(lines, cmdStyle.Render("    "+nextCmd))
+	for _, step := range scaffold.NextSteps(request.Language, request.Framework, plan.Port) {
+		lines = append(lines, cmdStyle.Render("    "+step))
+	}
+	if plan.Port != 0 {
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("    The server listens on http://localhost:%d", plan.Port)))
 	}
 
 	lines = append(lines, "")
+	// Monorepo projects leave timings zero; the monorepo summary has them.
+	if timings.Total() > 0 {
+		lines = append(lines, labelStyle.Render("  "+timings.Summary()), "")
+	}
 
-	_, _ = fmt.Fprintln(os.Stdout, strings.Join(lines, "\n"))
+	_, _ = fmt.Fprintln(w, strings.Join(lines, "\n"))
 }
 
-func nextStepCommand(language string) string {
-	switch strings.ToLower(language) {
-	case "go":
-		return "go mod tidy"
-	case "node.js":
-		return "npm install"
-	case "bun":
-		return "bun install"
-	case "python":
-		return "pip install -r requirements.txt"
-	default:
+func printMonorepoSuccess(w io.Writer, root string, projects int, git gitResult, timings Timings) {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.Green)
+	labelStyle := lipgloss.NewStyle().Foreground(ui.Muted)
+	valueStyle := lipgloss.NewStyle().Foreground(ui.Text)
+
+	noun := "projects"
+	if projects == 1 {
+		noun = "project"
+	}
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("  Monorepo created with %d %s", projects, noun)),
+		"",
+		labelStyle.Render("  Root        ") + valueStyle.Render(root),
+	}
+	if git.initialized {
+		status := "initialized"
+		if git.branch != "" {
+			status += " on " + git.branch
+		}
+		lines = append(lines, labelStyle.Render("  Git         ")+valueStyle.Render(status))
+	}
+	if git.remote != "" {
+		lines = append(lines, labelStyle.Render("  Remote      ")+valueStyle.Render("origin → "+git.remote))
+	}
+	lines = append(lines, "", labelStyle.Render("  "+timings.Summary()), "")
+
+	_, _ = fmt.Fprintln(w, strings.Join(lines, "\n"))
+}
+
+// gitResult records what the post-scaffold git setup managed to do.
+type gitResult struct {
+	initialized bool
+	branch      string
+	remote      string
+}
+
+// validateRemote does a minimal sanity check on a remote URL: it must be a
+// URL with a known git scheme or an scp-like "user@host:path" address.
+func validateRemote(remote string) error {
+	invalid := apperrors.NewValidationError("remote", fmt.Sprintf("%q is not a git remote URL", remote))
+	if strings.ContainsAny(remote, " \t\n") {
+		return invalid
+	}
+	if scheme, rest, ok := strings.Cut(remote, "://"); ok {
+		switch scheme {
+		case "https", "http", "ssh", "git", "file":
+			if rest != "" {
+				return nil
+			}
+		}
+		return invalid
+	}
+	if host, path, ok := strings.Cut(remote, ":"); ok && strings.Contains(host, "@") && path != "" {
+		return nil
+	}
+	return invalid
+}
+
+// validateBranch applies the rules of "git check-ref-format --branch" to
+// an initial branch name, so a bad --branch fails before anything is
+// written instead of leaving a repository on git's default branch.
+func validateBranch(branch string) error {
+	invalid := apperrors.NewValidationError("branch", fmt.Sprintf("%q is not a valid git branch name", branch))
+	if branch == "@" || strings.HasPrefix(branch, "-") || strings.HasSuffix(branch, ".") ||
+		strings.Contains(branch, "..") || strings.Contains(branch, "@{") {
+		return invalid
+	}
+	for _, r := range branch {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return invalid
+		}
+	}
+	for _, part := range strings.Split(branch, "/") {
+		if part == "" || strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return invalid
+		}
+	}
+	return nil
+}
+
+// resolveModule fills in the Go module path of a Go request: module when
+// --module is set, otherwise a prefix such as "github.com/acme" taken from
+// the origin remote of the repository around the base dir, or around its
+// nearest existing parent when it is not created yet. Without one the
+// planner falls back to the slug.
+func resolveModule(request *scaffold.Request, module string) error {
+	if !strings.EqualFold(request.Language, "go") {
+		return nil
+	}
+	if module = strings.TrimSpace(module); module != "" {
+		if err := validateModule(module); err != nil {
+			return err
+		}
+		request.Module = module
+		return nil
+	}
+	dir, err := existingAncestor(request.Dir)
+	if err != nil {
+		return nil
+	}
+	if remote, err := gitOutput(dir, "remote", "get-url", "origin"); err == nil {
+		request.ModulePrefix = modulePrefix(remote)
+	}
+	return nil
+}
+
+// validateModule rejects a --module value that cannot be a Go module path.
+// An empty value is fine.
+func validateModule(module string) error {
+	module = strings.TrimSpace(module)
+	if strings.ContainsAny(module, " \t\n\\") || strings.HasPrefix(module, "/") || strings.HasSuffix(module, "/") {
+		return apperrors.NewValidationError("module", fmt.Sprintf("%q is not a Go module path", module))
+	}
+	return nil
+}
+
+// modulePrefix turns a git remote URL into the module path of its owner,
+// e.g. "github.com/acme" for "git@github.com:acme/tools.git" or
+// "https://gitlab.com/acme/platform/tools". It returns "" for remotes
+// without a host and owner, such as local paths.
+func modulePrefix(remote string) string {
+	remote = strings.TrimSpace(remote)
+	var host, repoPath string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Scheme == "file" {
+			return ""
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		// scp-like "user@host:owner/repo"
+		address, path, ok := strings.Cut(remote, ":")
+		if !ok || strings.Contains(address, "/") {
+			return ""
+		}
+		_, host, _ = strings.Cut(address, "@")
+		if host == "" {
+			host = address
+		}
+		repoPath = path
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	owner, _, ok := strings.Cut(repoPath, "/")
+	if host == "" || !ok || owner == "" {
 		return ""
 	}
+	// Keep nested groups, dropping only the repository name.
+	return strings.ToLower(host) + "/" + repoPath[:strings.LastIndex(repoPath, "/")]
 }
 
-func gitInit(projectDir string) bool {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = projectDir
+// defaultBranch is the initial branch used when --branch is not set.
+const defaultBranch = "main"
+
+// verifyProject builds a freshly created Go project to catch templates
+// that no longer compile. It returns 1 if the build fails, and 0 when it
+// passes or is skipped because the project is not Go or Go is not installed.
+func verifyProject(language string, dir string, stderr io.Writer) int {
+	if !strings.EqualFold(language, "go") {
+		_, _ = fmt.Fprintf(stderr, "verify: skipped, only Go projects are built (got %s)\n", language)
+		return 0
+	}
+	if _, err := lookPath("go"); err != nil {
+		_, _ = fmt.Fprintln(stderr, "verify: skipped, go is not installed")
+		return 0
+	}
+	if out, err := goBuild(dir); err != nil {
+		_, _ = fmt.Fprintf(stderr, "verify: go build ./... failed: %v\n%s", err, out)
+		return 1
+	}
+	_, _ = fmt.Fprintln(stderr, "verify: go build ./... ok")
+	return 0
+}
+
+// lookPath and goBuild are variables so tests can fake a missing or
+// failing Go toolchain.
+var lookPath = exec.LookPath
+
+var goBuild = func(dir string) ([]byte, error) {
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// runGit runs a git subcommand in dir, discarding its output. It is a variable
+// so tests can record invocations without a git binary.
+var runGit = func(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
 	cmd.Stdout = nil
 	cmd.Stderr = nil
-	return cmd.Run() == nil
+	return cmd.Run()
+}
+
+// gitOutput runs a git subcommand in dir and returns its trimmed stdout. It
+// is a variable so tests can fake repository state.
+var gitOutput = func(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
 }
 
-func runGenerator(generator string, projectDir string) error {
+// generatorCommand returns the command a generator framework runs to
+// create projectDir. Dry runs print it, so it is the only place the
+// generator's target is decided.
+func generatorCommand(generator string, projectDir string) (string, []string, error) {
 	switch generator {
 	case "composer-laravel":
-		return runCommand("composer", []string{"create-project", "laravel/laravel", projectDir})
+		return "composer", []string{"create-project", "laravel/laravel", projectDir}, nil
 	default:
-		return fmt.Errorf("unknown generator: %s", generator)
+		return "", nil, fmt.Errorf("unknown generator: %s", generator)
+	}
+}
+
+func runGenerator(generator string, projectDir string, stdout io.Writer, stderr io.Writer) error {
+	name, args, err := generatorCommand(generator, projectDir)
+	if err != nil {
+		return err
 	}
+	return runCommand(name, args, stdout, stderr)
 }
 
-func runCommand(name string, args []string) error {
+// runCommand runs an external command with its output passed through. It
+// is a variable so tests can record generator runs.
+var runCommand = func(name string, args []string, stdout io.Writer, stderr io.Writer) error {
 	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	return cmd.Run()
 }
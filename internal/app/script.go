@@ -0,0 +1,118 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"project-initiator/internal/domain"
+)
+
+// emitScript writes the plan as a shell script to path, or to stdout when
+// path is "-", so it can be reviewed and applied by hand.
+func emitScript(path string, plan domain.Plan, stdout io.Writer, stderr io.Writer) int {
+	var buf bytes.Buffer
+	if err := writeScript(&buf, plan); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if path == "-" {
+		_, _ = stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		_, _ = fmt.Fprintln(stderr, "emit script:", err)
+		return 1
+	}
+	return 0
+}
+
+// writeScript writes a POSIX shell script that creates the plan's
+// directories and files, or runs its generator. File contents go in quoted
+// heredocs so the shell expands nothing in them.
+func writeScript(w io.Writer, plan domain.Plan) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Creates %s as planned by project-initiator.\n", plan.ProjectDir)
+	b.WriteString("set -eu\n\n")
+
+	if plan.Generator != "" {
+		name, args, err := generatorCommand(plan.Generator, plan.ProjectDir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "mkdir -p %s\n", shellQuote(filepath.Dir(plan.ProjectDir)))
+		b.WriteString(shellQuote(name))
+		for _, arg := range args {
+			b.WriteString(" " + shellQuote(arg))
+		}
+		b.WriteString("\n")
+		_, err = io.WriteString(w, b.String())
+		return err
+	}
+
+	dirs := []string{plan.ProjectDir}
+	seen := map[string]bool{plan.ProjectDir: true}
+	for _, action := range plan.Actions {
+		if dir := filepath.Dir(action.Path); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range dirs {
+		fmt.Fprintf(&b, "mkdir -p %s\n", shellQuote(dir))
+	}
+
+	for _, action := range plan.Actions {
+		b.WriteString("\n")
+		writeFileCommand(&b, action)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFileCommand writes the command that creates one file with exactly
+// its planned content. A heredoc always ends in a newline, so content
+// without a trailing one is written with printf instead.
+func writeFileCommand(b *strings.Builder, action domain.Action) {
+	path := shellQuote(action.Path)
+	switch {
+	case action.Content == "":
+		fmt.Fprintf(b, ": > %s\n", path)
+	case strings.HasSuffix(action.Content, "\n"):
+		delim := heredocDelimiter(action.Content)
+		fmt.Fprintf(b, "cat > %s <<'%s'\n%s%s\n", path, delim, action.Content, delim)
+	default:
+		fmt.Fprintf(b, "printf '%%s' %s > %s\n", shellQuote(action.Content), path)
+	}
+	if action.Mode != 0 {
+		fmt.Fprintf(b, "chmod %o %s\n", action.Mode.Perm(), path)
+	}
+}
+
+// heredocDelimiter picks a terminator that no line of content matches.
+func heredocDelimiter(content string) string {
+	lines := strings.Split(content, "\n")
+	delim := "PROJECT_INITIATOR_EOF"
+	for i := 1; ; i++ {
+		clash := false
+		for _, line := range lines {
+			if line == delim {
+				clash = true
+				break
+			}
+		}
+		if !clash {
+			return delim
+		}
+		delim = fmt.Sprintf("PROJECT_INITIATOR_EOF_%d", i)
+	}
+}
+
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
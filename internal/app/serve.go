@@ -0,0 +1,370 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"project-initiator/internal/config"
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+	"project-initiator/internal/flags"
+	"project-initiator/internal/scaffold"
+)
+
+// serveProtocol is the version of the serve protocol, sent in every
+// response. It changes when a method or field changes incompatibly.
+const serveProtocol = 1
+
+// serveMaxLine caps one request line, so a runaway client cannot make the
+// server buffer without bound.
+const serveMaxLine = 1 << 20
+
+// Error codes in serve responses.
+const (
+	codeParse         = "parse"          // the line is not JSON
+	codeInvalid       = "invalidRequest" // the request has no method
+	codeUnknownMethod = "unknownMethod"
+	codeInvalidParams = "invalidParams" // params are not the method's
+	codeValidation    = "validation"    // the request fails Validate
+	codeExists        = "exists"        // apply would overwrite a file
+	codeFailed        = "failed"        // planning or writing failed
+)
+
+// serveRequest is one line read by serve: a method, its params, and an
+// id the response echoes back.
+type serveRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// serveResponse is one line written by serve. Exactly one of Result and
+// Error is set.
+type serveResponse struct {
+	Protocol int             `json:"protocol"`
+	ID       json.RawMessage `json:"id,omitempty"`
+	Result   any             `json:"result,omitempty"`
+	Error    *serveError     `json:"error,omitempty"`
+}
+
+type serveError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Field names the invalid request field for validation errors.
+	Field string `json:"field,omitempty"`
+}
+
+// serveParams are the params of validateName, plan and apply. Language,
+// framework and dir default to the config's, as they do on the command line.
+type serveParams struct {
+	Language  string   `json:"language"`
+	Framework string   `json:"framework"`
+	Name      string   `json:"name"`
+	Dir       string   `json:"dir"`
+	Libraries []string `json:"libraries"`
+	Port      int      `json:"port"`
+	OpenAPI   bool     `json:"openapi"`
+	// Force lets apply overwrite existing files, as --force does. There
+	// is no one to confirm a diff, so changing a file also needs Yes.
+	Force bool `json:"force"`
+	Yes   bool `json:"yes"`
+}
+
+type serveOption struct {
+	Language    string   `json:"language"`
+	Framework   string   `json:"framework"`
+	Description string   `json:"description"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+	Libraries   []string `json:"libraries"`
+}
+
+type servePlan struct {
+	planJSON
+	Warnings []string `json:"warnings"`
+}
+
+// applyReport is the result of apply.
+type applyReport struct {
+	ProjectDir string   `json:"projectDir"`
+	Generator  string   `json:"generator,omitempty"`
+	Created    []string `json:"created"`
+	Git        bool     `json:"git"`
+	Warnings   []string `json:"warnings"`
+}
+
+// server answers serve requests. It holds the config loaded at start, so
+// every request sees the same defaults.
+type server struct {
+	cfg     config.Config
+	planner *scaffold.Planner
+	// stderr receives generator output, which would corrupt the protocol
+	// on stdout.
+	stderr io.Writer
+}
+
+// runServe handles "serve --stdio": it answers each request line on stdin
+// with one response line on stdout until stdin closes.
+func runServe(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	opts, err := flags.ParseServe(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	cfg, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, "config error:", err)
+		return 2
+	}
+
+	s := server{cfg: cfg, planner: scaffold.DefaultPlanner(), stderr: stderr}
+	if err := s.serve(stdin, stdout); err != nil {
+		_, _ = fmt.Fprintln(stderr, "serve error:", err)
+		return 1
+	}
+	return 0
+}
+
+func (s server) serve(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReaderSize(r, 64<<10)
+	enc := json.NewEncoder(w)
+	for {
+		line, tooLong, readErr := readLine(reader)
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+		var err error
+		if line = bytes.TrimSpace(line); tooLong {
+			err = enc.Encode(errorResponse(nil, &serveError{Code: codeParse, Message: fmt.Sprintf("request line is longer than %d bytes", serveMaxLine)}))
+		} else if len(line) > 0 {
+			err = enc.Encode(s.handle(line))
+		}
+		if err != nil {
+			return err
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// readLine reads up to the next newline. A line over serveMaxLine is
+// read to its end and dropped, with tooLong set, so one bad request
+// does not stop the server. err is io.EOF after the last line.
+func readLine(r *bufio.Reader) (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(bytes.TrimSuffix(line, []byte("\n"))) > serveMaxLine {
+				line, tooLong = nil, true
+			}
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, tooLong, err
+		}
+	}
+}
+
+// handle answers one request line.
+func (s server) handle(line []byte) serveResponse {
+	var req serveRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, &serveError{Code: codeParse, Message: err.Error()})
+	}
+	if req.Method == "" {
+		return errorResponse(req.ID, &serveError{Code: codeInvalid, Message: "request has no method"})
+	}
+
+	var result any
+	var err error
+	switch req.Method {
+	case "listOptions":
+		result, err = s.listOptions()
+	case "validateName":
+		result, err = s.withParams(req.Params, s.validateName)
+	case "plan":
+		result, err = s.withParams(req.Params, s.plan)
+	case "apply":
+		result, err = s.withParams(req.Params, s.apply)
+	default:
+		return errorResponse(req.ID, &serveError{Code: codeUnknownMethod, Message: fmt.Sprintf("unknown method %q", req.Method)})
+	}
+	if err != nil {
+		return errorResponse(req.ID, toServeError(err))
+	}
+	return serveResponse{Protocol: serveProtocol, ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, err *serveError) serveResponse {
+	return serveResponse{Protocol: serveProtocol, ID: id, Error: err}
+}
+
+// withParams decodes params strictly, so a misspelled field is reported
+// rather than ignored, and passes them to method.
+func (s server) withParams(raw json.RawMessage, method func(serveParams) (any, error)) (any, error) {
+	var params serveParams
+	if len(raw) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&params); err != nil {
+			return nil, &serveError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+	return method(params)
+}
+
+func (s server) listOptions() (any, error) {
+	options := scaffold.ListOptions(scaffold.Frameworks, disabledOptions(s.cfg))
+	out := make([]serveOption, 0, len(options))
+	for _, opt := range options {
+		libraries := []string{}
+		for _, library := range scaffold.OfferedLibraries(opt) {
+			libraries = append(libraries, library.Name)
+		}
+		out = append(out, serveOption{
+			Language:    opt.Language,
+			Framework:   opt.Name,
+			Description: opt.Description,
+			Deprecated:  opt.Deprecated,
+			Libraries:   libraries,
+		})
+	}
+	return map[string]any{"options": out}, nil
+}
+
+// validateName checks a request without planning it and returns the
+// directory the project would be created in.
+func (s server) validateName(params serveParams) (any, error) {
+	req, err := s.request(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.planner.Validate(req); err != nil {
+		return nil, err
+	}
+	dir, err := s.planner.ProjectPath(req)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"projectDir": dir}, nil
+}
+
+// plan returns the files a request would write and the existing ones it
+// would overwrite, as --dry-run --output json does.
+func (s server) plan(params serveParams) (any, error) {
+	req, err := s.request(params)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.planner.Plan(req)
+	if err != nil {
+		return nil, err
+	}
+	plan.PostSteps = postSteps(plan.ProjectDir, defaultBranch, "")
+	conflicts, err := s.applier(params).Conflicts(plan)
+	if err != nil {
+		return nil, err
+	}
+	return servePlan{planJSON: newPlanJSON(plan, conflicts), Warnings: planWarnings(plan)}, nil
+}
+
+// apply creates the project with the same checks as a --no-tui run: the
+// configured ignore list and permissions, the symlink check, and refusing
+// to overwrite files unless force is set, or to change them unless yes is
+// set too.
+func (s server) apply(params serveParams) (any, error) {
+	req, err := s.request(params)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.planner.Plan(req)
+	if err != nil {
+		return nil, err
+	}
+	plan.PostSteps = postSteps(plan.ProjectDir, defaultBranch, "")
+	applier := s.applier(params)
+	if err := ensureBaseDir(flags.Options{NoTUI: true}, applier, plan.BaseDir, s.stderr); err != nil {
+		return nil, err
+	}
+	if err := reviewOverwrites(flags.Options{NoTUI: true, Force: params.Force, Yes: params.Yes}, applier, plan, s.stderr); err != nil {
+		return nil, err
+	}
+	created, err := applyPlan(plan, applier, &Timings{}, s.stderr, s.stderr)
+	if err != nil {
+		return nil, err
+	}
+	git := setupGit(plan.PostSteps, defaultBranch, "", s.stderr)
+	if created == nil {
+		created = []string{}
+	}
+	return applyReport{
+		ProjectDir: plan.ProjectDir,
+		Generator:  plan.Generator,
+		Created:    created,
+		Git:        git.initialized,
+		Warnings:   planWarnings(plan),
+	}, nil
+}
+
+// request builds a scaffold request from params and the config the same
+// way a --no-tui run does, so the config's disabled options and the module
+// path resolution apply here too.
+func (s server) request(params serveParams) (scaffold.Request, error) {
+	// buildRequest reports a missing name as a usage error; here it is a
+	// problem with the name param.
+	if strings.TrimSpace(params.Name) == "" {
+		return scaffold.Request{}, apperrors.NewValidationError("name", "project name is required")
+	}
+	cfg := s.cfg
+	req, _, err := buildRequest(flags.Options{
+		NoTUI:     true,
+		Language:  params.Language,
+		Framework: params.Framework,
+		Name:      params.Name,
+		Dir:       params.Dir,
+		Libraries: strings.Join(params.Libraries, ","),
+		Port:      params.Port,
+		OpenAPI:   params.OpenAPI,
+	}, &cfg)
+	if err != nil {
+		return scaffold.Request{}, err
+	}
+	return req, nil
+}
+
+func (s server) applier(params serveParams) *scaffold.Applier {
+	return newApplier(flags.Options{Force: params.Force}, s.cfg)
+}
+
+func planWarnings(plan domain.Plan) []string {
+	if plan.Warnings == nil {
+		return []string{}
+	}
+	return plan.Warnings
+}
+
+// toServeError maps an error to a response error, with the codes matching
+// the CLI's exit codes: validation errors, existing files, other failures.
+func toServeError(err error) *serveError {
+	var serr *serveError
+	if errors.As(err, &serr) {
+		return serr
+	}
+	var verr *apperrors.ValidationError
+	if errors.As(err, &verr) {
+		return &serveError{Code: codeValidation, Message: verr.Message, Field: verr.Field}
+	}
+	if errors.Is(err, apperrors.ErrProjectExists) {
+		return &serveError{Code: codeExists, Message: err.Error()}
+	}
+	return &serveError{Code: codeFailed, Message: err.Error()}
+}
+
+func (e *serveError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"project-initiator/internal/config"
+	"project-initiator/internal/flags"
+	"project-initiator/internal/scaffold"
+)
+
+// setupSummary describes how a request came together, for the success
+// screen: how many wizard steps were answered and which values were filled
+// in from config without being asked for.
+type setupSummary struct {
+	WizardSteps int
+	Defaults    []string
+}
+
+// usesWizard reports whether the request is completed in the wizard; with
+// --no-tui, or the language, framework and name all given, it is not.
+func usesWizard(opts flags.Options) bool {
+	if opts.NoTUI {
+		return false
+	}
+	return opts.Name == "" || opts.Language == "" || opts.Framework == "" || opts.Framework == flags.AskFramework
+}
+
+// summarizeSetup compares the request with the flags and config it was
+// built from. cfg must be the config as loaded, before the run saves the
+// new defaults into it. wizardSteps is the wizard's own count of the steps
+// answered, as buildRequest returns it.
+func summarizeSetup(opts flags.Options, cfg config.Config, request scaffold.Request, wizardSteps int) setupSummary {
+	var summary setupSummary
+	if usesWizard(opts) {
+		summary.WizardSteps = wizardSteps
+	} else {
+		if opts.Language == "" && cfg.DefaultLanguage != "" {
+			summary.Defaults = append(summary.Defaults, "language defaulted from config")
+		}
+		if opts.Framework == "" && cfg.DefaultFramework != "" {
+			summary.Defaults = append(summary.Defaults, "framework defaulted from config")
+		}
+	}
+	if opts.Dir == "" && strings.TrimSpace(cfg.DefaultDir) != "" {
+		summary.Defaults = append(summary.Defaults, "directory defaulted from config")
+	}
+	if opts.Layout == "" && cfg.Layout != "" {
+		summary.Defaults = append(summary.Defaults, fmt.Sprintf("%s layout from config", cfg.Layout))
+	}
+	if opts.Port == 0 {
+		if port := cfg.PortFor(request.Language, request.Framework); port != 0 {
+			summary.Defaults = append(summary.Defaults, fmt.Sprintf("port %d from config", port))
+		}
+	}
+	return summary
+}
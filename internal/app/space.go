@@ -0,0 +1,86 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+)
+
+// spaceReserve is the free space --check-space wants left after a run.
+// Generators download dependencies as they go, so their plans, which hold
+// no files, are held to it alone.
+const spaceReserve = 100 << 20
+
+// errSpaceUnsupported is returned by freeSpace on platforms without statfs.
+var errSpaceUnsupported = errors.New("checking free space is not supported on this platform")
+
+// availableSpace returns the bytes available to the user on the volume
+// holding dir. It is a variable so tests can report any amount.
+var availableSpace = freeSpace
+
+// checkSpace is --check-space: it fails when the plans' files would not
+// fit on the volume holding target, and warns when they would leave less
+// than spaceReserve free. A volume that cannot be checked only gets a
+// warning.
+func checkSpace(target string, plans []domain.Plan, stderr io.Writer) error {
+	dir, err := existingAncestor(target)
+	if err == nil {
+		var available uint64
+		if available, err = availableSpace(dir); err == nil {
+			return compareSpace(planBytes(plans), available, dir, stderr)
+		}
+	}
+	_, _ = fmt.Fprintf(stderr, "warning: could not check free space for %s: %v\n", target, err)
+	return nil
+}
+
+func compareSpace(need uint64, available uint64, dir string, stderr io.Writer) error {
+	if need > available {
+		return apperrors.NewScaffoldError("check space", fmt.Errorf("%s has %s free, the project needs %s", dir, megabytes(available), megabytes(need)))
+	}
+	if available-need < spaceReserve {
+		_, _ = fmt.Fprintf(stderr, "warning: %s has %s free; the project leaves less than %s\n", dir, megabytes(available), megabytes(spaceReserve))
+	}
+	return nil
+}
+
+// planBytes is the content the plans write.
+func planBytes(plans []domain.Plan) uint64 {
+	var total uint64
+	for _, plan := range plans {
+		for _, action := range plan.Actions {
+			total += uint64(len(action.Content))
+		}
+	}
+	return total
+}
+
+// existingAncestor returns dir, or its nearest parent that exists, since
+// the project and base directories may not be created yet.
+func existingAncestor(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no existing directory above %s", dir)
+		}
+		dir = parent
+	}
+}
+
+func megabytes(n uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
@@ -0,0 +1,7 @@
+//go:build !(linux || darwin || freebsd)
+
+package app
+
+func freeSpace(dir string) (uint64, error) {
+	return 0, errSpaceUnsupported
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"project-initiator/internal/flags"
+	"project-initiator/internal/ui"
+)
+
+// errDumbTerminal stops the wizard before it starts on a terminal that
+// cannot show it.
+var errDumbTerminal = errors.New("TERM=dumb cannot show the full-screen wizard")
+
+// noTerminalMessages are the ways Bubble Tea and the OS report that there
+// is no terminal to run the wizard in: no /dev/tty under cron or CI, or
+// stdin redirected from a file.
+var noTerminalMessages = []string{
+	"/dev/tty",
+	"not a terminal",
+	"inappropriate ioctl",
+	"no such device or address",
+	"the handle is invalid",
+}
+
+// startWizard runs the wizard and turns a failure to start it into advice
+// to rerun with --no-tui. language, framework and dir are what the run has
+// so far, filled into the suggested command.
+func startWizard(opts flags.Options, language string, framework string, dir string, wizardOpts ui.Options) (ui.Result, error) {
+	result, err := runWizard(wizardOpts)
+	if err != nil && (errors.Is(err, errDumbTerminal) || noTerminal(err)) {
+		return ui.Result{}, wizardUnavailable(opts, language, framework, dir, err)
+	}
+	return result, err
+}
+
+func noTerminal(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, known := range noTerminalMessages {
+		if strings.Contains(message, known) {
+			return true
+		}
+	}
+	return false
+}
+
+func dumbTerminal() bool {
+	return strings.EqualFold(os.Getenv("TERM"), "dumb")
+}
+
+// wizardUnavailable explains why the wizard could not start and gives the
+// --no-tui command that completes the request without it, keeping every
+// other flag the run was given. Values the run does not know yet are left
+// as <placeholders>.
+func wizardUnavailable(opts flags.Options, language string, framework string, dir string, cause error) error {
+	args := []string{
+		"project-initiator", "--no-tui",
+		"--lang", commandValue(language, "<language>"),
+		"--framework", commandValue(framework, "<framework>"),
+		"--name", commandValue(opts.Name, "<name>"),
+	}
+	if opts.Libraries != "" {
+		args = append(args, "--libraries", commandValue(opts.Libraries, ""))
+	}
+	if dir != "" {
+		args = append(args, "--dir", commandValue(dir, ""))
+	}
+	if opts.ConfigPath != "" {
+		args = append(args, "--config", commandValue(opts.ConfigPath, ""))
+	}
+	// Every other flag given is passed on as is; --monorepo is what the
+	// suggestion works around.
+	rest := opts
+	rest.Language, rest.Framework, rest.Name, rest.Libraries = "", "", "", ""
+	rest.Dir, rest.ConfigPath, rest.Monorepo, rest.NoTUI = "", "", "", false
+	for _, arg := range flags.Changed(rest) {
+		args = append(args, commandValue(arg, ""))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "the interactive wizard cannot start: %v\n", cause)
+	if opts.Monorepo != "" {
+		b.WriteString("--monorepo needs the wizard; create each project without it instead:\n")
+	} else {
+		b.WriteString("create the project without it:\n")
+	}
+	b.WriteString("  " + strings.Join(args, " "))
+	return errors.New(b.String())
+}
+
+var plainArg = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]+$`)
+
+// commandValue is value quoted for the shell when it needs it, or
+// placeholder when it is empty.
+func commandValue(value string, placeholder string) string {
+	switch {
+	case value == "":
+		return placeholder
+	case plainArg.MatchString(value):
+		return value
+	default:
+		return shellQuote(value)
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timings records how long each phase of a run took, to tell whether a
+// slow run was spent in a generator, on the filesystem or in git. Phases
+// that did not run stay zero.
+type Timings struct {
+	Plan time.Duration
+	// Files is the time spent writing the planned files.
+	Files time.Duration
+	// Generator is the time an external generator, such as composer, ran.
+	Generator time.Duration
+	Git       time.Duration
+	Verify    time.Duration
+	// GeneratorTool names the generator in the summary, e.g. "composer".
+	GeneratorTool string
+}
+
+// Total is the sum of every phase.
+func (t Timings) Total() time.Duration {
+	return t.Plan + t.Files + t.Generator + t.Git + t.Verify
+}
+
+// Summary renders the total and the phases that ran, e.g.
+// "done in 3.4s — composer 2.9s, files 0.2s, git 0.3s".
+func (t Timings) Summary() string {
+	tool := t.GeneratorTool
+	if tool == "" {
+		tool = "generator"
+	}
+	phases := []struct {
+		name    string
+		elapsed time.Duration
+	}{
+		{"plan", t.Plan},
+		{tool, t.Generator},
+		{"files", t.Files},
+		{"git", t.Git},
+		{"verify", t.Verify},
+	}
+
+	var parts []string
+	for _, phase := range phases {
+		if phase.elapsed > 0 {
+			parts = append(parts, phase.name+" "+seconds(phase.elapsed))
+		}
+	}
+	summary := "done in " + seconds(t.Total())
+	if len(parts) > 0 {
+		summary += " — " + strings.Join(parts, ", ")
+	}
+	return summary
+}
+
+// seconds formats d to a tenth of a second.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -1,18 +1,130 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"project-initiator/internal/pkgname"
 )
 
 const defaultConfigFilename = ".project-initiator.json"
 
 type Config struct {
-	DefaultLanguage  string `json:"defaultLanguage"`
-	DefaultFramework string `json:"defaultFramework"`
-	DefaultDir       string `json:"defaultDir"`
+	DefaultLanguage  string   `json:"defaultLanguage"`
+	DefaultFramework string   `json:"defaultFramework"`
+	DefaultDir       string   `json:"defaultDir"`
+	Disabled         Disabled `json:"disabled,omitzero"`
+	Pinned           []string `json:"pinned,omitempty"`
+	// ApplyIgnore holds path patterns, relative to the project dir, that are
+	// never conflict-checked or overwritten. Nil means the built-in list; an
+	// empty list ignores nothing.
+	ApplyIgnore []string `json:"applyIgnore,omitzero"`
+	// FilePermissions and DirPermissions are octal modes such as "0664" and
+	// "2775" applied to created files and directories regardless of umask.
+	// Empty means the usual 0666/0777 narrowed by the process umask.
+	FilePermissions string `json:"filePermissions,omitempty"`
+	DirPermissions  string `json:"dirPermissions,omitempty"`
+	// AllowSymlinkedDirs lets projects be written through symlinked
+	// directories below the base dir, which are refused by default.
+	AllowSymlinkedDirs bool `json:"allowSymlinkedDirs,omitempty"`
+	// Transitions sets the wizard animation speed; see TransitionSpeeds.
+	// Empty means normal.
+	Transitions string `json:"transitions,omitempty"`
+	// Layout is "by-language" (the default) to put projects in
+	// <dir>/<Language>/<name>, or "flat" for <dir>/<name>.
+	Layout string `json:"layout,omitempty"`
+	// SkipSplash shows the wizard title fully revealed from the start.
+	SkipSplash bool `json:"skipSplash,omitempty"`
+	// DurableWrites syncs each created file to disk before moving on, for
+	// network filesystems where a crash can otherwise leave empty files.
+	DurableWrites bool `json:"durableWrites,omitempty"`
+	// AtomicWrites builds each new project in a staging dir and renames it
+	// into place once complete, so a failed run leaves nothing behind.
+	AtomicWrites bool `json:"atomicWrites,omitempty"`
+	// Ports maps "Language/Framework" combos to the port their servers
+	// listen on when --port is not given, e.g. {"Python/FastAPI": 8001}.
+	Ports map[string]int `json:"ports,omitempty"`
+	// NPMScope, such as "@acme", prefixes the package.json name of
+	// JavaScript projects: @acme/<slug>.
+	NPMScope string `json:"npmScope,omitempty"`
+	// GeneratedHeader stamps code files with a comment saying which
+	// version of the tool generated them, when and for which project.
+	GeneratedHeader bool `json:"generatedHeader,omitempty"`
+	// NameHistory holds the names of recently created projects, newest
+	// first, for recall in the wizard's name input.
+	NameHistory []string `json:"nameHistory,omitempty"`
+	// LastFrameworkByLang maps each language to the framework last created
+	// with it, so choosing a language in the wizard recalls its framework.
+	LastFrameworkByLang map[string]string `json:"lastFrameworkByLang,omitempty"`
+}
+
+// NameHistoryLimit is how many names NameHistory keeps.
+const NameHistoryLimit = 20
+
+// RememberName moves name to the front of NameHistory, dropping an older
+// entry with the same name and any beyond NameHistoryLimit.
+func (c *Config) RememberName(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	history := []string{name}
+	for _, old := range c.NameHistory {
+		if old != name && len(history) < NameHistoryLimit {
+			history = append(history, old)
+		}
+	}
+	c.NameHistory = history
+}
+
+// RememberFramework records framework as the last one created for
+// language, replacing an entry that differs only in case.
+func (c *Config) RememberFramework(language string, framework string) {
+	if language == "" || framework == "" {
+		return
+	}
+	for known := range c.LastFrameworkByLang {
+		if strings.EqualFold(known, language) {
+			delete(c.LastFrameworkByLang, known)
+		}
+	}
+	if c.LastFrameworkByLang == nil {
+		c.LastFrameworkByLang = map[string]string{}
+	}
+	c.LastFrameworkByLang[language] = framework
+}
+
+// LastFramework returns the framework last created for language, matched
+// case-insensitively, or "" when there is none.
+func (c Config) LastFramework(language string) string {
+	for known, framework := range c.LastFrameworkByLang {
+		if strings.EqualFold(known, language) {
+			return framework
+		}
+	}
+	return ""
+}
+
+// Layouts lists the accepted Layout values.
+var Layouts = []string{"by-language", "flat"}
+
+// TransitionSpeeds lists the accepted Transitions values. "off" stops all
+// wizard animation.
+var TransitionSpeeds = []string{"off", "slow", "normal", "fast"}
+
+// Disabled lists built-in options hidden from the wizard and --list.
+// Frameworks are written as "Language/Framework", e.g. "Go/Cobra".
+type Disabled struct {
+	Languages  []string `json:"languages,omitempty"`
+	Frameworks []string `json:"frameworks,omitempty"`
 }
 
 func Default() Config {
@@ -27,42 +139,553 @@ func Default() Config {
 	}
 }
 
+// Load reads the config at path. path may list several files separated by
+// commas, e.g. a shared base followed by personal overrides; they are
+// merged in order, so a key set in a later file wins. Missing files are
+// skipped, and defaults fill in whatever no file sets.
 func Load(path string) (Config, error) {
-	if path == "" {
-		path = defaultConfigPath()
-	}
-
-	data, err := os.ReadFile(path)
+	cfg, found, err := loadLayers(Paths(path))
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return Default(), nil
-		}
 		return Config{}, err
 	}
+	if !found {
+		return Default(), nil
+	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := validate(cfg); err != nil {
 		return Config{}, err
 	}
-
 	return applyDefaults(cfg), nil
 }
 
+// validate checks the keys whose values have a fixed syntax, so Load and
+// Import reject the same configs.
+func validate(cfg Config) error {
+	if _, _, err := cfg.Permissions(); err != nil {
+		return err
+	}
+	if _, err := ParseTransitions(cfg.Transitions); err != nil {
+		return fmt.Errorf("transitions: %w", err)
+	}
+	if err := ValidateLayout(cfg.Layout); err != nil {
+		return fmt.Errorf("layout: %w", err)
+	}
+	if err := ValidatePorts(cfg.Ports); err != nil {
+		return fmt.Errorf("ports: %w", err)
+	}
+	if err := pkgname.ValidateNPMScope(cfg.NPMScope); err != nil {
+		return fmt.Errorf("npmScope: %w", err)
+	}
+	return nil
+}
+
+// loadLayers decodes each existing file in paths over the previous ones.
+// Only keys present in a file change the merged config. found reports
+// whether any file existed.
+func loadLayers(paths []string) (cfg Config, found bool, err error) {
+	for _, path := range paths {
+		if err := checkFormat(path); err != nil {
+			return Config{}, false, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return Config{}, false, err
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			if len(paths) > 1 {
+				return Config{}, false, fmt.Errorf("%s: %w", path, err)
+			}
+			return Config{}, false, err
+		}
+		found = true
+	}
+	return cfg, found, nil
+}
+
+// Save writes cfg to path. When path lists several files, only the last
+// one is written, and it keeps just the keys that differ from what the
+// files before it give, so later changes to a shared base still apply.
 func Save(path string, cfg Config) error {
-	if path == "" {
-		path = defaultConfigPath()
+	paths := Paths(path)
+	path = paths[len(paths)-1]
+	if err := checkFormat(path); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	if len(paths) == 1 {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+	} else {
+		data, err = overrideJSON(paths[:len(paths)-1], cfg)
+	}
+	if err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
+	return writeFileAtomic(path, data, 0o644)
+}
+
+// Update re-reads the config at path, applies change to it and saves the
+// result, so keys another run saved since this one loaded the config are
+// kept. change should set only the keys the caller means to change; when it
+// changes nothing, nothing is written.
+func Update(path string, change func(cfg *Config) error) error {
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	before, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := change(&cfg); err != nil {
+		return err
+	}
+	after, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(before, after) {
+		return nil
+	}
+	return Save(path, cfg)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path, so readers never see a partly written file. A symlinked
+// path is followed so the link survives, and an existing file keeps its
+// mode; perm only applies to new files.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
 
-	return os.WriteFile(path, data, 0o644)
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// overrideJSON encodes the keys of cfg that differ from the merged base
+// files.
+func overrideJSON(basePaths []string, cfg Config) ([]byte, error) {
+	base, _, err := loadLayers(basePaths)
+	if err != nil {
+		return nil, err
+	}
+	baseKeys, err := jsonKeys(applyDefaults(base))
+	if err != nil {
+		return nil, err
+	}
+	keys, err := jsonKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	override := map[string]json.RawMessage{}
+	for key, value := range keys {
+		if !bytes.Equal(value, baseKeys[key]) {
+			override[key] = value
+		}
+	}
+	// A key cleared in cfg is omitted from its JSON; write it out empty so
+	// the base value does not come back on the next load.
+	for key, value := range baseKeys {
+		if _, ok := keys[key]; !ok {
+			override[key] = emptyJSON(value)
+		}
+	}
+	return json.MarshalIndent(override, "", "  ")
+}
+
+// emptyJSON returns the empty value of the same JSON type as value.
+// Objects keep their keys with empty values, since decoding {} over a
+// struct would leave its fields as they were.
+func emptyJSON(value json.RawMessage) json.RawMessage {
+	switch {
+	case bytes.HasPrefix(value, []byte("{")):
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return json.RawMessage("{}")
+		}
+		for key, field := range fields {
+			fields[key] = emptyJSON(field)
+		}
+		data, _ := json.Marshal(fields)
+		return data
+	case bytes.HasPrefix(value, []byte("[")):
+		return json.RawMessage("[]")
+	case bytes.HasPrefix(value, []byte(`"`)):
+		return json.RawMessage(`""`)
+	case bytes.Equal(value, []byte("true")), bytes.Equal(value, []byte("false")):
+		return json.RawMessage("false")
+	default:
+		return json.RawMessage("null")
+	}
+}
+
+// jsonKeys encodes cfg and splits it into its top-level keys.
+func jsonKeys(cfg Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string]json.RawMessage
+	err = json.Unmarshal(data, &keys)
+	return keys, err
+}
+
+// Paths splits a comma-separated --config value into the files Load merges,
+// in order. An empty path means the default config file.
+func Paths(path string) []string {
+	paths := splitList(path)
+	if len(paths) == 0 {
+		return []string{defaultConfigPath()}
+	}
+	return paths
+}
+
+// Export writes the config stored at path to target so it can be shared.
+func Export(path string, target string) error {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return errors.New("export path is required")
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	return Save(target, cfg)
+}
+
+// Import loads the config file at source and saves it as the active config at path.
+func Import(source string, path string) error {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return errors.New("import path is required")
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil {
+		return fmt.Errorf("invalid config %s: %w", source, err)
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid config %s: %w", source, err)
+	}
+
+	return Save(path, applyDefaults(cfg))
+}
+
+// Key describes a config key supported by Get and Set.
+type Key struct {
+	Name        string
+	Description string
+}
+
+// Keys lists the config keys supported by Get and Set. Help text and the
+// man page are generated from it.
+var Keys = []Key{
+	{"defaultLanguage", "Language preselected in the wizard and used when --lang is not given"},
+	{"defaultFramework", "Framework preselected in the wizard and used when --framework is not given"},
+	{"defaultDir", "Base directory for new projects when --dir is not given"},
+	{"pinned", "Comma-separated Language/Framework combos listed first in the wizard"},
+	{"disabled.languages", "Comma-separated languages hidden from the wizard and --list"},
+	{"disabled.frameworks", "Comma-separated Language/Framework combos hidden from the wizard and --list"},
+	{"applyIgnore", "Comma-separated path patterns in the project dir that are never conflict-checked or overwritten"},
+	{"filePermissions", "Octal mode, such as 0664, for created files regardless of umask"},
+	{"dirPermissions", "Octal mode, such as 2775, for created directories regardless of umask"},
+	{"allowSymlinkedDirs", "Allow writing projects through symlinked directories below the base dir (true or false)"},
+	{"transitions", "Wizard animation speed: " + strings.Join(TransitionSpeeds, ", ")},
+	{"layout", "Project directory layout: by-language for <dir>/<Language>/<name>, or flat for <dir>/<name>"},
+	{"skipSplash", "Show the wizard title fully revealed instead of typing it out (true or false)"},
+	{"durableWrites", "Write files through a synced temp file and rename, for network filesystems; slower (true or false)"},
+	{"atomicWrites", "Build new projects in a staging dir renamed into place when complete, so a failure leaves no partial project (true or false)"},
+	{"ports", "Comma-separated Language/Framework=port defaults for servers when --port is not given, e.g. Python/FastAPI=8001"},
+	{"npmScope", "Scope, such as @acme, for the package.json name of JavaScript projects"},
+	{"generatedHeader", "Start generated code files with a comment naming the tool version, date and project (true or false)"},
+}
+
+// IsKey reports whether name is one of Keys.
+func IsKey(name string) bool {
+	return slices.ContainsFunc(Keys, func(k Key) bool { return k.Name == name })
+}
+
+// Get returns the value of key formatted for display. List values are
+// comma-separated.
+func (c Config) Get(key string) (string, error) {
+	switch key {
+	case "defaultLanguage":
+		return c.DefaultLanguage, nil
+	case "defaultFramework":
+		return c.DefaultFramework, nil
+	case "defaultDir":
+		return c.DefaultDir, nil
+	case "pinned":
+		return strings.Join(c.Pinned, ","), nil
+	case "disabled.languages":
+		return strings.Join(c.Disabled.Languages, ","), nil
+	case "disabled.frameworks":
+		return strings.Join(c.Disabled.Frameworks, ","), nil
+	case "applyIgnore":
+		return strings.Join(c.ApplyIgnore, ","), nil
+	case "filePermissions":
+		return c.FilePermissions, nil
+	case "dirPermissions":
+		return c.DirPermissions, nil
+	case "allowSymlinkedDirs":
+		return strconv.FormatBool(c.AllowSymlinkedDirs), nil
+	case "transitions":
+		return c.Transitions, nil
+	case "layout":
+		return c.Layout, nil
+	case "skipSplash":
+		return strconv.FormatBool(c.SkipSplash), nil
+	case "durableWrites":
+		return strconv.FormatBool(c.DurableWrites), nil
+	case "atomicWrites":
+		return strconv.FormatBool(c.AtomicWrites), nil
+	case "ports":
+		pairs := make([]string, 0, len(c.Ports))
+		for _, combo := range slices.Sorted(maps.Keys(c.Ports)) {
+			pairs = append(pairs, combo+"="+strconv.Itoa(c.Ports[combo]))
+		}
+		return strings.Join(pairs, ","), nil
+	case "npmScope":
+		return c.NPMScope, nil
+	case "generatedHeader":
+		return strconv.FormatBool(c.GeneratedHeader), nil
+	default:
+		return "", fmt.Errorf("unknown config key: %s", key)
+	}
+}
+
+// Set parses value and stores it under key. List values are comma-separated.
+func (c *Config) Set(key string, value string) error {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "defaultLanguage":
+		c.DefaultLanguage = value
+	case "defaultFramework":
+		c.DefaultFramework = value
+	case "defaultDir":
+		c.DefaultDir = value
+	case "pinned":
+		c.Pinned = splitList(value)
+	case "disabled.languages":
+		c.Disabled.Languages = splitList(value)
+	case "disabled.frameworks":
+		c.Disabled.Frameworks = splitList(value)
+	case "applyIgnore":
+		// Setting it, even to nothing, replaces the built-in list.
+		c.ApplyIgnore = append([]string{}, splitList(value)...)
+	case "filePermissions", "dirPermissions":
+		if _, err := ParsePermissions(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if key == "filePermissions" {
+			c.FilePermissions = value
+		} else {
+			c.DirPermissions = value
+		}
+	case "allowSymlinkedDirs", "skipSplash", "durableWrites", "atomicWrites", "generatedHeader":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: want true or false, got %q", key, value)
+		}
+		switch key {
+		case "allowSymlinkedDirs":
+			c.AllowSymlinkedDirs = enabled
+		case "skipSplash":
+			c.SkipSplash = enabled
+		case "generatedHeader":
+			c.GeneratedHeader = enabled
+		case "atomicWrites":
+			c.AtomicWrites = enabled
+		default:
+			c.DurableWrites = enabled
+		}
+	case "transitions":
+		speed, err := ParseTransitions(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.Transitions = speed
+	case "layout":
+		if err := ValidateLayout(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.Layout = value
+	case "ports":
+		ports, err := parsePorts(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.Ports = ports
+	case "npmScope":
+		if err := pkgname.ValidateNPMScope(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.NPMScope = value
+	default:
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	return nil
+}
+
+// Permissions parses FilePermissions and DirPermissions. A zero mode means
+// the setting is empty.
+func (c Config) Permissions() (file os.FileMode, dir os.FileMode, err error) {
+	if file, err = ParsePermissions(c.FilePermissions); err != nil {
+		return 0, 0, fmt.Errorf("filePermissions: %w", err)
+	}
+	if dir, err = ParsePermissions(c.DirPermissions); err != nil {
+		return 0, 0, fmt.Errorf("dirPermissions: %w", err)
+	}
+	return file, dir, nil
+}
+
+// ParsePermissions parses an octal mode such as "0644" or "2775" into an
+// os.FileMode, mapping the setuid, setgid and sticky bits. An empty string
+// yields 0.
+func ParsePermissions(value string) (os.FileMode, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	bits, err := strconv.ParseUint(strings.TrimPrefix(value, "0o"), 8, 32)
+	if err != nil || bits > 0o7777 {
+		return 0, fmt.Errorf("invalid permissions %q: want an octal mode such as 0644", value)
+	}
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode, nil
+}
+
+// ParseTransitions normalizes a transition speed, case-insensitively. An
+// empty string is returned as is and means normal.
+func ParseTransitions(value string) (string, error) {
+	speed := strings.ToLower(strings.TrimSpace(value))
+	if speed == "" || slices.Contains(TransitionSpeeds, speed) {
+		return speed, nil
+	}
+	return "", fmt.Errorf("invalid transition speed %q: want one of %s", value, strings.Join(TransitionSpeeds, ", "))
+}
+
+// ValidateLayout accepts one of Layouts, or empty for the default layout.
+func ValidateLayout(value string) error {
+	if value == "" || slices.Contains(Layouts, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid layout %q: want one of %s", value, strings.Join(Layouts, ", "))
+}
+
+// PortFor returns the configured port for a Language/Framework combo,
+// matched case-insensitively, or zero when none is set.
+func (c Config) PortFor(language string, framework string) int {
+	for combo, port := range c.Ports {
+		if strings.EqualFold(strings.TrimSpace(combo), language+"/"+framework) {
+			return port
+		}
+	}
+	return 0
+}
+
+// ValidatePorts checks that every configured port is a TCP port.
+func ValidatePorts(ports map[string]int) error {
+	for _, combo := range slices.Sorted(maps.Keys(ports)) {
+		if port := ports[combo]; port < 1 || port > 65535 {
+			return fmt.Errorf("%s: %d is not a TCP port (1-65535)", combo, port)
+		}
+	}
+	return nil
+}
+
+// parsePorts parses "Language/Framework=port" pairs.
+func parsePorts(value string) (map[string]int, error) {
+	pairs := splitList(value)
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	ports := make(map[string]int, len(pairs))
+	for _, pair := range pairs {
+		combo, raw, ok := strings.Cut(pair, "=")
+		combo = strings.TrimSpace(combo)
+		port, err := strconv.Atoi(strings.TrimSpace(raw))
+		if !ok || !strings.Contains(combo, "/") || err != nil {
+			return nil, fmt.Errorf("invalid port %q: want Language/Framework=port", pair)
+		}
+		ports[combo] = port
+	}
+	return ports, ValidatePorts(ports)
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// otherFormats maps the extensions of config formats this package can't
+// read or write, so such a file is neither parsed as JSON nor overwritten
+// with it.
+//
+// This is only a guard. Saving a YAML or TOML config back in its own format
+// waits on Load reading those formats, which needs a parser this module
+// does not depend on yet; until then both formats are refused.
+var otherFormats = map[string]string{".yaml": "YAML", ".yml": "YAML", ".toml": "TOML"}
+
+// checkFormat refuses a config path in one of otherFormats.
+func checkFormat(path string) error {
+	if format, ok := otherFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return fmt.Errorf("%s: %s config files are not supported yet; use a .json file", path, format)
+	}
+	return nil
 }
 
 func defaultConfigPath() string {
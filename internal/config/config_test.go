@@ -2,8 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -36,7 +41,7 @@ func TestLoad(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if cfg != defaults {
+		if !reflect.DeepEqual(cfg, defaults) {
 			t.Errorf("got %+v, want %+v", cfg, defaults)
 		}
 	})
@@ -56,7 +61,7 @@ func TestLoad(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if got != want {
+		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %+v, want %+v", got, want)
 		}
 	})
@@ -96,7 +101,7 @@ func TestLoad(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if got != defaults {
+		if !reflect.DeepEqual(got, defaults) {
 			t.Errorf("got %+v, want %+v", got, defaults)
 		}
 	})
@@ -122,6 +127,25 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestLoad_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	raw := `{"disabled": {"languages": ["PHP"], "frameworks": ["Go/Cobra"]}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Disabled{Languages: []string{"PHP"}, Frameworks: []string{"Go/Cobra"}}
+	if !reflect.DeepEqual(got.Disabled, want) {
+		t.Errorf("Disabled = %+v, want %+v", got.Disabled, want)
+	}
+}
+
 func TestSave(t *testing.T) {
 	t.Run("saves to file and reads back correctly", func(t *testing.T) {
 		dir := t.TempDir()
@@ -141,7 +165,7 @@ func TestSave(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Load() error after Save: %v", err)
 		}
-		if got != want {
+		if !reflect.DeepEqual(got, want) {
 			t.Errorf("round-trip failed: got %+v, want %+v", got, want)
 		}
 	})
@@ -160,6 +184,79 @@ func TestSave(t *testing.T) {
 		}
 	})
 
+	t.Run("leaves no temporary files behind", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+
+		for range 3 {
+			if err := Save(path, Default()); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "config.json" {
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			t.Errorf("dir holds %v, want only config.json", names)
+		}
+	})
+
+	t.Run("writes through a symlinked config", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "dotfiles", "config.json")
+		if err := Save(target, Default()); err != nil {
+			t.Fatalf("first Save() error: %v", err)
+		}
+		path := filepath.Join(dir, "config.json")
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+
+		want := Config{DefaultLanguage: "Go", DefaultFramework: "Gin"}
+		if err := Save(path, want); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+
+		if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Fatalf("%s is no longer a symlink: %v", path, err)
+		}
+		got, err := Load(target)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if got.DefaultFramework != want.DefaultFramework {
+			t.Errorf("link target DefaultFramework = %q, want %q", got.DefaultFramework, want.DefaultFramework)
+		}
+	})
+
+	t.Run("keeps the existing file's mode", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Windows has no Unix file modes")
+		}
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Save(path, Default()); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != 0o600 {
+			t.Errorf("mode = %o, want 600", got)
+		}
+	})
+
 	t.Run("overwrites existing file", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "config.json")
@@ -186,12 +283,500 @@ func TestSave(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Load() error: %v", err)
 		}
-		if got != updated {
+		if !reflect.DeepEqual(got, updated) {
 			t.Errorf("got %+v, want %+v", got, updated)
 		}
 	})
 }
 
+func TestUpdate_InterleavedRunsKeepEachOthersChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := Save(path, Config{DefaultLanguage: "Go", DefaultFramework: "Cobra", DefaultDir: "/projects"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	// Two runs load the config before either saves.
+	first, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	second, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	// The first run pins a framework in the wizard and saves.
+	first.Pinned = []string{"Go/Gin"}
+	if err := Update(path, func(cfg *Config) error {
+		cfg.Pinned = first.Pinned
+		return nil
+	}); err != nil {
+		t.Fatalf("first Update() error: %v", err)
+	}
+
+	// The second run, still holding the stale config, finishes a Python
+	// project in another directory and saves its defaults.
+	second.DefaultLanguage = "Python"
+	second.DefaultFramework = "FastAPI"
+	second.DefaultDir = "/services"
+	if err := Update(path, func(cfg *Config) error {
+		cfg.DefaultLanguage = second.DefaultLanguage
+		cfg.DefaultFramework = second.DefaultFramework
+		cfg.DefaultDir = second.DefaultDir
+		return nil
+	}); err != nil {
+		t.Fatalf("second Update() error: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := Config{
+		DefaultLanguage:  "Python",
+		DefaultFramework: "FastAPI",
+		DefaultDir:       "/services",
+		Pinned:           []string{"Go/Gin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("after interleaved updates got %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdate_ChangeErrorSavesNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := Save(path, Config{DefaultLanguage: "Go", DefaultFramework: "Cobra", DefaultDir: "/projects"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	err := Update(path, func(cfg *Config) error {
+		cfg.DefaultLanguage = "Rust"
+		return cfg.Set("layout", "sideways")
+	})
+	if err == nil {
+		t.Fatal("Update() error = nil, want the change's error")
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.DefaultLanguage != "Go" {
+		t.Errorf("DefaultLanguage = %q, want the saved Go", got.DefaultLanguage)
+	}
+}
+
+func TestLayeredConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, path string, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("later files win and defaults fill the rest", func(t *testing.T) {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "team.json")
+		override := filepath.Join(dir, "me.json")
+		writeConfig(t, base, `{"defaultFramework": "Vanilla", "pinned": ["Go/Cobra"], "disabled": {"languages": ["PHP"]}}`)
+		writeConfig(t, override, `{"defaultFramework": "Gin", "disabled": {"frameworks": ["Go/Cobra"]}}`)
+
+		got, err := Load(base + "," + override)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		want := Config{
+			DefaultLanguage:  Default().DefaultLanguage,
+			DefaultFramework: "Gin",
+			DefaultDir:       Default().DefaultDir,
+			Pinned:           []string{"Go/Cobra"},
+			Disabled:         Disabled{Languages: []string{"PHP"}, Frameworks: []string{"Go/Cobra"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Load() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("missing layers are skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "team.json")
+		writeConfig(t, base, `{"defaultLanguage": "Python"}`)
+
+		got, err := Load(base + ", " + filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if got.DefaultLanguage != "Python" {
+			t.Errorf("DefaultLanguage = %q, want Python", got.DefaultLanguage)
+		}
+	})
+
+	t.Run("save writes only the override's differences", func(t *testing.T) {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "team.json")
+		override := filepath.Join(dir, "me.json")
+		writeConfig(t, base, `{"defaultFramework": "Vanilla", "pinned": ["Go/Cobra"], "defaultDir": "/srv"}`)
+		paths := base + "," + override
+
+		cfg, err := Load(paths)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		cfg.DefaultFramework = "Gin"
+		cfg.Pinned = nil
+		if err := Save(paths, cfg); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+
+		data, err := os.ReadFile(override)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var written map[string]any
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]any{"defaultFramework": "Gin", "pinned": []any{}}
+		if !reflect.DeepEqual(written, want) {
+			t.Errorf("override file = %s, want only %v", data, want)
+		}
+
+		got, err := Load(paths)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if got.DefaultFramework != "Gin" || len(got.Pinned) != 0 || got.DefaultDir != "/srv" {
+			t.Errorf("reloaded %+v, want Gin, no pins and the base dir", got)
+		}
+	})
+}
+
+func TestExportImport(t *testing.T) {
+	t.Run("export then import round-trips the config", func(t *testing.T) {
+		dir := t.TempDir()
+		active := filepath.Join(dir, "active.json")
+		shared := filepath.Join(dir, "shared", "team.json")
+		other := filepath.Join(dir, "other.json")
+
+		want := Config{
+			DefaultLanguage:  "Python",
+			DefaultFramework: "FastAPI",
+			DefaultDir:       "/srv/apps",
+		}
+		if err := Save(active, want); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+
+		if err := Export(active, shared); err != nil {
+			t.Fatalf("Export() error: %v", err)
+		}
+		if err := Import(shared, other); err != nil {
+			t.Fatalf("Import() error: %v", err)
+		}
+
+		got, err := Load(other)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round-trip failed: got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("export requires a target path", func(t *testing.T) {
+		if err := Export(filepath.Join(t.TempDir(), "config.json"), "  "); err == nil {
+			t.Error("expected error for empty export path")
+		}
+	})
+
+	t.Run("import requires a source path", func(t *testing.T) {
+		if err := Import("", filepath.Join(t.TempDir(), "config.json")); err == nil {
+			t.Error("expected error for empty import path")
+		}
+	})
+
+	t.Run("import of missing file returns error", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := Import(filepath.Join(dir, "missing.json"), filepath.Join(dir, "config.json")); err == nil {
+			t.Error("expected error for missing import file")
+		}
+	})
+
+	t.Run("import rejects unknown keys", func(t *testing.T) {
+		dir := t.TempDir()
+		source := filepath.Join(dir, "team.json")
+		writeJSON(t, source, map[string]string{"defaultLanguge": "Go"})
+
+		target := filepath.Join(dir, "config.json")
+		if err := Import(source, target); err == nil {
+			t.Error("expected error for unknown config key")
+		}
+		if _, err := os.Stat(target); !os.IsNotExist(err) {
+			t.Error("active config should not be written when import fails")
+		}
+	})
+}
+
+func TestSave_PinnedRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	want := Default()
+	want.Pinned = []string{"Go/Gin", "TypeScript/Hono"}
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Pinned, want.Pinned) {
+		t.Errorf("Pinned = %v, want %v", got.Pinned, want.Pinned)
+	}
+}
+
+func TestRememberName(t *testing.T) {
+	full := make([]string, NameHistoryLimit)
+	for i := range full {
+		full[i] = fmt.Sprintf("app-%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		history []string
+		add     string
+		want    []string
+	}{
+		{name: "empty history", add: "api", want: []string{"api"}},
+		{name: "newest first", history: []string{"api"}, add: "web", want: []string{"web", "api"}},
+		{name: "repeat moves to front", history: []string{"web", "api", "cli"}, add: "api", want: []string{"api", "web", "cli"}},
+		{name: "blank ignored", history: []string{"api"}, add: "  ", want: []string{"api"}},
+		{name: "trimmed", add: " api ", want: []string{"api"}},
+		{name: "capped", history: full, add: "new", want: append([]string{"new"}, full[:NameHistoryLimit-1]...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{NameHistory: slices.Clone(tt.history)}
+			cfg.RememberName(tt.add)
+			if !slices.Equal(cfg.NameHistory, tt.want) {
+				t.Errorf("NameHistory = %q, want %q", cfg.NameHistory, tt.want)
+			}
+		})
+	}
+}
+
+func TestRememberFramework(t *testing.T) {
+	var cfg Config
+	cfg.RememberFramework("Go", "Cobra")
+	cfg.RememberFramework("Python", "FastAPI")
+	cfg.RememberFramework("go", "Vanilla")
+
+	want := map[string]string{"go": "Vanilla", "Python": "FastAPI"}
+	if !reflect.DeepEqual(cfg.LastFrameworkByLang, want) {
+		t.Errorf("LastFrameworkByLang = %v, want %v", cfg.LastFrameworkByLang, want)
+	}
+	if got := cfg.LastFramework("GO"); got != "Vanilla" {
+		t.Errorf("LastFramework(GO) = %q, want Vanilla", got)
+	}
+	if got := cfg.LastFramework("PHP"); got != "" {
+		t.Errorf("LastFramework(PHP) = %q, want none", got)
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "scalar key", key: "defaultLanguage", value: "Python", want: "Python"},
+		{name: "list key trims entries", key: "pinned", value: " Go/Gin , TypeScript/Hono,", want: "Go/Gin,TypeScript/Hono"},
+		{name: "empty list clears", key: "pinned", value: "", want: ""},
+		{name: "nested key", key: "disabled.languages", value: "PHP", want: "PHP"},
+		{name: "permissions key", key: "dirPermissions", value: "2775", want: "2775"},
+		{name: "bool key", key: "allowSymlinkedDirs", value: "true", want: "true"},
+		{name: "skip splash", key: "skipSplash", value: "true", want: "true"},
+		{name: "durable writes", key: "durableWrites", value: "true", want: "true"},
+		{name: "atomic writes", key: "atomicWrites", value: "true", want: "true"},
+		{name: "generated header", key: "generatedHeader", value: "true", want: "true"},
+		{name: "transitions normalized", key: "transitions", value: " Off ", want: "off"},
+		{name: "unknown transition speed", key: "transitions", value: "ludicrous", wantErr: true},
+		{name: "layout", key: "layout", value: "flat", want: "flat"},
+		{name: "unknown layout", key: "layout", value: "nested", wantErr: true},
+		{name: "ports sorted", key: "ports", value: "Python/FastAPI=8001, Go/Cobra=8080", want: "Go/Cobra=8080,Python/FastAPI=8001"},
+		{name: "port out of range", key: "ports", value: "Go/Cobra=70000", wantErr: true},
+		{name: "port without combo", key: "ports", value: "8080", wantErr: true},
+		{name: "npm scope", key: "npmScope", value: "@acme", want: "@acme"},
+		{name: "npm scope without @", key: "npmScope", value: "acme", wantErr: true},
+		{name: "unknown key", key: "colour", value: "blue", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			err := cfg.Set(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsKey(tt.key) {
+					if _, err := cfg.Get(tt.key); err == nil {
+						t.Error("Get() should also reject unknown key")
+					}
+				}
+				return
+			}
+
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeys_SupportedByGetAndSet(t *testing.T) {
+	for _, key := range Keys {
+		if key.Description == "" {
+			t.Errorf("key %q has no description", key.Name)
+		}
+		cfg := Default()
+		value, err := cfg.Get(key.Name)
+		if err != nil {
+			t.Errorf("Get(%q) error: %v", key.Name, err)
+			continue
+		}
+		if err := cfg.Set(key.Name, value); err != nil {
+			t.Errorf("Set(%q, %q) error: %v", key.Name, value, err)
+		}
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0644", want: 0o644},
+		{value: "664", want: 0o664},
+		{value: "0o750", want: 0o750},
+		{value: "2775", want: os.ModeSetgid | 0o775},
+		{value: "1777", want: os.ModeSticky | 0o777},
+		{value: "0988", wantErr: true},
+		{value: "17777", wantErr: true},
+		{value: "rw-r--r--", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePermissions(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePermissions(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePermissions(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissions_RejectedWhenInvalid(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Set("filePermissions", "0999"); err == nil {
+		t.Error("Set() accepted an invalid mode")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"dirPermissions": "rwx"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "dirPermissions") {
+		t.Errorf("Load() error = %v, want a dirPermissions error", err)
+	}
+}
+
+func TestConfig_RejectsOtherFormats(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	saved := "defaultLanguage: Go\n"
+	if err := os.WriteFile(path, []byte(saved), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "YAML config files are not supported") {
+		t.Errorf("Load() error = %v, want the unsupported-format error", err)
+	}
+	err := Update(path, func(cfg *Config) error {
+		cfg.DefaultLanguage = "Rust"
+		return nil
+	})
+	if err == nil {
+		t.Error("Update() error = nil, want the unsupported-format error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != saved {
+		t.Errorf("config.yaml = %q, want it left as %q", data, saved)
+	}
+	if err := Save(filepath.Join(dir, "config.TOML"), Default()); err == nil {
+		t.Error("Save(config.TOML) error = nil, want the unsupported-format error")
+	}
+}
+
+func TestLoad_RejectsUnknownTransitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"transitions": "warp"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "transitions") {
+		t.Errorf("Load() error = %v, want a transitions error", err)
+	}
+}
+
+func TestLoad_RejectsInvalidPorts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"ports": {"Go/Cobra": 0}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "ports") {
+		t.Errorf("Load() error = %v, want a ports error", err)
+	}
+}
+
+func TestLoad_RejectsInvalidNPMScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"npmScope": "Acme"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "npmScope: npm:") {
+		t.Errorf("Load() error = %v, want an npmScope error", err)
+	}
+}
+
+func TestPortFor(t *testing.T) {
+	cfg := Config{Ports: map[string]int{"Python/FastAPI": 8001}}
+	if got := cfg.PortFor("python", "fastapi"); got != 8001 {
+		t.Errorf("PortFor(python, fastapi) = %d, want 8001", got)
+	}
+	if got := cfg.PortFor("Go", "Cobra"); got != 0 {
+		t.Errorf("PortFor(Go, Cobra) = %d, want 0", got)
+	}
+}
+
+func TestSet_RejectsMalformedBool(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Set("allowSymlinkedDirs", "sometimes"); err == nil {
+		t.Error("Set() accepted a non-boolean value")
+	}
+	if cfg.AllowSymlinkedDirs {
+		t.Error("rejected value should leave the setting unchanged")
+	}
+}
+
 func TestApplyDefaults(t *testing.T) {
 	defaults := Default()
 
@@ -262,7 +847,7 @@ func TestApplyDefaults(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := applyDefaults(tt.in)
-			if got != tt.want {
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("applyDefaults(%+v) = %+v, want %+v", tt.in, got, tt.want)
 			}
 		})
@@ -280,3 +865,33 @@ func writeJSON(t *testing.T, path string, v any) {
 		t.Fatalf("failed to write file: %v", err)
 	}
 }
+
+func TestSave_ApplyIgnoreRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		ignore []string
+	}{
+		{name: "unset keeps built-in list", ignore: nil},
+		{name: "explicitly empty ignores nothing", ignore: []string{}},
+		{name: "custom patterns", ignore: []string{".git/", "*.log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			cfg := Default()
+			cfg.ApplyIgnore = tt.ignore
+			if err := Save(path, cfg); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if !reflect.DeepEqual(got.ApplyIgnore, tt.ignore) {
+				t.Errorf("ApplyIgnore = %#v, want %#v", got.ApplyIgnore, tt.ignore)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "go.mod gains a requirement",
+			old:  "module app\n\ngo 1.25\n",
+			new:  "module app\n\ngo 1.25\n\nrequire github.com/gin-gonic/gin v1.10.0\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n" +
+				"@@ -1,3 +1,5 @@\n" +
+				" module app\n" +
+				" \n" +
+				" go 1.25\n" +
+				"+\n" +
+				"+require github.com/gin-gonic/gin v1.10.0\n",
+		},
+		{
+			name: "changed line in the middle",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			new:  "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "x\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -0,0 +1 @@\n+x\n",
+		},
+		{
+			name: "missing final newline",
+			old:  "x",
+			new:  "x\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -1 +1 @@\n-x\n\\ No newline at end of file\n+x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("a/go.mod", "b/go.mod", tt.old, tt.new); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUnified_Applies checks the output against patch, when it is
+// installed, for a larger edit.
+func TestUnified_Applies(t *testing.T) {
+	patch, err := exec.LookPath("patch")
+	if err != nil {
+		t.Skip("patch not installed")
+	}
+	old := strings.Repeat("keep\nold\n", 20) + "tail"
+	new := "head\n" + strings.ReplaceAll(old, "old\n", "new\nline\n") + "\n"
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(patch, file)
+	cmd.Stdin = strings.NewReader(Unified("a/file.txt", "b/file.txt", old, new))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("patch failed: %v\n%s", err, out)
+	}
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != new {
+		t.Errorf("patched file =\n%q\nwant\n%q", got, new)
+	}
+}
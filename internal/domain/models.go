@@ -1,6 +1,11 @@
 // Package domain contains shared domain models and types used across the application.
 package domain
 
+import (
+	"os"
+	"strings"
+)
+
 // Project represents a project to be scaffolded.
 type Project struct {
 	Language  string
@@ -9,39 +14,124 @@ type Project struct {
 	Slug      string
 	Module    string
 	Dir       string
+	BaseDir   string // directory the project was requested in, above Dir
 	Libraries []string
+	Port      int    // port generated servers listen on; zero means DefaultPort
+	OpenAPI   bool   // add an openapi.yaml describing the server's endpoints
+	NPMScope  string // scope for the package.json name, e.g. "@acme"
+	Seed      uint64 // seed for random template values; zero means time-based
 }
 
+// DefaultPort is the port generated servers listen on unless one is chosen.
+const DefaultPort = 3000
+
 // Library represents an optional library that can be added to a project.
 type Library struct {
 	Name        string
 	Description string
+	// Deprecated, when set, says why the library should no longer be used.
+	Deprecated string
+	// ReplacedBy names the library, offered by the same framework, that is
+	// added instead of this one.
+	ReplacedBy string
+}
+
+// EnvVar is an environment variable the generated code reads, listed in
+// the generated .env.example.
+type EnvVar struct {
+	Name    string
+	Example string // placeholder value, e.g. "app.db"
+	Comment string // what the variable configures
 }
 
 // Template represents a file template to be generated.
 type Template struct {
 	RelativePath string
 	Content      string
+	// Mode, when non-zero, is the permission the generated file gets; see
+	// Action.Mode.
+	Mode os.FileMode
 }
 
 // Framework represents a project framework option.
 type Framework struct {
-	Language  string
-	Name      string
-	Templates []Template
-	Generator string
-	Libraries []Library
+	Language    string
+	Name        string
+	Description string // short summary shown in the wizard and --list
+	Recommended bool   // suggested starting point for the language, badged in the wizard
+	Templates   []Template
+	Generator   string
+	Requires    string // external tool the generator needs, e.g. "composer"
+	Libraries   []Library
+	// DefaultPort is the port the framework's server listens on unless one
+	// is chosen; zero means the package DefaultPort.
+	DefaultPort int
+	// Env lists the environment variables the framework's templates read.
+	// Examples are rendered like template content, e.g. "{{.Port}}".
+	Env []EnvVar
+	// Deprecated, when set, says why the framework should no longer be
+	// used. The wizard lists it last and --list marks it.
+	Deprecated string
+	// ReplacedBy names the framework, in the same language, that requests
+	// for this one are scaffolded with instead.
+	ReplacedBy string
+	// NextSteps are the commands to run in a new project, shown after it
+	// is created. They are rendered like template content, e.g.
+	// "{{.Port}}"; none means the language's usual steps.
+	NextSteps []string
 }
 
 // Action represents a file system action to be performed.
 type Action struct {
 	Path    string
 	Content string
+	// Mode is the file's permission, e.g. 0o755 for a script. Zero keeps
+	// the default: 0644 under the usual umask, or the configured
+	// filePermissions.
+	Mode os.FileMode
 }
 
 // Plan represents the complete scaffolding plan.
 type Plan struct {
 	ProjectDir string
-	Actions    []Action
-	Generator  string
+	// BaseDir is the directory the user chose; symlinks above it are trusted.
+	BaseDir   string
+	Actions   []Action
+	Generator string
+	// Warnings describe selections that work but need care, such as
+	// libraries whose generated code overlaps.
+	Warnings []string
+	// Port is the port the generated server listens on, or zero when the
+	// project does not start one.
+	Port int
+	// PostSteps are the commands run, in order, once the files are written,
+	// such as git init. Dry runs print them instead of running them.
+	PostSteps []CommandSpec
+}
+
+// CommandSpec is an external command and the directory it runs in.
+type CommandSpec struct {
+	// Kind says what the command is for, so callers can act on its result
+	// without parsing Args.
+	Kind CommandKind
+	Name string
+	Args []string
+	Dir  string
+	// Fallback runs in order when the command fails, e.g. a plain git init
+	// for git versions without "init -b". The command counts as failed if
+	// any of it fails.
+	Fallback []CommandSpec
+}
+
+// CommandKind identifies a post-create step.
+type CommandKind string
+
+const (
+	CommandGitInit   CommandKind = "gitInit"
+	CommandGitRemote CommandKind = "gitRemote"
+)
+
+// String returns the command line, e.g. "git init -b main".
+func (c CommandSpec) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
 }
@@ -9,6 +9,7 @@ import (
 // Common errors that can be checked with errors.Is.
 var (
 	ErrProjectExists = errors.New("project already exists")
+	ErrSymlinkedDir  = errors.New("refusing to write through a symlinked directory")
 )
 
 // ScaffoldError represents an error during scaffolding.
@@ -1,31 +1,346 @@
 package flags
 
-import "flag"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// AskFramework is the --framework value that ignores the configured default
+// and asks for a framework in the wizard.
+const AskFramework = "?"
 
 type Options struct {
-	ConfigPath string
-	Language   string
-	Framework  string
-	Name       string
-	Dir        string
-	DryRun     bool
-	NoTUI      bool
+	ConfigPath   string
+	Language     string
+	Framework    string
+	Name         string
+	Libraries    string // comma-separated, as given
+	Module       string
+	Dir          string
+	DryRun       bool
+	NoTUI        bool
+	Verbose      bool
+	Quiet        bool
+	List         bool
+	Branch       string
+	Remote       string
+	Monorepo     string
+	Flatten      bool
+	Force        bool
+	Yes          bool
+	SkipExisting bool
+	ASCII        bool
+	Output       string
+	Verify       bool
+	ShowFiles    bool
+	Transitions  string
+	Accessible   bool
+	NoClipboard  bool
+	Layout       string
+	Port         int
+	OpenAPI      bool
+	// CheckSpace checks the target volume's free space before writing.
+	CheckSpace bool
+	// CollapseDupes drops repeated neighbouring words from the slug.
+	CollapseDupes bool
+	// Seed fixes random template values; zero means a time-based seed.
+	Seed uint64
+	// EmitScript is where --emit-script writes the plan as a shell script,
+	// or "-" for stdout.
+	EmitScript string
+	// PrintDir prints the resolved project directory and exits without
+	// planning or writing anything.
+	PrintDir bool
+	// NoCreateDir is set by --create-dir=false: fail instead of creating a
+	// missing base directory.
+	NoCreateDir bool
+
+	IgnoreDisabled bool
+
+	ProfileExport string
+	ProfileImport string
 }
 
-func Parse(args []string) (Options, error) {
-	fs := flag.NewFlagSet("project-initiator", flag.ContinueOnError)
+// Flag describes a registered command-line flag for help text and the man
+// page.
+type Flag struct {
+	Name string
+	// Arg names the flag's value, e.g. "path"; empty for boolean flags.
+	Arg     string
+	Default string
+	Usage   string
+}
 
+func Parse(args []string) (Options, error) {
 	var opts Options
-	fs.StringVar(&opts.ConfigPath, "config", "", "Path to config file")
-	fs.StringVar(&opts.Language, "lang", "", "Language to scaffold")
-	fs.StringVar(&opts.Framework, "framework", "", "Framework to scaffold")
-	fs.StringVar(&opts.Name, "name", "", "Project name")
-	fs.StringVar(&opts.Dir, "dir", "", "Base directory for the new project")
+	fs, createDir := newFlagSet(&opts)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	opts.NoCreateDir = !*createDir
+	return opts, nil
+}
+
+// Definitions lists the main command's flags, sorted by name.
+func Definitions() []Flag {
+	fs, _ := newFlagSet(&Options{})
+	return definitions(fs)
+}
+
+// Changed returns the command-line words that reproduce opts's flags that
+// differ from their defaults, sorted by flag name: "--name value", "--name"
+// for a boolean set to true, or "--name=false" for one turned off.
+func Changed(opts Options) []string {
+	// Registering a flag resets it to its default, so fill in the values
+	// after.
+	var set Options
+	fs, createDir := newFlagSet(&set)
+	set, *createDir = opts, !opts.NoCreateDir
+	var args []string
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		switch {
+		case value == f.DefValue:
+		case isBool(f) && value == "true":
+			args = append(args, "--"+f.Name)
+		case isBool(f):
+			args = append(args, "--"+f.Name+"="+value)
+		default:
+			args = append(args, "--"+f.Name, value)
+		}
+	})
+	return args
+}
+
+// newFlagSet registers the main command's flags on a new FlagSet that
+// writes into opts. createDir holds --create-dir until it is inverted into
+// opts.NoCreateDir.
+func newFlagSet(opts *Options) (fs *flag.FlagSet, createDir *bool) {
+	fs = flag.NewFlagSet("project-initiator", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigPath, "config", "", "Config `files`, comma-separated and merged in order; changes are saved to the last")
+	fs.StringVar(&opts.Language, "lang", "", "`Language` to scaffold")
+	fs.StringVar(&opts.Framework, "framework", "", "`Framework` to scaffold, or ? to choose one in the wizard")
+	fs.StringVar(&opts.Name, "name", "", "Project `name`")
+	fs.StringVar(&opts.Libraries, "libraries", "", "Comma-separated Go `libraries` to add, e.g. gin,gorm; with --lang, --framework and --name the project is created without the wizard")
+	fs.StringVar(&opts.Module, "module", "", "Go `module` path (default: <host>/<owner>/<name> from the origin remote around --dir, else the name)")
+	fs.StringVar(&opts.Dir, "dir", "", "Base `directory` for the new project; {name} and {slug} are replaced by the project's")
+	fs.StringVar(&opts.Layout, "layout", "", "Project `layout`: by-language for <dir>/<Language>/<name> or flat for <dir>/<name> (overrides config)")
+	fs.IntVar(&opts.Port, "port", 0, "`Port` generated servers listen on (3000 if unset)")
+	fs.BoolVar(&opts.OpenAPI, "openapi", false, "Add an openapi.yaml describing the root and health endpoints (Gin, FastAPI and Express)")
 	fs.BoolVar(&opts.DryRun, "dry-run", false, "Print actions without writing files")
 	fs.BoolVar(&opts.NoTUI, "no-tui", false, "Disable TUI prompts")
+	createDir = fs.Bool("create-dir", true, "Create the base directory if it does not exist, asking first in interactive mode")
+	fs.BoolVar(&opts.SkipExisting, "skip-existing", false, "Leave files that already exist untouched instead of failing")
+	fs.BoolVar(&opts.ASCII, "ascii", false, "Use a plain text title for terminals without block glyphs")
+	fs.BoolVar(&opts.Accessible, "accessible", false, "High-contrast wizard with a plain title and no animation (also set by ACCESSIBLE)")
+	fs.BoolVar(&opts.NoClipboard, "no-clipboard", false, "Do not read the clipboard to suggest a project name in the wizard")
+	fs.StringVar(&opts.Transitions, "transitions", "", "Wizard animation `speed`: off, slow, normal or fast (overrides config)")
+	fs.BoolVar(&opts.Force, "force", false, "Overwrite existing files; dry runs report conflicts but still succeed")
+	fs.BoolVar(&opts.Yes, "yes", false, "With --force, overwrite changed files without showing their diff and asking")
+	fs.StringVar(&opts.Output, "output", "", "Output `format` of --dry-run and --list: text or json")
+	fs.StringVar(&opts.EmitScript, "emit-script", "", "Write the plan as a shell script to `path` (- for stdout) and exit without creating the project")
+	fs.BoolVar(&opts.PrintDir, "print-dir", false, "Print only the directory the project would be created in and exit")
+	fs.BoolVar(&opts.Flatten, "flatten", false, "Avoid nesting the project twice when --dir already ends in its name")
+	fs.StringVar(&opts.Branch, "branch", "", "Initial git `branch` name (main if unset)")
+	fs.StringVar(&opts.Remote, "remote", "", "Git remote `URL` to add as origin after init")
+	fs.StringVar(&opts.Monorepo, "monorepo", "", "Scaffold several projects from the wizard into this root `directory`")
+	fs.BoolVar(&opts.List, "list", false, "List available languages and frameworks and exit")
+	fs.BoolVar(&opts.IgnoreDisabled, "ignore-disabled", false, "Allow languages and frameworks disabled by config")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "Print only the project path on stdout; the name is logged to stderr")
+	fs.BoolVar(&opts.CollapseDupes, "collapse-dupes", false, "Collapse repeated neighbouring words in the slug, e.g. \"my my app\" to my-app")
+	fs.Uint64Var(&opts.Seed, "seed", 0, "Seed random template values, such as placeholder secrets, so runs produce identical files (0 for time-based)")
+	fs.BoolVar(&opts.CheckSpace, "check-space", false, "Check free space on the target volume first: fail if the files do not fit, warn below 100 MB left")
+	fs.BoolVar(&opts.Verify, "verify", false, "Run go build ./... in a new Go project to check that it compiles")
+	fs.BoolVar(&opts.ShowFiles, "show-files", false, "Print each file as it is written or skipped (implied by --verbose)")
+	fs.BoolVar(&opts.Verbose, "verbose", false, "Print extra details such as phase durations")
+	fs.StringVar(&opts.ProfileExport, "profile-export", "", "Write the current config to the given `path` and exit")
+	fs.StringVar(&opts.ProfileImport, "profile-import", "", "Load a config `file` and save it as the active config, then exit")
+	fs.Usage = func() { writeUsage(fs, "project-initiator [flags]") }
+	return fs, createDir
+}
+
+// BatchOptions holds the flags for the batch subcommand.
+type BatchOptions struct {
+	ConfigPath string
+	Dir        string
+	Output     string
+	Force      bool
+	Yes        bool
+	Manifest   string
+}
+
+// ParseBatch parses "batch [flags] <manifest>" arguments, excluding the
+// subcommand name itself.
+func ParseBatch(args []string) (BatchOptions, error) {
+	var opts BatchOptions
+	fs := newBatchFlagSet(&opts)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() != 1 {
+		return opts, errors.New("batch needs exactly one manifest path")
+	}
+	opts.Manifest = fs.Arg(0)
+	return opts, nil
+}
 
+// BatchDefinitions lists the batch subcommand's flags.
+func BatchDefinitions() []Flag {
+	return definitions(newBatchFlagSet(&BatchOptions{}))
+}
+
+func newBatchFlagSet(opts *BatchOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("project-initiator batch", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigPath, "config", "", "Config `files`, comma-separated and merged in order; changes are saved to the last")
+	fs.StringVar(&opts.Dir, "dir", "", "Base `directory` for projects that do not set one")
+	fs.StringVar(&opts.Output, "output", "", "Progress output `format`: text or json")
+	fs.BoolVar(&opts.Force, "force", false, "Overwrite existing files; a project whose files would change fails unless --yes is given")
+	fs.BoolVar(&opts.Yes, "yes", false, "With --force, overwrite changed files without showing their diff")
+	fs.Usage = func() { writeUsage(fs, "project-initiator batch [flags] <manifest>") }
+	return fs
+}
+
+// ManOptions holds the flags for the man subcommand.
+type ManOptions struct {
+	Format string
+}
+
+// ParseMan parses "man [flags]" arguments, excluding the subcommand name.
+func ParseMan(args []string) (ManOptions, error) {
+	var opts ManOptions
+	fs := newManFlagSet(&opts)
 	if err := fs.Parse(args); err != nil {
 		return opts, err
 	}
+	if fs.NArg() != 0 {
+		return opts, errors.New("man takes no arguments")
+	}
 	return opts, nil
 }
+
+// ManDefinitions lists the man subcommand's flags.
+func ManDefinitions() []Flag {
+	return definitions(newManFlagSet(&ManOptions{}))
+}
+
+func newManFlagSet(opts *ManOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("project-initiator man", flag.ContinueOnError)
+	fs.StringVar(&opts.Format, "format", "roff", "Output `format`: roff or md")
+	fs.Usage = func() { writeUsage(fs, "project-initiator man [flags]") }
+	return fs
+}
+
+// ServeOptions holds the flags for the serve subcommand.
+type ServeOptions struct {
+	ConfigPath string
+	Stdio      bool
+}
+
+// ParseServe parses "serve [flags]" arguments, excluding the subcommand
+// name. --stdio is required, as it is the only transport.
+func ParseServe(args []string) (ServeOptions, error) {
+	var opts ServeOptions
+	fs := newServeFlagSet(&opts)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() != 0 {
+		return opts, errors.New("serve takes no arguments")
+	}
+	if !opts.Stdio {
+		return opts, errors.New("serve needs --stdio")
+	}
+	return opts, nil
+}
+
+// ServeDefinitions lists the serve subcommand's flags.
+func ServeDefinitions() []Flag {
+	return definitions(newServeFlagSet(&ServeOptions{}))
+}
+
+func newServeFlagSet(opts *ServeOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("project-initiator serve", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigPath, "config", "", "Config `files`, comma-separated and merged in order")
+	fs.BoolVar(&opts.Stdio, "stdio", false, "Read newline-delimited JSON requests from stdin and write responses to stdout")
+	fs.Usage = func() { writeUsage(fs, "project-initiator serve --stdio [flags]") }
+	return fs
+}
+
+// definitions describes every flag registered on fs, sorted by name.
+func definitions(fs *flag.FlagSet) []Flag {
+	var defs []Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		if isBool(f) {
+			arg = ""
+		}
+		defs = append(defs, Flag{Name: f.Name, Arg: arg, Default: f.DefValue, Usage: usage})
+	})
+	return defs
+}
+
+func isBool(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// writeUsage prints the -h help from the same definitions as the man page.
+func writeUsage(fs *flag.FlagSet, synopsis string) {
+	WriteUsage(fs.Output(), synopsis, definitions(fs))
+}
+
+// WriteUsage prints a usage line followed by one entry per flag.
+func WriteUsage(w io.Writer, synopsis string, defs []Flag) {
+	_, _ = fmt.Fprintf(w, "Usage: %s\n\nFlags:\n", synopsis)
+	for _, def := range defs {
+		name := "--" + def.Name
+		if def.Arg != "" {
+			name += " " + def.Arg
+		}
+		_, _ = fmt.Fprintf(w, "  %s\n    \t%s", name, def.Usage)
+		if def.Default != "" && def.Default != "false" {
+			_, _ = fmt.Fprintf(w, " (default %s)", def.Default)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// ConfigOptions holds the flags and arguments for the config subcommand.
+type ConfigOptions struct {
+	ConfigPath string
+	// Args is "get <key>", "set <key> <value>", or empty to list every key.
+	Args []string
+}
+
+// ParseConfig parses "config [flags] [get <key> | set <key> <value>]"
+// arguments, excluding the subcommand name.
+func ParseConfig(args []string) (ConfigOptions, error) {
+	var opts ConfigOptions
+	fs := newConfigFlagSet(&opts)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	opts.Args = fs.Args()
+	switch {
+	case len(opts.Args) == 0:
+	case opts.Args[0] == "get" && len(opts.Args) == 2:
+	case opts.Args[0] == "set" && len(opts.Args) == 3:
+	default:
+		return opts, errors.New("config takes get <key> or set <key> <value>")
+	}
+	return opts, nil
+}
+
+// ConfigDefinitions lists the config subcommand's flags.
+func ConfigDefinitions() []Flag {
+	return definitions(newConfigFlagSet(&ConfigOptions{}))
+}
+
+func newConfigFlagSet(opts *ConfigOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("project-initiator config", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigPath, "config", "", "Config `files`, comma-separated and merged in order; changes are saved to the last")
+	fs.Usage = func() { writeUsage(fs, "project-initiator config [flags] [get <key> | set <key> <value>]") }
+	return fs
+}
@@ -1,6 +1,9 @@
 package flags
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestParse(t *testing.T) {
 	tests := []struct {
@@ -50,6 +53,16 @@ func TestParse(t *testing.T) {
 			args: []string{"--name", "cool-app"},
 			want: Options{Name: "cool-app"},
 		},
+		{
+			name: "libraries flag only",
+			args: []string{"--libraries", "gin,gorm"},
+			want: Options{Libraries: "gin,gorm"},
+		},
+		{
+			name: "module flag only",
+			args: []string{"--module", "github.com/acme/api"},
+			want: Options{Module: "github.com/acme/api"},
+		},
 		{
 			name: "dir flag only",
 			args: []string{"--dir", "/home/user/projects"},
@@ -70,6 +83,151 @@ func TestParse(t *testing.T) {
 			args: []string{"--config", "config.yaml"},
 			want: Options{ConfigPath: "config.yaml"},
 		},
+		{
+			name: "list flag only",
+			args: []string{"--list"},
+			want: Options{List: true},
+		},
+		{
+			name: "ignore-disabled flag only",
+			args: []string{"--ignore-disabled"},
+			want: Options{IgnoreDisabled: true},
+		},
+		{
+			name: "branch flag only",
+			args: []string{"--branch", "trunk"},
+			want: Options{Branch: "trunk"},
+		},
+		{
+			name: "remote flag only",
+			args: []string{"--remote", "git@github.com:acme/app.git"},
+			want: Options{Remote: "git@github.com:acme/app.git"},
+		},
+		{
+			name: "monorepo flag only",
+			args: []string{"--monorepo", "platform"},
+			want: Options{Monorepo: "platform"},
+		},
+		{
+			name: "flatten flag only",
+			args: []string{"--flatten"},
+			want: Options{Flatten: true},
+		},
+		{
+			name: "force flag only",
+			args: []string{"--force"},
+			want: Options{Force: true},
+		},
+		{
+			name: "yes flag only",
+			args: []string{"--yes"},
+			want: Options{Yes: true},
+		},
+		{
+			name: "output flag only",
+			args: []string{"--output", "json"},
+			want: Options{Output: "json"},
+		},
+		{
+			name: "ascii flag only",
+			args: []string{"--ascii"},
+			want: Options{ASCII: true},
+		},
+		{
+			name: "skip-existing flag only",
+			args: []string{"--skip-existing"},
+			want: Options{SkipExisting: true},
+		},
+		{
+			name: "quiet flag only",
+			args: []string{"--quiet"},
+			want: Options{Quiet: true},
+		},
+		{
+			name: "verify flag only",
+			args: []string{"--verify"},
+			want: Options{Verify: true},
+		},
+		{
+			name: "show-files flag only",
+			args: []string{"--show-files"},
+			want: Options{ShowFiles: true},
+		},
+		{
+			name: "layout flag only",
+			args: []string{"--layout", "flat"},
+			want: Options{Layout: "flat"},
+		},
+		{
+			name: "port flag only",
+			args: []string{"--port", "8080"},
+			want: Options{Port: 8080},
+		},
+		{
+			name: "openapi flag only",
+			args: []string{"--openapi"},
+			want: Options{OpenAPI: true},
+		},
+		{
+			name: "check-space flag only",
+			args: []string{"--check-space"},
+			want: Options{CheckSpace: true},
+		},
+		{
+			name: "seed",
+			args: []string{"--seed", "42"},
+			want: Options{Seed: 42},
+		},
+		{
+			name: "collapse-dupes",
+			args: []string{"--collapse-dupes"},
+			want: Options{CollapseDupes: true},
+		},
+		{
+			name: "accessible flag only",
+			args: []string{"--accessible"},
+			want: Options{Accessible: true},
+		},
+		{
+			name: "no clipboard flag only",
+			args: []string{"--no-clipboard"},
+			want: Options{NoClipboard: true},
+		},
+		{
+			name: "transitions flag only",
+			args: []string{"--transitions", "off"},
+			want: Options{Transitions: "off"},
+		},
+		{
+			name: "print-dir flag only",
+			args: []string{"--print-dir"},
+			want: Options{PrintDir: true},
+		},
+		{
+			name: "create-dir disabled",
+			args: []string{"--create-dir=false"},
+			want: Options{NoCreateDir: true},
+		},
+		{
+			name: "create-dir explicitly enabled",
+			args: []string{"--create-dir"},
+			want: Options{},
+		},
+		{
+			name: "verbose flag only",
+			args: []string{"--verbose"},
+			want: Options{Verbose: true},
+		},
+		{
+			name: "profile export flag",
+			args: []string{"--profile-export", "team.json"},
+			want: Options{ProfileExport: "team.json"},
+		},
+		{
+			name: "profile import flag",
+			args: []string{"--profile-import", "team.json"},
+			want: Options{ProfileImport: "team.json"},
+		},
 		{
 			name:    "invalid flag returns error",
 			args:    []string{"--nonexistent", "value"},
@@ -117,3 +275,146 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    BatchOptions
+		wantErr bool
+	}{
+		{
+			name: "manifest only",
+			args: []string{"services.json"},
+			want: BatchOptions{Manifest: "services.json"},
+		},
+		{
+			name: "flags before manifest",
+			args: []string{"--output", "json", "--dir", "/tmp/out", "--force", "--yes", "services.json"},
+			want: BatchOptions{Output: "json", Dir: "/tmp/out", Force: true, Yes: true, Manifest: "services.json"},
+		},
+		{
+			name:    "missing manifest",
+			args:    []string{"--force"},
+			wantErr: true,
+		},
+		{
+			name:    "extra arguments",
+			args:    []string{"a.json", "b.json"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBatch(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseBatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	defs := map[string]Flag{}
+	for _, def := range Definitions() {
+		defs[def.Name] = def
+	}
+
+	tests := []struct {
+		name string
+		want Flag
+	}{
+		{name: "config", want: Flag{Name: "config", Arg: "files", Usage: "Config files, comma-separated and merged in order; changes are saved to the last"}},
+		{name: "dry-run", want: Flag{Name: "dry-run", Default: "false", Usage: "Print actions without writing files"}},
+		{name: "create-dir", want: Flag{Name: "create-dir", Default: "true", Usage: "Create the base directory if it does not exist, asking first in interactive mode"}},
+	}
+	for _, tt := range tests {
+		if got := defs[tt.name]; got != tt.want {
+			t.Errorf("Definitions()[%q] = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if len(defs) < 25 {
+		t.Errorf("Definitions() has %d flags, want every registered flag", len(defs))
+	}
+}
+
+func TestChanged(t *testing.T) {
+	args := []string{"--remote", "git@github.com:acme/app.git", "--port", "8080", "--force", "--create-dir=false", "--name", "my app"}
+	opts, err := Parse(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := Changed(opts)
+	want := []string{"--create-dir=false", "--force", "--name", "my app", "--port", "8080", "--remote", "git@github.com:acme/app.git"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Changed() = %q, want %q", got, want)
+	}
+	if reparsed, err := Parse(got); err != nil || reparsed != opts {
+		t.Errorf("Parse(Changed()) = %+v, %v; want %+v", reparsed, err, opts)
+	}
+	if got := Changed(Options{}); len(got) != 0 {
+		t.Errorf("Changed(Options{}) = %q, want none", got)
+	}
+}
+
+func TestParseServe(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    ServeOptions
+		wantErr bool
+	}{
+		{name: "stdio", args: []string{"--stdio"}, want: ServeOptions{Stdio: true}},
+		{name: "with config", args: []string{"--config", "c.json", "--stdio"}, want: ServeOptions{ConfigPath: "c.json", Stdio: true}},
+		{name: "missing stdio", args: []string{}, wantErr: true},
+		{name: "extra arguments", args: []string{"--stdio", "now"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServe(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseServe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseServe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "list", args: []string{}},
+		{name: "get", args: []string{"get", "defaultDir"}, want: []string{"get", "defaultDir"}},
+		{name: "set with config path", args: []string{"--config", "c.json", "set", "pinned", "Go/Cobra"}, want: []string{"set", "pinned", "Go/Cobra"}},
+		{name: "get without key", args: []string{"get"}, wantErr: true},
+		{name: "set without value", args: []string{"set", "pinned"}, wantErr: true},
+		{name: "unknown action", args: []string{"unset", "pinned"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConfig(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got.Args, tt.want) {
+				t.Errorf("ParseConfig() args = %q, want %q", got.Args, tt.want)
+			}
+		})
+	}
+}
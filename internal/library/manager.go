@@ -3,6 +3,8 @@ package library
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"project-initiator/internal/domain"
@@ -10,12 +12,19 @@ import (
 
 // Manager handles library-specific code generation.
 type Manager struct {
-	data domain.Project
+	data  domain.Project
+	specs []Spec
 }
 
 // NewManager creates a new library manager for the given project.
 func NewManager(data domain.Project) *Manager {
-	return &Manager{data: data}
+	return NewManagerWithSpecs(data, Specs)
+}
+
+// NewManagerWithSpecs creates a library manager that knows the given specs
+// instead of the built-in ones.
+func NewManagerWithSpecs(data domain.Project, specs []Spec) *Manager {
+	return &Manager{data: data, specs: specs}
 }
 
 // HasLibrary checks if a library is included.
@@ -29,7 +38,63 @@ func (m *Manager) HasLibrary(name string) bool {
 	return false
 }
 
-// GenerateReadme generates a README with library information.
+// Selected returns the specs of the project's selected libraries, in spec order.
+func (m *Manager) Selected() []Spec {
+	var selected []Spec
+	for _, spec := range m.specs {
+		if m.HasLibrary(spec.Name) {
+			selected = append(selected, spec)
+		}
+	}
+	return selected
+}
+
+// HasAny reports whether any selected library has a spec.
+func (m *Manager) HasAny() bool {
+	return len(m.Selected()) > 0
+}
+
+// Serves reports whether the generated main starts a server.
+func (m *Manager) Serves() bool {
+	return m.HasLibrary("gin") || m.HasLibrary("graphql")
+}
+
+// EnvVars returns the environment variables the selected libraries read,
+// in spec order. A variable read by several libraries is listed once, as
+// the first of them describes it.
+func (m *Manager) EnvVars() []domain.EnvVar {
+	var vars []domain.EnvVar
+	seen := map[string]bool{}
+	for _, spec := range m.Selected() {
+		if spec.Env == nil {
+			continue
+		}
+		for _, v := range spec.Env(m.data) {
+			if !seen[v.Name] {
+				seen[v.Name] = true
+				vars = append(vars, v)
+			}
+		}
+	}
+	return vars
+}
+
+// Warnings returns a warning for each pair of selected libraries whose
+// specs say they overlap.
+func (m *Manager) Warnings() []string {
+	var warnings []string
+	for _, spec := range m.Selected() {
+		for _, other := range slices.Sorted(maps.Keys(spec.Overlaps)) {
+			if m.HasLibrary(other) {
+				warnings = append(warnings, spec.Overlaps[other])
+			}
+		}
+	}
+	return warnings
+}
+
+// GenerateReadme generates a README listing the selected libraries, followed
+// by each library's usage section.
 func (m *Manager) GenerateReadme() string {
 	lines := []string{
 		"# " + m.data.Name,
@@ -38,15 +103,15 @@ func (m *Manager) GenerateReadme() string {
 		"",
 		"Included libraries:",
 	}
-	if m.HasLibrary("gin") {
-		lines = append(lines, "- Gin")
+	selected := m.Selected()
+	for _, spec := range selected {
+		lines = append(lines, "- "+spec.Title)
 	}
-	if m.HasLibrary("gorm") {
-		lines = append(lines, "- Gorm")
-	}
-	if m.HasLibrary("sqlc") {
-		lines = append(lines, "- Sqlc")
-		lines = append(lines, "", "Run: `sqlc generate`")
+	for _, spec := range selected {
+		if spec.Readme == nil {
+			continue
+		}
+		lines = append(lines, "", strings.TrimRight(spec.Readme(m.data), "\n"))
 	}
 	lines = append(lines, "")
 	return strings.Join(lines, "\n")
@@ -61,12 +126,10 @@ func (m *Manager) GenerateGoMod(goVersion string) string {
 		"",
 		"require (",
 	}
-	if m.HasLibrary("gin") {
-		lines = append(lines, "\tgithub.com/gin-gonic/gin v1.10.0")
-	}
-	if m.HasLibrary("gorm") {
-		lines = append(lines, "\tgorm.io/driver/sqlite v1.5.7")
-		lines = append(lines, "\tgorm.io/gorm v1.25.12")
+	for _, spec := range m.Selected() {
+		for _, req := range spec.Requires {
+			lines = append(lines, "\t"+req)
+		}
 	}
 	lines = append(lines, ")")
 	return strings.Join(lines, "\n") + "\n"
@@ -75,12 +138,23 @@ func (m *Manager) GenerateGoMod(goVersion string) string {
 // GenerateMain generates the main.go file with library imports and setup.
 func (m *Manager) GenerateMain(framework string) string {
 	imports := []string{"\"fmt\""}
+	if m.Serves() {
+		imports = append(imports, "\"os\"")
+	}
 	if m.HasLibrary("gin") {
 		imports = append(imports, fmt.Sprintf("\"%s/internal/http\"", m.data.Module))
 	}
 	if m.HasLibrary("gorm") {
 		imports = append(imports, fmt.Sprintf("\"%s/internal/db\"", m.data.Module))
 	}
+	if m.HasLibrary("graphql") {
+		if m.HasLibrary("gin") {
+			imports = append(imports, "\"github.com/gin-gonic/gin\"")
+		} else {
+			imports = append(imports, "\"net/http\"")
+		}
+		imports = append(imports, fmt.Sprintf("\"%s/graph\"", m.data.Module))
+	}
 
 	body := []string{}
 	body = append(body, "func run() error {")
@@ -98,7 +172,18 @@ func (m *Manager) GenerateMain(framework string) string {
 		if m.HasLibrary("gorm") {
 			body = append(body, "\t_ = dbConn")
 		}
-		body = append(body, "\treturn server.Run(\":3000\")")
+		if m.HasLibrary("graphql") {
+			body = append(body, "\tserver.Any(\"/query\", gin.WrapH(graph.Handler()))")
+			body = append(body, "\tserver.GET(\"/playground\", gin.WrapH(graph.Playground()))")
+		}
+		body = append(body, m.readPort())
+		body = append(body, "\treturn server.Run(\":\" + port)")
+	} else if m.HasLibrary("graphql") {
+		body = append(body, "\tmux := http.NewServeMux()")
+		body = append(body, "\tmux.Handle(\"/query\", graph.Handler())")
+		body = append(body, "\tmux.Handle(\"/playground\", graph.Playground())")
+		body = append(body, m.readPort())
+		body = append(body, "\treturn http.ListenAndServe(\":\"+port, mux)")
 	} else {
 		body = append(body, "\treturn nil")
 	}
@@ -121,31 +206,31 @@ func (m *Manager) GenerateMain(framework string) string {
 	return strings.Join(code, "\n")
 }
 
+// readPort is the main.go code that reads PORT, falling back to the
+// project's port.
+func (m *Manager) readPort() string {
+	return fmt.Sprintf("\tport := os.Getenv(\"PORT\")\n\tif port == \"\" {\n\t\tport = \"%d\"\n\t}", listenPort(m.data))
+}
+
 // FileTemplates returns additional file templates for libraries.
 func (m *Manager) FileTemplates() map[string]string {
 	templates := make(map[string]string)
-
-	if m.HasLibrary("gin") {
-		templates["internal/http/server.go"] = goGinServer
-		templates["internal/http/routes.go"] = fmt.Sprintf(goGinRoutesTemplate, m.data.Name)
-	}
-	if m.HasLibrary("gorm") {
-		templates["internal/db/db.go"] = goGormDB
-		templates["internal/db/models.go"] = goGormModels
-	}
-	if m.HasLibrary("sqlc") {
-		templates["sqlc.yaml"] = goSqlcConfig
-		templates["db/schema.sql"] = goSqlcSchema
-		templates["db/query.sql"] = goSqlcQuery
-		templates["internal/db/README.md"] = goSqlcReadme
+	for _, spec := range m.Selected() {
+		if spec.Files == nil {
+			continue
+		}
+		for path, content := range spec.Files(m.data) {
+			templates[path] = content
+		}
 	}
-
 	return templates
 }
 
-// ReplacedFiles returns the set of files that should be replaced when using libraries.
+// ReplacedFiles returns the set of files that should be replaced when using
+// libraries: main.go, go.mod, README.md and any template file a selected
+// library writes its own version of.
 func (m *Manager) ReplacedFiles(projectSlug string) map[string]bool {
-	if !m.HasLibrary("gin") && !m.HasLibrary("gorm") && !m.HasLibrary("sqlc") {
+	if !m.HasAny() {
 		return nil
 	}
 
@@ -154,17 +239,17 @@ func (m *Manager) ReplacedFiles(projectSlug string) map[string]bool {
 		"README.md": true,
 	}
 
-	// Main file location depends on framework
-	if strings.EqualFold(m.data.Framework, "cobra") {
-		replaced[fmt.Sprintf("cmd/%s/main.go", projectSlug)] = true
-	} else {
-		replaced["main.go"] = true
+	project := m.data
+	project.Slug = projectSlug
+	replaced[MainFile(project)] = true
+	for path := range m.FileTemplates() {
+		replaced[path] = true
 	}
 
 	return replaced
 }
 
-const goGinServer = `package http
+const goGinServerTemplate = `package http
 
 import (
 	"net/http"
@@ -175,7 +260,7 @@ import (
 func NewServer() *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Recovery())
-
+%s
 	RegisterRoutes(router)
 
 	router.GET("/health", func(c *gin.Context) {
@@ -198,18 +283,62 @@ func RegisterRoutes(router *gin.Engine) {
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "hello from %s"})
 	})
+%s}
+`
+
+const goGinCors = `package http
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAllowedOrigins is used when CORS_ALLOWED_ORIGINS is unset.
+var defaultAllowedOrigins = []string{"http://localhost:5173"}
+
+func corsMiddleware() gin.HandlerFunc {
+	config := cors.DefaultConfig()
+	config.AllowOrigins = allowedOrigins()
+	config.AllowHeaders = append(config.AllowHeaders, "Authorization")
+	config.MaxAge = 12 * time.Hour
+	return cors.New(config)
+}
+
+// allowedOrigins reads the comma-separated CORS_ALLOWED_ORIGINS.
+func allowedOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return defaultAllowedOrigins
+	}
+	return origins
 }
 `
 
 const goGormDB = `package db
 
 import (
+	"os"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// Open opens the SQLite database named by DATABASE_URL, or {{.File}}.
 func Open() (*gorm.DB, error) {
-	return gorm.Open(sqlite.Open("app.db"), &gorm.Config{})
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = {{quote .File}}
+	}
+	return gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 }
 `
 
@@ -255,3 +384,287 @@ const goSqlcReadme = `# SQLC
 
 Run ` + "`" + `sqlc generate` + "`" + ` to generate Go code into internal/db.
 `
+
+const goOpenAPISpec = `openapi: 3.0.3
+info:
+  title: {{quote .Name}}
+  description: {{quote (printf "%s API generated by project-initiator." .Name)}}
+  version: 0.1.0
+paths:
+  /health:
+    get:
+      operationId: getHealth
+      responses:
+        "200":
+          description: The service is healthy.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Health"
+  /{{.Resource}}:
+    get:
+      operationId: list{{.Schema}}
+      responses:
+        "200":
+          description: All {{.Schema}} records.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/{{.Schema}}"
+  /{{.Resource}}/{id}:
+    get:
+      operationId: get{{.Schema}}
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+            format: int64
+      responses:
+        "200":
+          description: The {{.Schema}} record.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/{{.Schema}}"
+        "404":
+          description: No record has this id.
+components:
+  schemas:
+    Health:
+      type: object
+      required:
+        - status
+      properties:
+        status:
+          type: string
+          example: ok
+    {{.Schema}}:
+      type: object
+      required:
+        - id
+        - name
+      properties:
+        id:
+          type: integer
+          format: int64
+        name:
+          type: string
+`
+
+const goOpenAPICodegen = `package: api
+output: internal/api/api.gen.go
+generate:
+  models: true
+{{- if .Gin}}
+  gin-server: true
+{{- else}}
+  std-http-server: true
+{{- end}}
+  embedded-spec: true
+`
+
+const goOpenAPIMakefile = `.PHONY: generate{{if .Air}} dev{{end}}
+
+generate:
+	go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 --config oapi-codegen.yaml api/openapi.yaml
+{{- if .Air}}
+
+dev:
+	air
+{{- end}}
+`
+
+const goOpenAPIDoc = `// Package api holds the server stubs generated from api/openapi.yaml.
+// Run "make generate" to create api.gen.go.
+package api
+`
+
+const goGqlgenConfig = `schema:
+  - graph/*.graphqls
+
+exec:
+  filename: graph/generated.go
+  package: graph
+
+model:
+  filename: graph/model/models_gen.go
+  package: model
+
+resolver:
+  layout: follow-schema
+  dir: graph
+  package: graph
+  filename_template: "{name}.resolvers.go"
+`
+
+const goGraphQLSchema = `type {{.Type}} {
+  id: ID!
+  name: String!
+}
+
+type Query {
+  {{.Field}}(id: ID!): {{.Type}}
+  {{.Field}}List: [{{.Type}}!]!
+}
+`
+
+const goGraphQLResolver = `package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+// Resolver is the root resolver. Add the dependencies your resolvers need,
+// such as a database handle, as fields. gqlgen writes the resolver stubs
+// into schema.resolvers.go and never regenerates this file.
+type Resolver struct{}
+`
+
+const goGraphQLHandler = `package graph
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/playground"
+)
+
+// Handler serves GraphQL queries. NewExecutableSchema is generated by
+// "go generate ./graph".
+func Handler() http.Handler {
+	return handler.NewDefaultServer(NewExecutableSchema(Config{Resolvers: &Resolver{}}))
+}
+
+// Playground serves an in-browser IDE for the /query endpoint.
+func Playground() http.Handler {
+	return playground.Handler({{quote .Name}}, "/query")
+}
+`
+
+const goAirConfig = `root = "."
+tmp_dir = "tmp"
+
+[build]
+  cmd = {{quote (printf "go build -o ./tmp/%s %s" .Slug .Package)}}
+  entrypoint = ["./tmp/{{.Slug}}"]
+  include_ext = ["go", "tpl", "tmpl", "html"]
+  exclude_dir = ["tmp", "vendor"]
+  delay = 500
+
+[misc]
+  clean_on_exit = true
+`
+
+const goTestifyAppTestTemplate = `package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"%s/internal/testhelpers"
+)
+
+func TestRun(t *testing.T) {
+	require.NoError(t, Run())
+}
+
+func TestTempDir(t *testing.T) {
+	dir := testhelpers.TempDir(t, map[string]string{"config/app.txt": "hello"})
+
+	content, err := os.ReadFile(filepath.Join(dir, "config", "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+`
+
+const goTestifyGinTestTemplate = `package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	NewServer().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "ok", body["status"])
+}
+%s`
+
+const goTestifyCorsTest = `
+func TestCORSAllowsConfiguredOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	recorder := httptest.NewRecorder()
+	NewServer().ServeHTTP(recorder, request)
+
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+`
+
+const goTestHelpers = `// Package testhelpers holds fixtures shared by the project's tests.
+package testhelpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TempDir creates a temporary directory holding files, keyed by
+// slash-separated path, and returns its path. It is removed when the test
+// ends.
+func TempDir(t testing.TB, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+`
+
+// goVersionTemplate takes the module path.
+const goVersionTemplate = `// Package version describes the running build. The variables are set at
+// link time, e.g.
+//
+//	go build -ldflags "-X %[1]s/internal/version.Version=v1.0.0"
+package version
+
+var (
+	// Version is the release, such as a git tag.
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = "none"
+	// BuildDate is when the binary was built, in RFC 3339.
+	BuildDate = "unknown"
+)
+
+// String formats the build info, e.g. "v1.0.0 (3f2c1ab, 2026-01-02T15:04:05Z)".
+func String() string {
+	return Version + " (" + Commit + ", " + BuildDate + ")"
+}
+`
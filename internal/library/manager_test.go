@@ -0,0 +1,538 @@
+package library
+
+import (
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"project-initiator/internal/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// ---------------------------------------------------------------------------
+// README golden files
+// ---------------------------------------------------------------------------
+
+func TestGenerateReadme_Golden(t *testing.T) {
+	custom := Spec{
+		Name:  "chi",
+		Title: "Chi",
+		Readme: func(project domain.Project) string {
+			return "## Chi\n\nMount the router from `" + project.Module + "/internal/router`.\n"
+		},
+	}
+
+	tests := []struct {
+		golden    string
+		framework string
+		libraries []string
+		specs     []Spec
+	}{
+		{golden: "gin", libraries: []string{"gin"}},
+		{golden: "gorm", libraries: []string{"gorm"}},
+		{golden: "sqlc", libraries: []string{"sqlc"}},
+		{golden: "gin_gorm", libraries: []string{"gin", "gorm"}},
+		{golden: "gin_cors", libraries: []string{"cors", "gin"}},
+		{golden: "gorm_sqlc", libraries: []string{"gorm", "sqlc"}},
+		{golden: "gin_gorm_sqlc", libraries: []string{"sqlc", "gin", "gorm"}},
+		{golden: "cobra_gin", framework: "Cobra", libraries: []string{"gin"}},
+		{golden: "openapi", libraries: []string{"openapi"}},
+		{golden: "gin_openapi", libraries: []string{"openapi", "gin"}},
+		{golden: "graphql", libraries: []string{"graphql"}},
+		{golden: "air", libraries: []string{"air"}},
+		{golden: "cobra_air", framework: "Cobra", libraries: []string{"air"}},
+		{golden: "air_openapi", libraries: []string{"air", "openapi"}},
+		{golden: "testify", libraries: []string{"testify"}},
+		{golden: "custom", libraries: []string{"gin", "chi"}, specs: append(append([]Spec{}, Specs...), custom)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			project := domain.Project{
+				Language:  "Go",
+				Framework: tt.framework,
+				Name:      "demo",
+				Slug:      "demo",
+				Module:    "example.com/demo",
+				Libraries: tt.libraries,
+			}
+			specs := tt.specs
+			if specs == nil {
+				specs = Specs
+			}
+			got := NewManagerWithSpecs(project, specs).GenerateReadme()
+
+			path := filepath.Join("testdata", "readme_"+tt.golden+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("README mismatch for %s (run with -update to accept)\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Specs
+// ---------------------------------------------------------------------------
+
+func TestManager_CustomSpec(t *testing.T) {
+	custom := Spec{
+		Name:     "Chi",
+		Title:    "Chi",
+		Requires: []string{"github.com/go-chi/chi/v5 v5.1.0"},
+		Files: func(domain.Project) map[string]string {
+			return map[string]string{"internal/router/router.go": "package router\n"}
+		},
+	}
+	m := NewManagerWithSpecs(domain.Project{Module: "example.com/demo", Libraries: []string{"chi"}}, []Spec{custom})
+
+	if !m.HasAny() {
+		t.Fatal("HasAny() = false, want true for a selected custom library")
+	}
+	if got := m.GenerateGoMod("1.25"); !strings.Contains(got, "\tgithub.com/go-chi/chi/v5 v5.1.0\n") {
+		t.Errorf("go.mod missing custom require:\n%s", got)
+	}
+	if _, ok := m.FileTemplates()["internal/router/router.go"]; !ok {
+		t.Errorf("FileTemplates() = %v, want the custom file", m.FileTemplates())
+	}
+	if got := m.GenerateReadme(); !strings.Contains(got, "- Chi") {
+		t.Errorf("README missing custom library:\n%s", got)
+	}
+}
+
+func TestManager_EnvVars(t *testing.T) {
+	custom := Spec{
+		Name: "pgx",
+		Env: func(domain.Project) []domain.EnvVar {
+			return []domain.EnvVar{
+				{Name: "DATABASE_URL", Example: "postgres://localhost/demo"},
+				{Name: "PGX_POOL_SIZE", Example: "10"},
+			}
+		},
+	}
+	specs := append(append([]Spec{}, Specs...), custom)
+
+	tests := []struct {
+		name      string
+		libraries []string
+		port      int
+		want      []string
+	}{
+		{name: "none", libraries: []string{"sqlc", "testify"}},
+		{name: "gin on the chosen port", libraries: []string{"gin"}, port: 8080, want: []string{"PORT=8080"}},
+		{name: "gorm", libraries: []string{"gorm"}, want: []string{"DATABASE_URL=app.db"}},
+		{
+			name:      "union in spec order",
+			libraries: []string{"gorm", "cors", "gin"},
+			want:      []string{"PORT=3000", "CORS_ALLOWED_ORIGINS=http://localhost:5173", "DATABASE_URL=app.db"},
+		},
+		{
+			name:      "shared variable listed once",
+			libraries: []string{"pgx", "gorm", "graphql", "gin"},
+			want:      []string{"PORT=3000", "DATABASE_URL=app.db", "PGX_POOL_SIZE=10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range NewManagerWithSpecs(domain.Project{Libraries: tt.libraries, Port: tt.port}, specs).EnvVars() {
+				got = append(got, v.Name+"="+v.Example)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("EnvVars() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_UnknownLibraryIgnored(t *testing.T) {
+	m := NewManager(domain.Project{Libraries: []string{"left-pad"}})
+	if m.HasAny() {
+		t.Error("HasAny() = true, want false when no selected library has a spec")
+	}
+	if replaced := m.ReplacedFiles("demo"); replaced != nil {
+		t.Errorf("ReplacedFiles() = %v, want nil", replaced)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// OpenAPI
+// ---------------------------------------------------------------------------
+
+func TestOpenAPI_SpecParses(t *testing.T) {
+	tests := []struct {
+		name       string
+		slug       string
+		wantPath   string
+		wantSchema string
+	}{
+		{name: "demo", slug: "demo", wantPath: "/demo", wantSchema: "Demo"},
+		{name: "Order Service", slug: "order-service", wantPath: "/order-service", wantSchema: "OrderService"},
+		{name: `Ops: "beta" #2`, slug: "ops-beta-2", wantPath: "/ops-beta-2", wantSchema: "OpsBeta2"},
+		{name: "2fa", slug: "2fa", wantPath: "/2fa", wantSchema: "Item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			project := domain.Project{Name: tt.name, Slug: tt.slug, Module: "example.com/" + tt.slug, Libraries: []string{"openapi"}}
+			files := NewManager(project).FileTemplates()
+			doc := parseYAML(t, files["api/openapi.yaml"])
+
+			if got := yamlValue(t, doc, "info", "title"); got != tt.name {
+				t.Errorf("info.title = %q, want %q", got, tt.name)
+			}
+			if got := yamlValue(t, doc, "info", "description"); !strings.HasPrefix(got.(string), tt.name+" API") {
+				t.Errorf("info.description = %q, want it to start with the project name", got)
+			}
+			yamlValue(t, doc, "paths", "/health", "get")
+			yamlValue(t, doc, "paths", tt.wantPath, "get")
+			yamlValue(t, doc, "paths", tt.wantPath+"/{id}", "get", "parameters")
+			yamlValue(t, doc, "components", "schemas", tt.wantSchema, "properties", "name")
+
+			parseYAML(t, files["oapi-codegen.yaml"])
+		})
+	}
+}
+
+func TestOpenAPI_Gin(t *testing.T) {
+	tests := []struct {
+		name          string
+		libraries     []string
+		wantServer    string
+		wantRegisters bool
+	}{
+		{name: "openapi only", libraries: []string{"openapi"}, wantServer: "std-http-server"},
+		{name: "gin only", libraries: []string{"gin"}},
+		{name: "gin and openapi", libraries: []string{"gin", "openapi"}, wantServer: "gin-server", wantRegisters: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := domain.Project{Name: "demo", Slug: "demo", Module: "example.com/demo", Libraries: tt.libraries}
+			files := NewManager(project).FileTemplates()
+
+			if tt.wantServer != "" {
+				config := parseYAML(t, files["oapi-codegen.yaml"])
+				if got := yamlValue(t, config, "generate", tt.wantServer); got != "true" {
+					t.Errorf("generate.%s = %v, want true", tt.wantServer, got)
+				}
+			}
+			if routes, ok := files["internal/http/routes.go"]; ok {
+				if got := strings.Contains(routes, "api.RegisterHandlers(router, server)"); got != tt.wantRegisters {
+					t.Errorf("routes.go registers generated handlers = %v, want %v:\n%s", got, tt.wantRegisters, routes)
+				}
+			}
+		})
+	}
+}
+
+func TestIncompatible_OpenAPICobra(t *testing.T) {
+	if !Incompatible("OpenAPI", "cobra") {
+		t.Error("Incompatible(OpenAPI, cobra) = false, want true")
+	}
+	if Incompatible("openapi", "Vanilla") {
+		t.Error("Incompatible(openapi, Vanilla) = true, want false")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GraphQL
+// ---------------------------------------------------------------------------
+
+func TestGraphQL_SchemaNamedAfterProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		slug      string
+		wantType  string
+		wantField string
+	}{
+		{name: "demo", slug: "demo", wantType: "Demo", wantField: "demo"},
+		{name: "Order Service", slug: "order-service", wantType: "OrderService", wantField: "orderService"},
+		{name: "3d_models", slug: "3d_models", wantType: "Item", wantField: "item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			project := domain.Project{Name: tt.name, Slug: tt.slug, Module: "example.com/" + tt.slug, Libraries: []string{"graphql"}}
+			schema := NewManager(project).FileTemplates()["graph/schema.graphqls"]
+
+			for _, want := range []string{
+				"type " + tt.wantType + " {\n",
+				"  " + tt.wantField + "(id: ID!): " + tt.wantType + "\n",
+				"  " + tt.wantField + "List: [" + tt.wantType + "!]!\n",
+			} {
+				if !strings.Contains(schema, want) {
+					t.Errorf("schema missing %q:\n%s", want, schema)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratedGoParses(t *testing.T) {
+	for _, libraries := range [][]string{{"graphql"}, {"gin", "graphql"}, {"gin", "cors"}, {"gin", "gorm", "graphql"}, {"testify"}} {
+		t.Run(strings.Join(libraries, "_"), func(t *testing.T) {
+			project := domain.Project{Name: "Ops Beta", Slug: "ops-beta", Module: "example.com/ops-beta", Libraries: libraries}
+			m := NewManager(project)
+			sources := map[string]string{"main.go": m.GenerateMain("Vanilla")}
+			for path, content := range m.FileTemplates() {
+				if strings.HasSuffix(path, ".go") {
+					sources[path] = content
+				}
+			}
+			for path, src := range sources {
+				if _, err := parser.ParseFile(token.NewFileSet(), path, src, parser.AllErrors); err != nil {
+					t.Errorf("%s does not parse: %v\n%s", path, err, src)
+				}
+			}
+		})
+	}
+}
+
+func TestManager_Warnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		libraries []string
+		want      int
+	}{
+		{name: "graphql alone", libraries: []string{"graphql"}},
+		{name: "sqlc alone", libraries: []string{"sqlc"}},
+		{name: "graphql and sqlc", libraries: []string{"sqlc", "GraphQL"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewManager(domain.Project{Libraries: tt.libraries}).Warnings()
+			if len(got) != tt.want {
+				t.Fatalf("Warnings() = %q, want %d warning(s)", got, tt.want)
+			}
+			for _, warning := range got {
+				if !strings.Contains(warning, "sqlc") || !strings.Contains(warning, "graphql") {
+					t.Errorf("warning %q should name both libraries", warning)
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// gorm
+// ---------------------------------------------------------------------------
+
+func TestGorm_OpenUsesSlugDatabase(t *testing.T) {
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{slug: "billing-api", want: `dsn = "billing-api.db"`},
+		{slug: "", want: `dsn = "app.db"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			project := domain.Project{Name: "Billing API", Slug: tt.slug, Module: "example.com/billing-api", Libraries: []string{"gorm"}}
+			db := NewManager(project).FileTemplates()["internal/db/db.go"]
+			_, open, ok := strings.Cut(db, "func Open()")
+			if !ok || !strings.Contains(open, tt.want) {
+				t.Errorf("Open() should default to %s:\n%s", tt.want, db)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Air
+// ---------------------------------------------------------------------------
+
+func TestAir_BuildsMainPackage(t *testing.T) {
+	tests := []struct {
+		framework string
+		wantCmd   string
+		wantMain  string
+	}{
+		{framework: "Vanilla", wantCmd: `cmd = "go build -o ./tmp/demo ."`, wantMain: "main.go"},
+		{framework: "Cobra", wantCmd: `cmd = "go build -o ./tmp/demo ./cmd/demo"`, wantMain: "cmd/demo/main.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			project := domain.Project{Framework: tt.framework, Name: "demo", Slug: "demo", Module: "example.com/demo", Libraries: []string{"air"}}
+			config := NewManager(project).FileTemplates()[".air.toml"]
+
+			if !strings.Contains(config, "\n  "+tt.wantCmd+"\n") {
+				t.Errorf(".air.toml missing %q:\n%s", tt.wantCmd, config)
+			}
+			if !strings.Contains(config, `entrypoint = ["./tmp/demo"]`) {
+				t.Errorf(".air.toml does not run the built binary:\n%s", config)
+			}
+			if got := MainFile(project); got != tt.wantMain {
+				t.Errorf("MainFile() = %q, want %q", got, tt.wantMain)
+			}
+		})
+	}
+}
+
+func TestAir_MakefileDevTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		libraries []string
+		wantDev   bool
+	}{
+		{name: "openapi without air", libraries: []string{"openapi"}},
+		{name: "openapi with air", libraries: []string{"openapi", "air"}, wantDev: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := domain.Project{Name: "demo", Slug: "demo", Libraries: tt.libraries}
+			makefile := NewManager(project).FileTemplates()["Makefile"]
+
+			if got := strings.Contains(makefile, "\ndev:\n\tair\n"); got != tt.wantDev {
+				t.Errorf("Makefile has dev target = %v, want %v:\n%s", got, tt.wantDev, makefile)
+			}
+			if !strings.Contains(makefile, "\ngenerate:\n") {
+				t.Errorf("Makefile lost the generate target:\n%s", makefile)
+			}
+		})
+	}
+
+	air := NewManager(domain.Project{Slug: "demo", Libraries: []string{"air"}}).FileTemplates()
+	if _, ok := air["Makefile"]; ok {
+		t.Error("air alone should not write a Makefile")
+	}
+}
+
+// parseYAML parses the block-style subset of YAML the templates use:
+// nested mappings, "- " sequences and plain or double-quoted scalars. It
+// fails the test on anything else, including tabs and bad indentation.
+func parseYAML(t *testing.T, src string) map[string]any {
+	t.Helper()
+	if src == "" {
+		t.Fatal("empty YAML document")
+	}
+	p := &yamlParser{t: t}
+	for i, raw := range strings.Split(src, "\n") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if strings.Contains(raw, "\t") {
+			t.Fatalf("line %d: tab in YAML: %q", i+1, raw)
+		}
+		text := strings.TrimLeft(raw, " ")
+		p.lines = append(p.lines, yamlLine{no: i + 1, indent: len(raw) - len(text), text: text})
+	}
+	doc := p.mapping(0)
+	if p.pos != len(p.lines) {
+		t.Fatalf("line %d: unexpected indentation", p.lines[p.pos].no)
+	}
+	return doc
+}
+
+type yamlLine struct {
+	no     int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	t     *testing.T
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) block(indent int) any {
+	if strings.HasPrefix(p.lines[p.pos].text, "- ") {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) map[string]any {
+	m := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		key, value, ok := strings.Cut(line.text, ":")
+		if !ok || (value != "" && value[0] != ' ') {
+			p.t.Fatalf("line %d: want \"key: value\", got %q", line.no, line.text)
+		}
+		key = p.scalar(line, key)
+		if _, dup := m[key]; dup {
+			p.t.Fatalf("line %d: duplicate key %q", line.no, key)
+		}
+		p.pos++
+		switch {
+		case strings.TrimSpace(value) != "":
+			m[key] = p.scalar(line, strings.TrimSpace(value))
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			m[key] = p.block(p.lines[p.pos].indent)
+		default:
+			m[key] = nil
+		}
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		p.t.Fatalf("line %d: unexpected indentation", p.lines[p.pos].no)
+	}
+	return m
+}
+
+func (p *yamlParser) sequence(indent int) []any {
+	var items []any
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && strings.HasPrefix(p.lines[p.pos].text, "- ") {
+		line := p.lines[p.pos]
+		item := strings.TrimPrefix(line.text, "- ")
+		if strings.Contains(item, ": ") || strings.HasSuffix(item, ":") {
+			// An inline mapping continues on the lines indented past "- ".
+			p.lines[p.pos] = yamlLine{no: line.no, indent: indent + 2, text: item}
+			items = append(items, p.mapping(indent+2))
+			continue
+		}
+		items = append(items, p.scalar(line, item))
+		p.pos++
+	}
+	return items
+}
+
+func (p *yamlParser) scalar(line yamlLine, value string) string {
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			p.t.Fatalf("line %d: bad quoted scalar %s: %v", line.no, value, err)
+		}
+		return unquoted
+	}
+	if value == "" || strings.ContainsAny(value[:1], "'{}[]&*!|>%@`#") || strings.Contains(value, " #") {
+		p.t.Fatalf("line %d: unsupported scalar %q", line.no, value)
+	}
+	return value
+}
+
+// yamlValue walks doc through the mapping keys and fails if one is missing.
+func yamlValue(t *testing.T, doc map[string]any, keys ...string) any {
+	t.Helper()
+	var value any = doc
+	for i, key := range keys {
+		m, ok := value.(map[string]any)
+		if !ok {
+			t.Fatalf("%s is not a mapping", strings.Join(keys[:i], "."))
+		}
+		if value, ok = m[key]; !ok {
+			t.Fatalf("missing key %s", strings.Join(keys[:i+1], "."))
+		}
+	}
+	return value
+}
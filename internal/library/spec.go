@@ -0,0 +1,592 @@
+package library
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"project-initiator/internal/domain"
+)
+
+// Spec describes what a library adds to a generated Go project.
+type Spec struct {
+	// Name matches the selected library case-insensitively.
+	Name string
+	// Title is the display name used in the generated README.
+	Title string
+	// IncompatibleFrameworks names frameworks the library cannot be added
+	// to, matched case-insensitively. A framework with the library's own
+	// name is always incompatible.
+	IncompatibleFrameworks []string
+	// Needs names libraries that must be selected too, e.g. "gin" for
+	// middleware that plugs into the Gin server.
+	Needs []string
+	// Overlaps maps other library names to a warning shown when both are
+	// selected, e.g. because both generate the same kind of code.
+	Overlaps map[string]string
+	// Requires lists go.mod require lines, e.g. "gorm.io/gorm v1.25.12".
+	Requires []string
+	// Env returns the environment variables the library's generated code
+	// reads, for .env.example. Optional.
+	Env func(project domain.Project) []domain.EnvVar
+	// Files returns extra files keyed by slash-separated path relative to
+	// the project dir. Optional.
+	Files func(project domain.Project) map[string]string
+	// Readme returns the library's README section in Markdown, starting
+	// with a "## " heading. Optional.
+	Readme func(project domain.Project) string
+}
+
+// Specs lists the built-in libraries in the order they are generated.
+// Append to it to make a custom library available to NewManager.
+var Specs = []Spec{
+	{
+		Name:     "gin",
+		Title:    "Gin",
+		Requires: []string{"github.com/gin-gonic/gin v1.10.0"},
+		Env:      portEnv,
+		Files: func(project domain.Project) map[string]string {
+			return map[string]string{
+				"internal/http/server.go": ginServer(project),
+				"internal/http/routes.go": fmt.Sprintf(goGinRoutesTemplate, project.Name, ginOpenAPIRoutes(project)),
+			}
+		},
+		Readme: ginReadme,
+	},
+	{
+		Name:     "cors",
+		Title:    "CORS",
+		Needs:    []string{"gin"},
+		Requires: []string{"github.com/gin-contrib/cors v1.7.2"},
+		Env: func(domain.Project) []domain.EnvVar {
+			return []domain.EnvVar{{
+				Name:    "CORS_ALLOWED_ORIGINS",
+				Example: "http://localhost:5173",
+				Comment: "Comma-separated origins allowed to call the API",
+			}}
+		},
+		Files: func(domain.Project) map[string]string {
+			return map[string]string{"internal/http/cors.go": goGinCors}
+		},
+		Readme: corsReadme,
+	},
+	{
+		Name:     "gorm",
+		Title:    "Gorm",
+		Requires: []string{"gorm.io/driver/sqlite v1.5.7", "gorm.io/gorm v1.25.12"},
+		Env: func(project domain.Project) []domain.EnvVar {
+			return []domain.EnvVar{{
+				Name:    "DATABASE_URL",
+				Example: dbFile(project),
+				Comment: "SQLite database file Gorm opens",
+			}}
+		},
+		Files: func(project domain.Project) map[string]string {
+			return map[string]string{
+				"internal/db/db.go":     render(gormDBTemplate, newDBData(project)),
+				"internal/db/models.go": goGormModels,
+			}
+		},
+		Readme: gormReadme,
+	},
+	{
+		Name:  "sqlc",
+		Title: "Sqlc",
+		Files: func(domain.Project) map[string]string {
+			return map[string]string{
+				"sqlc.yaml":             goSqlcConfig,
+				"db/schema.sql":         goSqlcSchema,
+				"db/query.sql":          goSqlcQuery,
+				"internal/db/README.md": goSqlcReadme,
+			}
+		},
+		Readme: sqlcReadme,
+	},
+	{
+		Name:                   "openapi",
+		Title:                  "OpenAPI",
+		IncompatibleFrameworks: []string{"Cobra"},
+		Files: func(project domain.Project) map[string]string {
+			data := newOpenAPIData(project)
+			return map[string]string{
+				"api/openapi.yaml":    render(openAPISpecTemplate, data),
+				"oapi-codegen.yaml":   render(openAPICodegenTemplate, data),
+				"Makefile":            render(openAPIMakefileTemplate, data),
+				"internal/api/doc.go": goOpenAPIDoc,
+			}
+		},
+		Readme: openAPIReadme,
+	},
+	{
+		Name:  "graphql",
+		Title: "GraphQL",
+		Overlaps: map[string]string{
+			"sqlc": "graphql and sqlc both generate Go model types; keep gqlgen's in graph/model and convert sqlc rows to them in the resolvers",
+		},
+		Requires: []string{"github.com/99designs/gqlgen v0.17.55", "github.com/vektah/gqlparser/v2 v2.5.17"},
+		Env:      portEnv,
+		Files: func(project domain.Project) map[string]string {
+			data := newGraphQLData(project)
+			return map[string]string{
+				"gqlgen.yml":            goGqlgenConfig,
+				"graph/schema.graphqls": render(graphQLSchemaTemplate, data),
+				"graph/resolver.go":     goGraphQLResolver,
+				"graph/handler.go":      render(graphQLHandlerTemplate, data),
+			}
+		},
+		Readme: graphQLReadme,
+	},
+	{
+		Name:  "air",
+		Title: "Air",
+		Files: func(project domain.Project) map[string]string {
+			return map[string]string{".air.toml": render(airConfigTemplate, newAirData(project))}
+		},
+		Readme: airReadme,
+	},
+	{
+		Name:     "testify",
+		Title:    "Testify",
+		Requires: []string{"github.com/stretchr/testify v1.9.0"},
+		Files:    testifyFiles,
+		Readme:   testifyReadme,
+	},
+	{
+		Name:  "version",
+		Title: "Version",
+		Files: func(project domain.Project) map[string]string {
+			return map[string]string{"internal/version/version.go": fmt.Sprintf(goVersionTemplate, project.Module)}
+		},
+		Readme: versionReadme,
+	},
+}
+
+// Incompatible reports whether the named library cannot be added to the
+// framework, either because it shares the framework's name or because its
+// spec lists the framework in IncompatibleFrameworks.
+func Incompatible(name string, framework string) bool {
+	name = strings.TrimSpace(name)
+	framework = strings.TrimSpace(framework)
+	if strings.EqualFold(name, framework) {
+		return true
+	}
+	for _, spec := range Specs {
+		if !strings.EqualFold(spec.Name, name) {
+			continue
+		}
+		for _, incompatible := range spec.IncompatibleFrameworks {
+			if strings.EqualFold(strings.TrimSpace(incompatible), framework) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Needs returns the libraries that must be selected along with the named
+// one, or nil when its spec needs none or it has no spec.
+func Needs(name string) []string {
+	name = strings.TrimSpace(name)
+	for _, spec := range Specs {
+		if strings.EqualFold(spec.Name, name) {
+			return spec.Needs
+		}
+	}
+	return nil
+}
+
+// selects reports whether the project selected the named library.
+func selects(project domain.Project, name string) bool {
+	for _, lib := range project.Libraries {
+		if strings.EqualFold(strings.TrimSpace(lib), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MainPackage returns the slash-separated directory, relative to the
+// project dir, that holds the project's main.go: "cmd/<slug>" for Cobra and
+// "." otherwise. Plan and every library that points at main use it.
+func MainPackage(project domain.Project) string {
+	if strings.EqualFold(project.Framework, "cobra") {
+		return "cmd/" + project.Slug
+	}
+	return "."
+}
+
+// MainFile returns the slash-separated path of main.go relative to the
+// project dir.
+func MainFile(project domain.Project) string {
+	return path.Join(MainPackage(project), "main.go")
+}
+
+// listenPort is the port the generated server listens on.
+func listenPort(project domain.Project) int {
+	if project.Port == 0 {
+		return domain.DefaultPort
+	}
+	return project.Port
+}
+
+// portEnv is the PORT variable the generated main reads.
+func portEnv(project domain.Project) []domain.EnvVar {
+	return []domain.EnvVar{{
+		Name:    "PORT",
+		Example: strconv.Itoa(listenPort(project)),
+		Comment: "Port the server listens on",
+	}}
+}
+
+// runTarget is the package to pass to "go run" for the project's main file.
+func runTarget(project domain.Project) string {
+	if pkg := MainPackage(project); pkg != "." {
+		return "./" + pkg
+	}
+	return "."
+}
+
+func ginReadme(project domain.Project) string {
+	return strings.Join([]string{
+		"## Gin",
+		"",
+		"The HTTP server lives in `internal/http`; add routes in `routes.go`.",
+		"Start it and check the health endpoint:",
+		"",
+		"```bash",
+		"go run " + runTarget(project),
+		fmt.Sprintf("curl http://localhost:%d/health", listenPort(project)),
+		`# {"status":"ok"}`,
+		"```",
+	}, "\n")
+}
+
+func corsReadme(project domain.Project) string {
+	return strings.Join([]string{
+		"## CORS",
+		"",
+		"The Gin server answers cross-origin requests from the origins listed in",
+		"`CORS_ALLOWED_ORIGINS`, comma-separated. Unset, it allows the Vite dev",
+		"server at `http://localhost:5173`:",
+		"",
+		"```bash",
+		"CORS_ALLOWED_ORIGINS=https://app.example.com,http://localhost:5173 go run " + runTarget(project),
+		"```",
+		"",
+		"Change the allowed methods and headers in `internal/http/cors.go`.",
+	}, "\n")
+}
+
+// ginServer is server.go, which installs the CORS middleware when that
+// library is selected.
+func ginServer(project domain.Project) string {
+	middleware := ""
+	if selects(project, "cors") {
+		middleware = "\trouter.Use(corsMiddleware())\n"
+	}
+	return fmt.Sprintf(goGinServerTemplate, middleware)
+}
+
+func gormReadme(project domain.Project) string {
+	return strings.Join([]string{
+		"## Gorm",
+		"",
+		"`internal/db` opens the SQLite database named by `DATABASE_URL` (`" + dbFile(project) + "`",
+		"when unset) and migrates the `User` model on startup:",
+		"",
+		"```go",
+		`import "` + project.Module + `/internal/db"`,
+		"",
+		"conn, err := db.Open()",
+		"if err != nil {",
+		"\treturn err",
+		"}",
+		"if err := db.AutoMigrate(conn); err != nil {",
+		"\treturn err",
+		"}",
+		`conn.Create(&db.User{Name: "Ada"})`,
+		"```",
+	}, "\n")
+}
+
+func sqlcReadme(project domain.Project) string {
+	return strings.Join([]string{
+		"## Sqlc",
+		"",
+		"The schema and queries live in `db/`. Generate the Go code into",
+		"`internal/db`:",
+		"",
+		"```bash",
+		"sqlc generate",
+		"```",
+		"",
+		"Then call the generated queries with a `*sql.DB`:",
+		"",
+		"```go",
+		`import "` + project.Module + `/internal/db"`,
+		"",
+		"queries := db.New(sqlDB)",
+		"users, err := queries.ListUsers(ctx)",
+		"```",
+	}, "\n")
+}
+
+// openAPIData is the data the OpenAPI templates render from.
+type openAPIData struct {
+	domain.Project
+	// Resource is the sample resource's path segment, e.g. "my-app".
+	Resource string
+	// Schema is the sample resource's schema name, e.g. "MyApp".
+	Schema string
+	// Gin selects Gin server stubs instead of net/http ones.
+	Gin bool
+	// Air adds a dev target running the air live-reloader to the Makefile.
+	Air bool
+}
+
+func newOpenAPIData(project domain.Project) openAPIData {
+	data := openAPIData{
+		Project:  project,
+		Resource: project.Slug,
+		Schema:   typeName(project),
+		Gin:      selects(project, "gin"),
+		Air:      selects(project, "air"),
+	}
+	if data.Resource == "" {
+		data.Resource = "items"
+	}
+	return data
+}
+
+// typeName derives an exported type name for the project's sample resource
+// from its slug, e.g. "MyApp" for "my-app". It falls back to "Item" when
+// the slug does not start with a letter, since the name becomes a Go type.
+func typeName(project domain.Project) string {
+	var name string
+	words := strings.FieldsFunc(project.Slug, func(r rune) bool { return r == '-' || r == '_' })
+	for _, word := range words {
+		name += strings.ToUpper(word[:1]) + word[1:]
+	}
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		return "Item"
+	}
+	return name
+}
+
+var templateFuncs = template.FuncMap{
+	// quote renders a double-quoted string that is valid both as a YAML
+	// scalar and a Go literal, so names containing ':', '#' or '"' survive.
+	"quote": strconv.Quote,
+}
+
+var (
+	openAPISpecTemplate     = template.Must(template.New("openapi.yaml").Funcs(templateFuncs).Parse(goOpenAPISpec))
+	openAPICodegenTemplate  = template.Must(template.New("oapi-codegen.yaml").Parse(goOpenAPICodegen))
+	openAPIMakefileTemplate = template.Must(template.New("Makefile").Parse(goOpenAPIMakefile))
+	airConfigTemplate       = template.Must(template.New(".air.toml").Funcs(templateFuncs).Parse(goAirConfig))
+	graphQLSchemaTemplate   = template.Must(template.New("schema.graphqls").Parse(goGraphQLSchema))
+	graphQLHandlerTemplate  = template.Must(template.New("handler.go").Funcs(templateFuncs).Parse(goGraphQLHandler))
+	gormDBTemplate          = template.Must(template.New("db.go").Funcs(templateFuncs).Parse(goGormDB))
+)
+
+// render executes a built-in template. The templates only read fields of
+// their data struct, so execution cannot fail once they parse.
+func render(tmpl *template.Template, data any) string {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		panic(fmt.Sprintf("library: render %s: %v", tmpl.Name(), err))
+	}
+	return out.String()
+}
+
+// ginOpenAPIRoutes is the extra routes.go block that points Gin projects at
+// the generated handlers.
+func ginOpenAPIRoutes(project domain.Project) string {
+	if !selects(project, "openapi") {
+		return ""
+	}
+	return strings.Join([]string{
+		"",
+		"\t// Generated OpenAPI handlers: run `make generate`, implement",
+		"\t// api.ServerInterface from internal/api, then register it:",
+		"\t//",
+		"\t//\tapi.RegisterHandlers(router, server)",
+		"",
+	}, "\n")
+}
+
+func openAPIReadme(project domain.Project) string {
+	data := newOpenAPIData(project)
+	server := "net/http"
+	if data.Gin {
+		server = "Gin"
+	}
+	return strings.Join([]string{
+		"## OpenAPI",
+		"",
+		"`api/openapi.yaml` describes the API: a health endpoint and a sample",
+		"`/" + data.Resource + "` resource. Edit it first, then regenerate the " + server + " server",
+		"stubs into `internal/api` with [oapi-codegen](https://github.com/oapi-codegen/oapi-codegen),",
+		"configured by `oapi-codegen.yaml`:",
+		"",
+		"```bash",
+		"make generate",
+		"go mod tidy",
+		"```",
+		"",
+		"Implement the generated `api.ServerInterface` and register it with",
+		"`api.RegisterHandlers`.",
+	}, "\n")
+}
+
+// graphQLData is the data the GraphQL templates render from.
+type graphQLData struct {
+	domain.Project
+	// Type is the sample object type, e.g. "MyApp".
+	Type string
+	// Field is the query field returning one Type, e.g. "myApp".
+	Field string
+}
+
+func newGraphQLData(project domain.Project) graphQLData {
+	name := typeName(project)
+	return graphQLData{
+		Project: project,
+		Type:    name,
+		Field:   strings.ToLower(name[:1]) + name[1:],
+	}
+}
+
+func graphQLReadme(project domain.Project) string {
+	data := newGraphQLData(project)
+	return strings.Join([]string{
+		"## GraphQL",
+		"",
+		"`graph/schema.graphqls` defines a sample `" + data.Type + "` type. Generate the",
+		"executable schema and resolver stubs with [gqlgen](https://gqlgen.com),",
+		"configured by `gqlgen.yml`, then fill in `graph/schema.resolvers.go`:",
+		"",
+		"```bash",
+		"go generate ./graph",
+		"go mod tidy",
+		"go run " + runTarget(project),
+		"```",
+		"",
+		fmt.Sprintf("Queries go to `http://localhost:%d/query`; the playground is at", listenPort(project)),
+		fmt.Sprintf("`http://localhost:%d/playground`.", listenPort(project)),
+	}, "\n")
+}
+
+// dbData is the data the Gorm database code renders from.
+type dbData struct {
+	domain.Project
+	// File is the SQLite database opened when DATABASE_URL is unset.
+	File string
+}
+
+func newDBData(project domain.Project) dbData {
+	return dbData{Project: project, File: dbFile(project)}
+}
+
+// dbFile names the default SQLite database after the project, e.g.
+// "billing-api.db".
+func dbFile(project domain.Project) string {
+	if project.Slug == "" {
+		return "app.db"
+	}
+	return project.Slug + ".db"
+}
+
+// airData is the data .air.toml renders from.
+type airData struct {
+	domain.Project
+	// Package is the main package to build, e.g. "./cmd/my-app".
+	Package string
+}
+
+func newAirData(project domain.Project) airData {
+	return airData{Project: project, Package: runTarget(project)}
+}
+
+func airReadme(project domain.Project) string {
+	lines := []string{
+		"## Air",
+		"",
+		"`.air.toml` rebuilds the app from `" + MainFile(project) + "` and restarts it whenever a",
+		"Go file changes.",
+		"Install [air](https://github.com/air-verse/air) once, then run it from the project dir:",
+		"",
+		"```bash",
+		"go install github.com/air-verse/air@latest",
+		"air",
+		"```",
+	}
+	if selects(project, "openapi") {
+		lines = append(lines, "", "`make dev` does the same.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// testifyFiles adds sample tests for the other selected libraries that
+// generate something to test, such as the Gin server's health endpoint.
+func testifyFiles(project domain.Project) map[string]string {
+	// Replaces the plain app_test.go of frameworks that generate one.
+	files := map[string]string{
+		"internal/app/app_test.go":            fmt.Sprintf(goTestifyAppTestTemplate, project.Module),
+		"internal/testhelpers/testhelpers.go": goTestHelpers,
+	}
+	if selects(project, "gin") {
+		cors := ""
+		if selects(project, "cors") {
+			cors = goTestifyCorsTest
+		}
+		files["internal/http/server_test.go"] = fmt.Sprintf(goTestifyGinTestTemplate, cors)
+	}
+	return files
+}
+
+func testifyReadme(project domain.Project) string {
+	lines := []string{
+		"## Testify",
+		"",
+		"Tests use [testify](https://github.com/stretchr/testify): `require` stops a test",
+		"on the first failure, `assert` records it and carries on.",
+		"`internal/testhelpers` has shared fixtures, such as a temp dir filled with files:",
+		"",
+		"```go",
+		`import "` + project.Module + `/internal/testhelpers"`,
+		"",
+		`dir := testhelpers.TempDir(t, map[string]string{"config/app.txt": "hello"})`,
+		"```",
+	}
+	if selects(project, "gin") {
+		lines = append(lines, "", "`internal/http/server_test.go` checks the Gin server's `/health` endpoint.")
+	}
+	lines = append(lines, "", "```bash", "go test ./...", "```")
+	return strings.Join(lines, "\n")
+}
+
+func versionReadme(project domain.Project) string {
+	pkg := project.Module + "/internal/version"
+	return strings.Join([]string{
+		"## Version",
+		"",
+		"`internal/version` holds `Version`, `Commit` and `BuildDate`, which read `dev`,",
+		"`none` and `unknown` until they are set at link time. Build releases with:",
+		"",
+		"```makefile",
+		"LDFLAGS := -X " + pkg + ".Version=$(shell git describe --tags --always) \\",
+		"\t-X " + pkg + ".Commit=$(shell git rev-parse --short HEAD) \\",
+		"\t-X " + pkg + ".BuildDate=$(shell date -u +%Y-%m-%dT%H:%M:%SZ)",
+		"",
+		"build:",
+		"\tgo build -ldflags \"$(LDFLAGS)\" ./...",
+		"```",
+		"",
+		"In CI, pass the same `-ldflags` to `go build`, taking the version from the tag",
+		"being built.",
+	}, "\n")
+}
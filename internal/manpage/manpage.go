@@ -0,0 +1,142 @@
+// Package manpage renders the command reference as a roff man page or as
+// Markdown. Callers build a Page from the tables that also drive flag
+// parsing and config handling, so the docs cannot drift from the code.
+package manpage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page is a man page.
+type Page struct {
+	Name    string
+	Section int
+	// Summary is the one-line description shown after the name.
+	Summary  string
+	Synopsis []string
+	// Description is a paragraph introducing the command.
+	Description string
+	Sections    []Section
+}
+
+// Section is a titled list of entries, optionally introduced by a paragraph.
+type Section struct {
+	Title   string
+	Text    string
+	Entries []Entry
+}
+
+// Entry is a flag, command, key or code and what it does.
+type Entry struct {
+	Name string
+	// Arg names the entry's value, e.g. "path".
+	Arg         string
+	Default     string
+	Description string
+}
+
+// Roff writes page in man(7) format.
+func Roff(w io.Writer, page Page) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, ".TH %s %d \"\" %s \"User Commands\"\n", strings.ToUpper(page.Name), page.Section, roffQuote(page.Name))
+	fmt.Fprintf(bw, ".SH NAME\n%s \\- %s\n", roffEscape(page.Name), roffEscape(page.Summary))
+	if len(page.Synopsis) > 0 {
+		bw.WriteString(".SH SYNOPSIS\n")
+		for i, line := range page.Synopsis {
+			if i > 0 {
+				bw.WriteString(".br\n")
+			}
+			fmt.Fprintf(bw, "%s\n", roffText(line))
+		}
+	}
+	if page.Description != "" {
+		fmt.Fprintf(bw, ".SH DESCRIPTION\n%s\n", roffText(page.Description))
+	}
+	for _, section := range page.Sections {
+		fmt.Fprintf(bw, ".SH %s\n", strings.ToUpper(section.Title))
+		if section.Text != "" {
+			fmt.Fprintf(bw, "%s\n", roffText(section.Text))
+		}
+		for _, entry := range section.Entries {
+			bw.WriteString(".TP\n")
+			fmt.Fprintf(bw, "\\fB%s\\fR", roffEscape(entry.Name))
+			if entry.Arg != "" {
+				fmt.Fprintf(bw, " \\fI%s\\fR", roffEscape(entry.Arg))
+			}
+			fmt.Fprintf(bw, "\n%s\n", roffText(describe(entry)))
+		}
+	}
+	return bw.Flush()
+}
+
+// Markdown writes page as a Markdown document for docs sites.
+func Markdown(w io.Writer, page Page) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s(%d)\n\n%s — %s\n", page.Name, page.Section, page.Name, page.Summary)
+	if len(page.Synopsis) > 0 {
+		bw.WriteString("\n## Synopsis\n\n```\n")
+		for _, line := range page.Synopsis {
+			fmt.Fprintf(bw, "%s\n", line)
+		}
+		bw.WriteString("```\n")
+	}
+	if page.Description != "" {
+		fmt.Fprintf(bw, "\n## Description\n\n%s\n", page.Description)
+	}
+	for _, section := range page.Sections {
+		fmt.Fprintf(bw, "\n## %s\n\n", title(section.Title))
+		if section.Text != "" {
+			fmt.Fprintf(bw, "%s\n\n", section.Text)
+		}
+		for _, entry := range section.Entries {
+			name := "`" + entry.Name
+			if entry.Arg != "" {
+				name += " " + entry.Arg
+			}
+			fmt.Fprintf(bw, "- %s` — %s\n", name, describe(entry))
+		}
+	}
+	return bw.Flush()
+}
+
+// describe appends the default, when there is a meaningful one.
+func describe(entry Entry) string {
+	if entry.Default == "" || entry.Default == "false" || entry.Default == "0" {
+		return entry.Description
+	}
+	return fmt.Sprintf("%s (default: %s)", entry.Description, entry.Default)
+}
+
+// title turns "EXIT STATUS" into "Exit status".
+func title(s string) string {
+	s = strings.ToLower(s)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// roffEscape escapes backslashes and hyphens so groff prints them as typed.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	return strings.ReplaceAll(s, "-", `\-`)
+}
+
+// roffText escapes s and guards lines that would otherwise be read as
+// requests.
+func roffText(s string) string {
+	lines := strings.Split(roffEscape(s), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\(dq`) + `"`
+}
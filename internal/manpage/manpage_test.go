@@ -0,0 +1,65 @@
+package manpage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var testPage = Page{
+	Name:        "tool",
+	Section:     1,
+	Summary:     "do things",
+	Synopsis:    []string{"tool [flags]"},
+	Description: "Does things.\n.dot line",
+	Sections: []Section{
+		{
+			Title: "Options",
+			Entries: []Entry{
+				{Name: "--dry-run", Default: "false", Description: "Print only"},
+				{Name: "--dir", Arg: "directory", Default: `C:\tmp`, Description: "Base dir"},
+			},
+		},
+		{Title: "Exit status", Entries: []Entry{{Name: "2", Description: "Bad flags"}}},
+	},
+}
+
+func TestRoff(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Roff(&buf, testPage); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		".TH TOOL 1 \"\" \"tool\" \"User Commands\"\n",
+		".SH NAME\ntool \\- do things\n",
+		".SH EXIT STATUS\n",
+		".TP\n\\fB\\-\\-dry\\-run\\fR\nPrint only\n",
+		".TP\n\\fB\\-\\-dir\\fR \\fIdirectory\\fR\nBase dir (default: C:\\etmp)\n",
+		"\n\\&.dot line\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("roff output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, testPage); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# tool(1)\n\ntool — do things\n",
+		"## Synopsis\n\n```\ntool [flags]\n```\n",
+		"## Exit status\n\n- `2` — Bad flags\n",
+		"- `--dir directory` — Base dir (default: C:\\tmp)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, out)
+		}
+	}
+}
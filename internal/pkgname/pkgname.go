@@ -0,0 +1,60 @@
+// Package pkgname derives the names package managers publish a project
+// under, following each ecosystem's rules: npm's for package.json and
+// PyPI's (PEP 508 and PEP 503) for pyproject.toml.
+package pkgname
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// npmMaxLength is the longest package name npm accepts, scope included.
+const npmMaxLength = 214
+
+var (
+	// npm names and scopes are lowercase and URL-safe, and may not start
+	// with '.' or '_'.
+	npmScope = regexp.MustCompile(`^@[a-z0-9~-][a-z0-9._~-]*$`)
+	npmName  = regexp.MustCompile(`^[a-z0-9~-][a-z0-9._~-]*$`)
+	// pypiName is PEP 508's rule for distribution names.
+	pypiName = regexp.MustCompile(`(?i)^([a-z0-9]|[a-z0-9][a-z0-9._-]*[a-z0-9])$`)
+	// pypiSeparators are the runs PEP 503 collapses into a single '-'.
+	pypiSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// ValidateNPMScope checks a scope such as "@acme". Empty means no scope.
+func ValidateNPMScope(scope string) error {
+	if scope == "" || npmScope.MatchString(scope) {
+		return nil
+	}
+	return fmt.Errorf("npm: a scope is @ followed by lowercase letters, digits, '-', '.', '_' or '~', not starting with '.' or '_'; got %q", scope)
+}
+
+// NPM returns the package.json name for a project slug, lower-cased and
+// prefixed with scope when one is set, e.g. "@acme/billing-api".
+func NPM(scope string, slug string) (string, error) {
+	if err := ValidateNPMScope(scope); err != nil {
+		return "", err
+	}
+	name := strings.ToLower(slug)
+	if !npmName.MatchString(name) {
+		return "", fmt.Errorf("npm: package names use lowercase letters, digits, '-', '.', '_' or '~' and may not start with '.' or '_'; got %q", slug)
+	}
+	if scope != "" {
+		name = scope + "/" + name
+	}
+	if len(name) > npmMaxLength {
+		return "", fmt.Errorf("npm: package names are at most %d characters; %q has %d", npmMaxLength, name, len(name))
+	}
+	return name, nil
+}
+
+// PyPI returns the PEP 503 normalized form of a project name: lower-cased,
+// with each run of '-', '_' and '.' replaced by a single '-'.
+func PyPI(name string) (string, error) {
+	if !pypiName.MatchString(name) {
+		return "", fmt.Errorf("PyPI: PEP 508 names use ASCII letters, digits, '-', '_' and '.', and start and end with a letter or digit; got %q", name)
+	}
+	return strings.ToLower(pypiSeparators.ReplaceAllString(name, "-")), nil
+}
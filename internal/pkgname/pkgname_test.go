@@ -0,0 +1,87 @@
+package pkgname
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNPM(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		slug    string
+		want    string
+		wantErr string
+	}{
+		{name: "unscoped", slug: "billing-api", want: "billing-api"},
+		{name: "scoped", scope: "@acme", slug: "billing-api", want: "@acme/billing-api"},
+		{name: "lower-cased", scope: "@acme", slug: "Billing_API", want: "@acme/billing_api"},
+		{name: "dots and tildes", slug: "web.v2~beta", want: "web.v2~beta"},
+		{name: "scope with dash", scope: "@my-org", slug: "ui", want: "@my-org/ui"},
+		{name: "scope without @", scope: "acme", slug: "ui", wantErr: "npm: a scope is @"},
+		{name: "uppercase scope", scope: "@Acme", slug: "ui", wantErr: "npm: a scope is @"},
+		{name: "scope with slash", scope: "@acme/tools", slug: "ui", wantErr: "npm: a scope is @"},
+		{name: "leading underscore", slug: "_private", wantErr: "may not start with '.' or '_'"},
+		{name: "leading dot", slug: ".hidden", wantErr: "may not start with '.' or '_'"},
+		{name: "space", slug: "my app", wantErr: "npm: package names use lowercase"},
+		{name: "too long", scope: "@acme", slug: strings.Repeat("a", 209), wantErr: "at most 214 characters"},
+		{name: "exactly the limit", scope: "@acme", slug: strings.Repeat("a", 208), want: "@acme/" + strings.Repeat("a", 208)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NPM(tt.scope, tt.slug)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NPM(%q, %q) error = %v, want it to contain %q", tt.scope, tt.slug, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NPM(%q, %q) error = %v", tt.scope, tt.slug, err)
+			}
+			if got != tt.want {
+				t.Errorf("NPM(%q, %q) = %q, want %q", tt.scope, tt.slug, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPyPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "already normal", input: "billing-api", want: "billing-api"},
+		{name: "underscores", input: "billing_api", want: "billing-api"},
+		{name: "mixed case and dots", input: "Billing.API", want: "billing-api"},
+		{name: "separator runs collapse", input: "my__cool-._app", want: "my-cool-app"},
+		{name: "single character", input: "x", want: "x"},
+		{name: "digits", input: "Py3_Tools", want: "py3-tools"},
+		{name: "leading separator", input: "-api", wantErr: true},
+		{name: "trailing separator", input: "api_", wantErr: true},
+		{name: "space", input: "my app", wantErr: true},
+		{name: "non-ascii", input: "café", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PyPI(tt.input)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "PyPI: PEP 508") {
+					t.Fatalf("PyPI(%q) error = %v, want the PEP 508 rule", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PyPI(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("PyPI(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
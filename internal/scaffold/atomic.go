@@ -0,0 +1,152 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"project-initiator/internal/domain"
+)
+
+// rename moves the staged project into place. It is a variable so tests
+// can simulate a cross-device rename.
+var rename = os.Rename
+
+// stagingDir is where an atomic Apply builds a project before renaming it
+// to projectDir: a hidden directory beside it, so both are on the same
+// filesystem and the rename is a single step.
+func stagingDir(projectDir string) string {
+	return filepath.Join(filepath.Dir(projectDir), "."+filepath.Base(projectDir)+".partial")
+}
+
+// applyAtomic writes plan into a staging directory and renames it to the
+// project dir once every file is written, so a failure part way leaves no
+// project dir at all. The project dir must not exist yet.
+func (a *Applier) applyAtomic(plan domain.Plan) (written []string, err error) {
+	stage := stagingDir(plan.ProjectDir)
+	// A staging dir left by a crash is stale.
+	if err := os.RemoveAll(stage); err != nil {
+		return nil, fmt.Errorf("remove stale %s: %w", stage, err)
+	}
+	if err := a.mkdirAll(stage); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(stage)
+		}
+	}()
+
+	staged := plan
+	staged.ProjectDir = stage
+	staged.Actions = make([]domain.Action, len(plan.Actions))
+	for i, action := range plan.Actions {
+		rel, err := filepath.Rel(plan.ProjectDir, action.Path)
+		if err != nil {
+			return nil, err
+		}
+		action.Path = filepath.Join(stage, rel)
+		staged.Actions[i] = action
+	}
+
+	// Events and the returned paths name the final location, not the
+	// staging dir.
+	final := func(path string) string {
+		rel, err := filepath.Rel(stage, path)
+		if err != nil {
+			return path
+		}
+		return filepath.Join(plan.ProjectDir, rel)
+	}
+	inner := *a
+	inner.Progress = func(event FileEvent) {
+		event.Path = final(event.Path)
+		a.report(event)
+	}
+	stagedWritten, err := inner.write(staged)
+	if err != nil {
+		// Nothing reached the project dir.
+		return nil, err
+	}
+
+	if err := a.moveIntoPlace(stage, plan.ProjectDir); err != nil {
+		return nil, err
+	}
+	if a.DurableWrites {
+		if err := syncDir(filepath.Dir(plan.ProjectDir)); err != nil {
+			return nil, fmt.Errorf("sync %s: %w", filepath.Dir(plan.ProjectDir), err)
+		}
+	}
+	for _, path := range stagedWritten {
+		written = append(written, final(path))
+	}
+	return written, nil
+}
+
+// moveIntoPlace renames stage to projectDir, copying it instead when the
+// two are on different filesystems. A failed copy removes what it made.
+func (a *Applier) moveIntoPlace(stage string, projectDir string) error {
+	err := rename(stage, projectDir)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("rename %s: %w", projectDir, err)
+	}
+	if err := copyTree(stage, projectDir); err != nil {
+		_ = os.RemoveAll(projectDir)
+		return fmt.Errorf("copy %s: %w", projectDir, err)
+	}
+	return os.RemoveAll(stage)
+}
+
+// copyTree copies the directory src to dst, which must not exist, keeping
+// file and directory modes.
+func copyTree(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if err := os.Mkdir(target, 0o777); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode()&(fs.ModePerm|fs.ModeSetgid))
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src string, dst string, mode fs.FileMode) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return os.Chmod(dst, mode)
+}
@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"project-initiator/internal/domain"
+	"project-initiator/internal/library"
+)
+
+// envExampleName is the file listing the variables the generated code
+// reads. The real values go in .env, which the generated .gitignore keeps
+// out of git.
+const envExampleName = ".env.example"
+
+// envVars returns the variables the project's code reads: the framework's,
+// then those of its Go libraries, each listed once.
+func (p *Planner) envVars(project domain.Project, framework domain.Framework, data TemplateData) ([]domain.EnvVar, error) {
+	vars := make([]domain.EnvVar, 0, len(framework.Env))
+	for _, v := range framework.Env {
+		example, err := p.renderer.Render(v.Name, v.Example, data)
+		if err != nil {
+			return nil, fmt.Errorf("render %s example: %w", v.Name, err)
+		}
+		v.Example = example
+		vars = append(vars, v)
+	}
+	if usesGoLibraries(project, framework) {
+		vars = append(vars, library.NewManager(project).EnvVars()...)
+	}
+
+	seen := map[string]bool{}
+	return slices.DeleteFunc(vars, func(v domain.EnvVar) bool {
+		if seen[v.Name] {
+			return true
+		}
+		seen[v.Name] = true
+		return false
+	}), nil
+}
+
+// addEnvExample adds a .env.example listing vars to actions, and makes sure
+// the project's .gitignore ignores .env: an existing .gitignore action gets
+// the line appended, otherwise one is created. Without vars it adds nothing.
+func addEnvExample(actions []domain.Action, projectDir string, vars []domain.EnvVar) []domain.Action {
+	if len(vars) == 0 {
+		return actions
+	}
+	actions = append(actions, domain.Action{
+		Path:    filepath.Join(projectDir, envExampleName),
+		Content: envExample(vars),
+	})
+
+	gitignore := filepath.Join(projectDir, ".gitignore")
+	for i, action := range actions {
+		if action.Path != gitignore {
+			continue
+		}
+		if !ignoresEnv(action.Content) {
+			content := action.Content
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			actions[i].Content = content + ".env\n"
+		}
+		return actions
+	}
+	return append(actions, domain.Action{Path: gitignore, Content: ".env\n"})
+}
+
+// envExample renders vars, each under its comment.
+func envExample(vars []domain.EnvVar) string {
+	lines := []string{
+		"# Environment variables read by the generated code.",
+		"# Copy this file to .env, which git ignores, and fill in real values.",
+	}
+	for _, v := range vars {
+		lines = append(lines, "")
+		if v.Comment != "" {
+			lines = append(lines, "# "+v.Comment)
+		}
+		lines = append(lines, v.Name+"="+v.Example)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func ignoresEnv(gitignore string) bool {
+	for _, line := range strings.Split(gitignore, "\n") {
+		switch strings.TrimSpace(line) {
+		case ".env", "/.env", ".env*", "*.env":
+			return true
+		}
+	}
+	return false
+}
@@ -1,228 +1,155 @@
 package scaffold
 
-import "project-initiator/internal/domain"
+import (
+	"embed"
+	"fmt"
+	"path"
+
+	"project-initiator/internal/domain"
+)
+
+// templateSuffix ends every file under templates/, so the Go tool does not
+// mistake a main.go or go.mod template for code or a nested module.
+const templateSuffix = ".tmpl"
+
+// templateFiles holds the built-in templates, one directory per framework:
+// templates/<language>/<framework>/<path>.tmpl.
+//
+//go:embed all:templates
+var templateFiles embed.FS
+
+// embedded loads the templates at paths, relative to templates/dir, in the
+// order given, which is the order Plan writes them in. A missing file
+// panics, so a typo fails at startup rather than for one framework.
+func embedded(dir string, paths ...string) []domain.Template {
+	templates := make([]domain.Template, 0, len(paths))
+	for _, rel := range paths {
+		data, err := templateFiles.ReadFile(path.Join("templates", dir, rel) + templateSuffix)
+		if err != nil {
+			panic(fmt.Sprintf("scaffold: embedded template: %v", err))
+		}
+		templates = append(templates, domain.Template{RelativePath: rel, Content: string(data)})
+	}
+	return templates
+}
+
+// goLibraryDescriptions are the descriptions of the Go libraries, shared by
+// every Go framework that offers them.
+var goLibraryDescriptions = map[string]string{
+	"Gin":     "HTTP server with a health endpoint",
+	"CORS":    "cross-origin requests for the Gin server",
+	"Gorm":    "SQLite database with a migrated User model",
+	"Sqlc":    "type-safe Go from SQL queries",
+	"OpenAPI": "OpenAPI spec with oapi-codegen server stubs",
+	"GraphQL": "gqlgen schema and resolvers",
+	"Air":     "live reload on file changes",
+	"Testify": "assertions and shared test fixtures",
+	"Version": "version info set at link time",
+}
+
+// goLibraries returns the named Go libraries with their descriptions.
+func goLibraries(names ...string) []domain.Library {
+	libs := make([]domain.Library, 0, len(names))
+	for _, name := range names {
+		libs = append(libs, domain.Library{Name: name, Description: goLibraryDescriptions[name]})
+	}
+	return libs
+}
+
+var (
+	npmDev = []string{"npm install", "npm run dev"}
+	bunDev = []string{"bun run dev"}
+)
 
 // Frameworks contains all available framework options.
 var Frameworks = []domain.Framework{
 	{
-		Language: "JavaScript",
-		Name:     "Vanilla",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"node src/index.js\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/index.js",
-				Content:      "console.log(\"hello from {{.Name}}\");\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nJavaScript vanilla starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "JavaScript",
+		Name:        "Vanilla",
+		Description: "minimal starter",
+		Templates:   embedded("javascript/vanilla", "package.json", "src/index.js", "README.md"),
+		NextSteps:   []string{"npm run dev"},
 	},
 	{
-		Language: "Go",
-		Name:     "Vanilla",
-		Libraries: []domain.Library{
-			{Name: "Gin"},
-			{Name: "Gorm"},
-			{Name: "Sqlc"},
-		},
-		Templates: []domain.Template{
-			{
-				RelativePath: "main.go",
-				Content:      "package main\n\nimport (\n\t\"fmt\"\n\n\t\"{{.Module}}/internal/app\"\n)\n\nfunc main() {\n\tif err := app.Run(); err != nil {\n\t\tfmt.Println(\"error:\", err)\n\t}\n}\n",
-			},
-			{
-				RelativePath: "go.mod",
-				Content:      "module {{.Module}}\n\ngo {{.GoVersion}}\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nGo vanilla starter generated by project-initiator.\n",
-			},
-			{
-				RelativePath: "internal/app/app.go",
-				Content:      "package app\n\nimport \"fmt\"\n\nfunc Run() error {\n\tfmt.Println(\"hello from {{.Name}}\")\n\treturn nil\n}\n",
-			},
-		},
+		Language:    "Go",
+		Name:        "Vanilla",
+		Description: "minimal starter",
+		Libraries:   goLibraries("Gin", "CORS", "Gorm", "Sqlc", "OpenAPI", "GraphQL", "Air", "Testify", "Version"),
+		Templates:   embedded("go/vanilla", "main.go", "go.mod", "README.md", "internal/app/app.go", "internal/app/app_test.go"),
 	},
 	{
-		Language: "Go",
-		Name:     "Cobra",
-		Libraries: []domain.Library{
-			{Name: "Gin"},
-			{Name: "Gorm"},
-			{Name: "Sqlc"},
-		},
-		Templates: []domain.Template{
-			{
-				RelativePath: "go.mod",
-				Content:      "module {{.Module}}\n\ngo {{.GoVersion}}\n",
-			},
-			{
-				RelativePath: "cmd/{{.PackageName}}/main.go",
-				Content:      "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t\"github.com/spf13/cobra\"\n\t\"{{.Module}}/internal/app\"\n)\n\nfunc main() {\n\trootCmd := &cobra.Command{\n\t\tUse: \"{{.Name}}\",\n\t\tShort: \"{{.Name}} CLI\",\n\t\tRunE: func(cmd *cobra.Command, args []string) error {\n\t\t\treturn app.Run()\n\t\t},\n\t}\n\n\tif err := rootCmd.Execute(); err != nil {\n\t\t_, _ = fmt.Fprintln(os.Stderr, \"error:\", err)\n\t\tos.Exit(1)\n\t}\n}\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nGenerated by project-initiator.\n",
-			},
-			{
-				RelativePath: "internal/app/app.go",
-				Content:      "package app\n\nimport \"fmt\"\n\nfunc Run() error {\n\tfmt.Println(\"hello from {{.Name}}\")\n\treturn nil\n}\n",
-			},
-		},
+		Language:    "Go",
+		Name:        "Cobra",
+		Description: "CLI app structure",
+		Recommended: true,
+		Libraries:   goLibraries("Gin", "CORS", "Gorm", "Sqlc", "GraphQL", "Air", "Testify", "Version"),
+		Templates:   embedded("go/cobra", "go.mod", "cmd/{{.PackageName}}/main.go", "README.md", "internal/app/app.go"),
 	},
 	{
-		Language: "Node.js",
-		Name:     "Express",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"node src/index.js\"\n  },\n  \"dependencies\": {\n    \"express\": \"^4.19.2\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/index.js",
-				Content:      "import express from \"express\";\n\nconst app = express();\nconst port = process.env.PORT || 3000;\n\napp.get(\"/\", (req, res) => {\n  res.send(\"Hello from {{.Name}}\");\n});\n\napp.listen(port, () => {\n  console.log(`{{.Name}} listening on ${port}`);\n});\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nGenerated by project-initiator.\n",
-			},
-		},
+		Language:    "Node.js",
+		Name:        "Express",
+		Description: "Node.js web server",
+		Env:         []domain.EnvVar{{Name: "PORT", Example: "{{.Port}}", Comment: "Port the server listens on"}},
+		Templates:   embedded("nodejs/express", "package.json", "src/index.js", "README.md"),
+		NextSteps:   npmDev,
 	},
 	{
-		Language: "Node.js",
-		Name:     "Hono",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"node src/index.js\"\n  },\n  \"dependencies\": {\n    \"hono\": \"^4.6.3\",\n    \"@hono/node-server\": \"^1.12.2\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/index.js",
-				Content:      "import { Hono } from \"hono\";\nimport { serve } from \"@hono/node-server\";\n\nconst app = new Hono();\n\napp.get(\"/\", (c) => c.text(\"Hello from {{.Name}}\"));\n\nserve({ fetch: app.fetch, port: 3000 });\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nHono starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Node.js",
+		Name:        "Hono",
+		Description: "lightweight web framework",
+		Recommended: true,
+		Templates:   embedded("nodejs/hono", "package.json", "src/index.js", "README.md"),
+		NextSteps:   npmDev,
 	},
 	{
-		Language: "Node.js",
-		Name:     "NestJS",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"private\": true,\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"node --loader ts-node/esm src/main.ts\"\n  },\n  \"dependencies\": {\n    \"@nestjs/common\": \"^11.0.0\",\n    \"@nestjs/core\": \"^11.0.0\",\n    \"@nestjs/platform-express\": \"^11.0.0\",\n    \"reflect-metadata\": \"^0.2.2\",\n    \"rxjs\": \"^7.8.1\"\n  },\n  \"devDependencies\": {\n    \"ts-node\": \"^10.9.2\",\n    \"typescript\": \"^5.6.3\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "tsconfig.json",
-				Content:      "{\n  \"compilerOptions\": {\n    \"target\": \"ES2022\",\n    \"module\": \"ES2022\",\n    \"moduleResolution\": \"Bundler\",\n    \"experimentalDecorators\": true,\n    \"emitDecoratorMetadata\": true,\n    \"strict\": true,\n    \"outDir\": \"dist\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/app.module.ts",
-				Content:      "import { Module } from \"@nestjs/common\";\n\n@Module({})\nexport class AppModule {}\n",
-			},
-			{
-				RelativePath: "src/main.ts",
-				Content:      "import \"reflect-metadata\";\nimport { NestFactory } from \"@nestjs/core\";\nimport { AppModule } from \"./app.module.js\";\n\nasync function bootstrap() {\n  const app = await NestFactory.create(AppModule);\n  await app.listen(3000);\n  console.log(\"NestJS listening on 3000\");\n}\n\nbootstrap();\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nNestJS starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Node.js",
+		Name:        "NestJS",
+		Description: "typed Node framework",
+		Templates:   embedded("nodejs/nestjs", "package.json", "tsconfig.json", "src/app.module.ts", "src/main.ts", "README.md"),
+		NextSteps:   npmDev,
 	},
 	{
-		Language: "Bun",
-		Name:     "Vanilla",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"bun run src/index.ts\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/index.ts",
-				Content:      "console.log(\"hello from {{.Name}}\");\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nBun vanilla starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Bun",
+		Name:        "Vanilla",
+		Description: "minimal starter",
+		Templates:   embedded("bun/vanilla", "package.json", "src/index.ts", "README.md"),
+		NextSteps:   bunDev,
 	},
 	{
-		Language: "Bun",
-		Name:     "Bun",
-		Templates: []domain.Template{
-			{
-				RelativePath: "package.json",
-				Content:      "{\n  \"name\": \"{{.PackageName}}\",\n  \"version\": \"0.1.0\",\n  \"type\": \"module\",\n  \"scripts\": {\n    \"dev\": \"bun run src/index.ts\"\n  }\n}\n",
-			},
-			{
-				RelativePath: "src/index.ts",
-				Content:      "const server = Bun.serve({\n  port: 3000,\n  fetch() {\n    return new Response(\"Hello from {{.Name}}\");\n  },\n});\n\nconsole.log(`Listening on http://localhost:${server.port}`);\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nBun starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Bun",
+		Name:        "Bun",
+		Description: "Bun runtime server",
+		Templates:   embedded("bun/bun", "package.json", "src/index.ts", "README.md"),
+		NextSteps:   bunDev,
 	},
 	{
-		Language: "Python",
-		Name:     "Vanilla",
-		Templates: []domain.Template{
-			{
-				RelativePath: "app/main.py",
-				Content:      "def main():\n    print(\"hello from {{.Name}}\")\n\n\nif __name__ == \"__main__\":\n    main()\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nPython vanilla starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Python",
+		Name:        "Vanilla",
+		Description: "minimal starter",
+		Templates:   embedded("python/vanilla", "pyproject.toml", "app/main.py", "README.md"),
+		NextSteps:   []string{"python -m app.main"},
 	},
 	{
-		Language: "Python",
-		Name:     "FastAPI",
-		Templates: []domain.Template{
-			{
-				RelativePath: "requirements.txt",
-				Content:      "fastapi==0.115.5\nuvicorn==0.32.0\n",
-			},
-			{
-				RelativePath: "app/main.py",
-				Content:      "from fastapi import FastAPI\n\napp = FastAPI()\n\n@app.get(\"/\")\ndef read_root():\n    return {\"message\": \"hello from {{.Name}}\"}\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nFastAPI starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "Python",
+		Name:        "FastAPI",
+		Description: "Python API server",
+		Recommended: true,
+		DefaultPort: 8000,
+		Templates:   embedded("python/fastapi", "pyproject.toml", "requirements.txt", "app/main.py", "README.md"),
+		NextSteps:   []string{"pip install -r requirements.txt", "uvicorn app.main:app --reload --port {{.Port}}"},
 	},
 	{
-		Language: "PHP",
-		Name:     "Vanilla",
-		Templates: []domain.Template{
-			{
-				RelativePath: "src/index.php",
-				Content:      "<?php\n\necho \"hello from {{.Name}}\";\n",
-			},
-			{
-				RelativePath: "README.md",
-				Content:      "# {{.Name}}\n\nPHP vanilla starter generated by project-initiator.\n",
-			},
-		},
+		Language:    "PHP",
+		Name:        "Vanilla",
+		Description: "minimal starter",
+		Templates:   embedded("php/vanilla", "src/index.php", "README.md"),
 	},
 	{
-		Language:  "PHP",
-		Name:      "Laravel",
-		Generator: "composer-laravel",
+		Language:    "PHP",
+		Name:        "Laravel",
+		Description: "PHP web framework",
+		Generator:   "composer-laravel",
+		Requires:    "composer",
+		NextSteps:   []string{"php artisan serve"},
 	},
 }
@@ -0,0 +1,135 @@
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"project-initiator/internal/domain"
+)
+
+// commentSyntax is how a file type writes a one-line comment: a prefix,
+// and for block comments a suffix too.
+type commentSyntax struct {
+	open  string
+	close string
+}
+
+var (
+	slashComment = commentSyntax{open: "// "}
+	hashComment  = commentSyntax{open: "# "}
+	dashComment  = commentSyntax{open: "-- "}
+	htmlComment  = commentSyntax{open: "<!-- ", close: " -->"}
+)
+
+// headerComments maps file extensions to the comment syntax of the
+// generated-file header. Files with other extensions, JSON among them, get
+// no header.
+var headerComments = map[string]commentSyntax{
+	".go":       slashComment,
+	".js":       slashComment,
+	".mjs":      slashComment,
+	".cjs":      slashComment,
+	".jsx":      slashComment,
+	".ts":       slashComment,
+	".tsx":      slashComment,
+	".php":      slashComment,
+	".py":       hashComment,
+	".sh":       hashComment,
+	".toml":     hashComment,
+	".yaml":     hashComment,
+	".yml":      hashComment,
+	".graphqls": hashComment,
+	".sql":      dashComment,
+	".md":       htmlComment,
+	".html":     htmlComment,
+}
+
+// headerNames covers files that have no telling extension.
+var headerNames = map[string]commentSyntax{
+	"go.mod":           slashComment,
+	"Makefile":         hashComment,
+	"requirements.txt": hashComment,
+}
+
+// headerSkipped lists lockfiles, which tools rewrite and some of which
+// have an extension headerComments would otherwise match.
+var headerSkipped = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"bun.lock":          true,
+	"composer.lock":     true,
+	"poetry.lock":       true,
+	"uv.lock":           true,
+}
+
+// headerFor returns the comment syntax for the file at path, or false when
+// it gets no header.
+func headerFor(path string) (commentSyntax, bool) {
+	name := filepath.Base(path)
+	if headerSkipped[name] {
+		return commentSyntax{}, false
+	}
+	if syntax, ok := headerNames[name]; ok {
+		return syntax, true
+	}
+	syntax, ok := headerComments[strings.ToLower(filepath.Ext(name))]
+	return syntax, ok
+}
+
+// headerText is the generated-file header, without comment markers. The
+// date is ISO 8601, so it reads the same in every locale.
+func headerText(version string, date time.Time, name string) string {
+	return fmt.Sprintf("Generated by project-initiator %s on %s for %s — edit freely",
+		version, date.Format(time.DateOnly), strings.Join(strings.Fields(name), " "))
+}
+
+// stampHeaders starts every file with a known comment syntax with text as
+// a comment and a blank line. A shebang or "<?php" line stays first, so
+// scripts still run and PHP still parses.
+func stampHeaders(actions []domain.Action, text string) {
+	for i, action := range actions {
+		syntax, ok := headerFor(action.Path)
+		if !ok {
+			continue
+		}
+		line := text
+		if syntax.close != "" {
+			// A "--" could end an HTML comment early.
+			line = strings.ReplaceAll(line, "--", "- -")
+		}
+		comment := syntax.open + line + syntax.close + "\n"
+
+		if lead, rest, ok := leadLine(action.Content); ok {
+			actions[i].Content = lead + comment + rest
+		} else {
+			actions[i].Content = comment + "\n" + action.Content
+		}
+	}
+}
+
+// leadLine splits off a first line that must stay first: a shebang or a
+// PHP open tag.
+func leadLine(content string) (lead string, rest string, ok bool) {
+	if !strings.HasPrefix(content, "#!") && !strings.HasPrefix(content, "<?php") {
+		return "", content, false
+	}
+	lead, rest, found := strings.Cut(content, "\n")
+	if !found {
+		return content + "\n", "", true
+	}
+	return lead + "\n", rest, true
+}
+
+// toolVersion is the version this binary was built as: the module version
+// for go install builds, or "dev" for builds from a checkout.
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,187 @@
+package scaffold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+)
+
+// ManifestName is the file in the project dir where Apply records the
+// files it generated.
+const ManifestName = ".project-initiator.manifest"
+
+// Manifest records the generated files of a project and the hash of the
+// content each was generated with, so a later run can tell them apart from
+// files the user has changed or added.
+type Manifest struct {
+	Version int            `json:"version"`
+	Files   []ManifestFile `json:"files"`
+}
+
+// ManifestFile is one generated file.
+type ManifestFile struct {
+	// Path is slash-separated and relative to the project dir.
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// manifestVersion is written to new manifests.
+const manifestVersion = 1
+
+// FileState is how a planned file relates to what an earlier run generated.
+type FileState int
+
+const (
+	// FileNew is neither on disk nor in the manifest.
+	FileNew FileState = iota
+	// FileGenerated is on disk as the earlier run wrote it; it is safe to
+	// update.
+	FileGenerated
+	// FileModified was generated but has changed on disk since.
+	FileModified
+	// FileDeleted was generated but is no longer on disk.
+	FileDeleted
+	// FileUntracked is on disk but was not generated.
+	FileUntracked
+)
+
+func (s FileState) String() string {
+	switch s {
+	case FileNew:
+		return "new"
+	case FileGenerated:
+		return "generated"
+	case FileModified:
+		return "modified"
+	case FileDeleted:
+		return "deleted"
+	case FileUntracked:
+		return "untracked"
+	default:
+		return fmt.Sprintf("FileState(%d)", int(s))
+	}
+}
+
+// FileDiff is the state of one planned file.
+type FileDiff struct {
+	Path  string
+	State FileState
+	// Changed reports whether the planned content differs from what is on
+	// disk, or from what was generated for deleted files.
+	Changed bool
+}
+
+// ReadManifest reads the manifest in projectDir. A project without one
+// returns an error wrapping fs.ErrNotExist.
+func ReadManifest(projectDir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, ManifestName))
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", ManifestName, err)
+	}
+	return manifest, nil
+}
+
+// DiffManifest classifies every planned file against the manifest and the
+// files on disk, in plan order.
+func DiffManifest(plan domain.Plan, manifest Manifest) ([]FileDiff, error) {
+	generated := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		generated[file.Path] = file.SHA256
+	}
+
+	diffs := make([]FileDiff, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		planned := contentHash([]byte(action.Content))
+		hash, tracked := generated[manifestPath(plan.ProjectDir, action.Path)]
+
+		existing, err := os.ReadFile(action.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, apperrors.NewScaffoldError("diff manifest", err)
+		}
+		onDisk := err == nil
+
+		diff := FileDiff{Path: action.Path}
+		switch {
+		case onDisk && tracked:
+			current := contentHash(existing)
+			diff.State = FileGenerated
+			if current != hash {
+				diff.State = FileModified
+			}
+			diff.Changed = current != planned
+		case onDisk:
+			diff.State = FileUntracked
+			diff.Changed = contentHash(existing) != planned
+		case tracked:
+			diff.State = FileDeleted
+			diff.Changed = hash != planned
+		default:
+			diff.State = FileNew
+			diff.Changed = true
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// writeManifest records the files that now hold their planned content,
+// keeping entries from an earlier manifest for files this run left alone.
+// A missing or unreadable manifest is replaced rather than failing a run
+// whose files are already written.
+func (a *Applier) writeManifest(plan domain.Plan, current []domain.Action) error {
+	manifest, err := ReadManifest(plan.ProjectDir)
+	if err != nil {
+		manifest = Manifest{}
+	}
+	manifest.Version = manifestVersion
+
+	for _, action := range current {
+		file := ManifestFile{
+			Path:   manifestPath(plan.ProjectDir, action.Path),
+			SHA256: contentHash([]byte(action.Content)),
+		}
+		i := slices.IndexFunc(manifest.Files, func(f ManifestFile) bool { return f.Path == file.Path })
+		if i >= 0 {
+			manifest.Files[i] = file
+		} else {
+			manifest.Files = append(manifest.Files, file)
+		}
+	}
+	slices.SortFunc(manifest.Files, func(x, y ManifestFile) int { return strings.Compare(x.Path, y.Path) })
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return apperrors.NewScaffoldError("write manifest", err)
+	}
+	action := domain.Action{Path: filepath.Join(plan.ProjectDir, ManifestName), Content: string(data) + "\n"}
+	if err := a.writeFile(action); err != nil {
+		return apperrors.NewScaffoldError("write manifest", err)
+	}
+	return nil
+}
+
+func manifestPath(projectDir string, path string) string {
+	if rel, err := filepath.Rel(projectDir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+package scaffold
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+)
+
+// mergeRule combines the contents several contributors planned for files
+// whose name matches pattern.
+type mergeRule struct {
+	pattern string
+	merge   func(contents []string) string
+}
+
+// mergeRules lists the files that templates, libraries and generators may
+// each add to. Any other file planned twice with different content is a
+// conflict.
+var mergeRules = []mergeRule{
+	{pattern: ".gitignore", merge: mergeLines},
+	{pattern: "README.md", merge: mergeSections},
+}
+
+// mergeActions folds actions that write the same path into one, at the
+// first one's position, using the path's mergeRule. Identical duplicates
+// collapse; differing ones without a rule fail with a ScaffoldError.
+func mergeActions(actions []domain.Action, projectDir string) ([]domain.Action, error) {
+	contents := map[string][]string{}
+	for _, action := range actions {
+		contents[action.Path] = append(contents[action.Path], action.Content)
+	}
+
+	merged := make([]domain.Action, 0, len(actions))
+	for _, action := range actions {
+		parts, ok := contents[action.Path]
+		if !ok {
+			continue
+		}
+		delete(contents, action.Path)
+		if parts = distinct(parts); len(parts) > 1 {
+			rule, ok := ruleFor(action.Path)
+			if !ok {
+				rel, err := filepath.Rel(projectDir, action.Path)
+				if err != nil {
+					rel = action.Path
+				}
+				return nil, apperrors.NewScaffoldError("plan", fmt.Errorf("%d different contents planned for %s", len(parts), filepath.ToSlash(rel)))
+			}
+			action.Content = rule.merge(parts)
+		}
+		merged = append(merged, action)
+	}
+	return merged, nil
+}
+
+// distinct returns contents without exact repeats, in first-seen order.
+func distinct(contents []string) []string {
+	seen := make(map[string]bool, len(contents))
+	unique := make([]string, 0, len(contents))
+	for _, content := range contents {
+		if !seen[content] {
+			seen[content] = true
+			unique = append(unique, content)
+		}
+	}
+	return unique
+}
+
+func ruleFor(file string) (mergeRule, bool) {
+	for _, rule := range mergeRules {
+		if ok, _ := path.Match(rule.pattern, filepath.Base(file)); ok {
+			return rule, true
+		}
+	}
+	return mergeRule{}, false
+}
+
+// mergeLines joins line-based files such as .gitignore with a blank line
+// between contributions, keeping only the first copy of each line.
+func mergeLines(contents []string) string {
+	seen := map[string]bool{}
+	var blocks []string
+	for _, content := range contents {
+		var lines []string
+		for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+			key := strings.TrimSpace(line)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			lines = append(lines, line)
+		}
+		if block := strings.Trim(strings.Join(lines, "\n"), "\n"); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// mergeSections joins documents such as README.md with a blank line
+// between contributions, dropping repeated ones.
+func mergeSections(contents []string) string {
+	var sections []string
+	for _, content := range contents {
+		section := strings.Trim(content, "\n")
+		if section != "" && !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
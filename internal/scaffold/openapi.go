@@ -0,0 +1,123 @@
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+	"project-initiator/internal/library"
+	"project-initiator/internal/strutil"
+)
+
+// openAPIFile is where --openapi writes its spec, relative to the project.
+const openAPIFile = "openapi.yaml"
+
+// apiServer describes a server --openapi can document: its root response
+// and how it is described in the spec.
+type apiServer struct {
+	description string
+	// rootJSON is set when / returns {"message": ...} rather than text.
+	rootJSON bool
+}
+
+// apiServerFor returns the server the project generates, when --openapi
+// can document it: Express, FastAPI, or a Go project with the Gin library.
+func apiServerFor(framework domain.Framework, libraries []string) (apiServer, bool) {
+	switch {
+	case strings.EqualFold(framework.Language, "Node.js") && strings.EqualFold(framework.Name, "Express"):
+		return apiServer{description: framework.Description}, true
+	case strings.EqualFold(framework.Language, "Python") && strings.EqualFold(framework.Name, "FastAPI"):
+		return apiServer{description: framework.Description, rootJSON: true}, true
+	case strings.EqualFold(framework.Language, "Go") && strutil.ContainsFold(libraries, "gin"):
+		return apiServer{description: "Gin HTTP server", rootJSON: true}, true
+	}
+	return apiServer{}, false
+}
+
+// validateOpenAPI rejects --openapi for projects it cannot describe,
+// including generator frameworks: the generator owns their tree, so a
+// Gin server planned alongside would never be wired in.
+func validateOpenAPI(framework domain.Framework, libraries []string) error {
+	if framework.Generator != "" {
+		return apperrors.NewValidationError("openapi", fmt.Sprintf("%s/%s is created by the %s generator; --openapi supports Gin, FastAPI and Express templates", framework.Language, framework.Name, framework.Generator))
+	}
+	if _, ok := apiServerFor(framework, resolveLibraries(framework, libraries)); ok {
+		return nil
+	}
+	return apperrors.NewValidationError("openapi", fmt.Sprintf("%s/%s has no API server to describe; --openapi supports Gin, FastAPI and Express", framework.Language, framework.Name))
+}
+
+// addOpenAPISpec adds openapi.yaml to plan when the request asked for it.
+// The OpenAPI library already writes a fuller api/openapi.yaml, so a
+// project selecting it gets a warning instead.
+func addOpenAPISpec(plan *domain.Plan, project domain.Project, framework domain.Framework) {
+	if !project.OpenAPI {
+		return
+	}
+	if usesGoLibraries(project, framework) && library.NewManager(project).HasLibrary("openapi") {
+		plan.Warnings = append(plan.Warnings, "--openapi skipped: the OpenAPI library already writes api/openapi.yaml")
+		return
+	}
+	server, ok := apiServerFor(framework, project.Libraries)
+	if !ok {
+		return
+	}
+	plan.Actions = append(plan.Actions, domain.Action{
+		Path:    filepath.Join(project.Dir, openAPIFile),
+		Content: openAPISpec(project, server),
+	})
+}
+
+// openAPISpec is a minimal OpenAPI 3 document for the root and health
+// endpoints of the generated server.
+func openAPISpec(project domain.Project, server apiServer) string {
+	root := `          content:
+            text/plain:
+              schema:
+                type: string
+`
+	if server.rootJSON {
+		root = `          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  message:
+                    type: string
+`
+	}
+	return `openapi: 3.0.3
+info:
+  title: ` + strconv.Quote(project.Name) + `
+  description: ` + strconv.Quote(server.description+" generated by project-initiator.") + `
+  version: 0.1.0
+servers:
+  - url: http://localhost:` + strconv.Itoa(project.Port) + `
+paths:
+  /:
+    get:
+      operationId: getRoot
+      summary: Greeting
+      responses:
+        "200":
+          description: ` + strconv.Quote("A greeting from "+project.Name) + `
+` + root + `  /health:
+    get:
+      operationId: getHealth
+      summary: Health check
+      responses:
+        "200":
+          description: The service is up
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  status:
+                    type: string
+                    example: ok
+`
+}
@@ -0,0 +1,122 @@
+package scaffold
+
+import (
+	"strings"
+
+	"project-initiator/internal/domain"
+	"project-initiator/internal/library"
+	"project-initiator/internal/strutil"
+)
+
+// Disabled lists languages and "Language/Framework" combos that should be
+// hidden when options are enumerated.
+type Disabled struct {
+	Languages  []string
+	Frameworks []string
+}
+
+// Has reports whether the language, or the language/framework combo, is disabled.
+func (d Disabled) Has(language string, framework string) bool {
+	language = strings.TrimSpace(language)
+	framework = strings.TrimSpace(framework)
+
+	for _, disabled := range d.Languages {
+		if strings.EqualFold(strings.TrimSpace(disabled), language) {
+			return true
+		}
+	}
+
+	combo := language + "/" + framework
+	for _, disabled := range d.Frameworks {
+		if strings.EqualFold(strings.TrimSpace(disabled), combo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListOptions returns the options that are not disabled, preserving their order.
+func ListOptions(options []domain.Framework, disabled Disabled) []domain.Framework {
+	listed := make([]domain.Framework, 0, len(options))
+	for _, opt := range options {
+		if disabled.Has(opt.Language, opt.Name) {
+			continue
+		}
+		listed = append(listed, opt)
+	}
+	return listed
+}
+
+// languageNextSteps are the commands to run in a new project whose
+// framework lists none, keyed by lower-case language.
+var languageNextSteps = map[string][]string{
+	"go":      {"go mod tidy"},
+	"node.js": {"npm install"},
+	"bun":     {"bun install"},
+	"python":  {"pip install -r requirements.txt"},
+}
+
+// NextSteps returns the commands to run in a new project of a default
+// language/framework combo that listens on port.
+func NextSteps(language string, framework string, port int) []string {
+	return DefaultPlanner().NextSteps(language, framework, port)
+}
+
+// NextSteps returns the rendered next steps of a combo: the framework's
+// own, or else its language's. It is nil for an unknown combo whose
+// language has none.
+func (p *Planner) NextSteps(language string, framework string, port int) []string {
+	var steps []string
+	if opt, err := p.findFramework(language, framework); err == nil {
+		steps = opt.NextSteps
+	}
+	if len(steps) == 0 {
+		steps = languageNextSteps[strings.ToLower(language)]
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	rendered := make([]string, 0, len(steps))
+	for _, step := range steps {
+		// CheckTemplates rejects broken steps before a plan is made, so a
+		// failure here only leaves the step as written.
+		if out, err := p.renderer.Render(step, step, TemplateData{Port: port}); err == nil {
+			step = out
+		}
+		rendered = append(rendered, step)
+	}
+	return rendered
+}
+
+// LibrariesFor returns the libraries the default options offer for a
+// language/framework combo, or nil when the combo is unknown or offers none.
+func LibrariesFor(language string, framework string) []domain.Library {
+	return DefaultPlanner().LibrariesFor(language, framework)
+}
+
+// LibrariesFor returns the libraries the planner's options offer for a combo.
+func (p *Planner) LibrariesFor(language string, framework string) []domain.Library {
+	opt, err := p.findFramework(language, framework)
+	if err != nil {
+		return nil
+	}
+	return OfferedLibraries(opt)
+}
+
+// OfferedLibraries returns an option's libraries with blank and duplicate
+// (case-insensitive) names dropped, in declaration order. Libraries that are
+// incompatible with the option's framework are dropped too.
+func OfferedLibraries(opt domain.Framework) []domain.Library {
+	var libs []domain.Library
+	seen := map[string]bool{}
+	for _, lib := range opt.Libraries {
+		key := strutil.Key(lib.Name)
+		if key == "" || seen[key] || library.Incompatible(lib.Name, opt.Name) {
+			continue
+		}
+		seen[key] = true
+		libs = append(libs, lib)
+	}
+	return libs
+}
@@ -0,0 +1,53 @@
+package scaffold
+
+import (
+	"slices"
+	"strings"
+
+	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+	"project-initiator/internal/pkgname"
+)
+
+// writesPackageJSON reports whether framework's project is an npm package.
+func writesPackageJSON(framework domain.Framework) bool {
+	return slices.ContainsFunc(framework.Templates, func(t domain.Template) bool {
+		return t.RelativePath == "package.json"
+	})
+}
+
+// validatePackageNames checks the name the project is published under
+// against its ecosystem's rules: npm for package.json projects, PyPI for
+// Python ones.
+func validatePackageNames(framework domain.Framework, req Request) error {
+	slug := req.slug()
+	if writesPackageJSON(framework) {
+		if err := pkgname.ValidateNPMScope(req.NPMScope); err != nil {
+			return apperrors.NewValidationError("npmScope", err.Error())
+		}
+		if _, err := pkgname.NPM(req.NPMScope, slug); err != nil {
+			return apperrors.NewValidationError("name", err.Error())
+		}
+	}
+	if strings.EqualFold(framework.Language, "Python") {
+		if _, err := pkgname.PyPI(slug); err != nil {
+			return apperrors.NewValidationError("name", err.Error())
+		}
+	}
+	return nil
+}
+
+// packageNames returns the npm and PyPI names for project. Plan validates
+// them first; for other ecosystems, which never render them, they fall
+// back to the slug.
+func packageNames(project domain.Project) (npm string, pypi string) {
+	npm, err := pkgname.NPM(project.NPMScope, project.Slug)
+	if err != nil {
+		npm = project.Slug
+	}
+	pypi, err = pkgname.PyPI(project.Slug)
+	if err != nil {
+		pypi = project.Slug
+	}
+	return npm, pypi
+}
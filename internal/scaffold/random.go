@@ -0,0 +1,52 @@
+package scaffold
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// Ports RandomPort picks from: above the well-known and common development
+// ports, below the ephemeral range.
+const (
+	randomPortMin = 10000
+	randomPortMax = 49151
+)
+
+// newRandom returns the source a project's templates draw random values
+// from. A zero seed means a time-based one, so values differ from run to
+// run. The project slug picks the stream, so projects sharing a seed, as
+// in a monorepo, still get different ports and secrets.
+func newRandom(seed uint64, slug string) *rand.Rand {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	stream := fnv.New64a()
+	_, _ = stream.Write([]byte(slug))
+	return rand.New(rand.NewPCG(seed, stream.Sum64()))
+}
+
+// RandomHex returns n random bytes as 2n hex digits, like openssl rand -hex,
+// for placeholder secrets such as {{.RandomHex 32}}. With Request.Seed set
+// the same template renders the same value.
+func (d TemplateData) RandomHex(n int) string {
+	b := make([]byte, max(n, 0))
+	for i := range b {
+		b[i] = byte(d.source().Uint32())
+	}
+	return hex.EncodeToString(b)
+}
+
+// RandomPort returns a port from 10000 to 49151, for servers that should
+// not collide with others started from the same template.
+func (d TemplateData) RandomPort() int {
+	return randomPortMin + d.source().IntN(randomPortMax-randomPortMin+1)
+}
+
+func (d TemplateData) source() *rand.Rand {
+	if d.random == nil {
+		return newRandom(0, "")
+	}
+	return d.random
+}
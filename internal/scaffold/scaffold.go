@@ -2,17 +2,25 @@
 package scaffold
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"math/rand/v2"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"project-initiator/internal/domain"
 	apperrors "project-initiator/internal/errors"
 	"project-initiator/internal/library"
+	"project-initiator/internal/strutil"
 	"project-initiator/internal/template"
 )
 
@@ -26,12 +34,58 @@ type Request struct {
 	Dir       string
 	DryRun    bool
 	Libraries []string
+	// Flatten drops a trailing Dir segment that repeats the project slug, so
+	// "projects/my-app" + "my-app" does not nest the project twice.
+	Flatten bool
+	// Module is the Go module path. When empty it is ModulePrefix + "/" +
+	// the slug, or just the slug without a prefix.
+	Module       string
+	ModulePrefix string
+	// Layout is LayoutByLanguage or LayoutFlat; empty means by language.
+	Layout string
+	// Port is the port generated servers listen on; zero means the
+	// framework's DefaultPort, or domain.DefaultPort.
+	Port int
+	// OpenAPI adds an openapi.yaml describing the server's root and health
+	// endpoints. Only Gin, FastAPI and Express projects support it.
+	OpenAPI bool
+	// NPMScope, such as "@acme", prefixes the package.json name of
+	// JavaScript projects.
+	NPMScope string
+	// CollapseDupes drops words of the slug that repeat the word before
+	// them, so "my my app" becomes my-app rather than my-my-app.
+	CollapseDupes bool
+	// Seed fixes the values of RandomHex and RandomPort in templates, so
+	// the same request renders the same files. Zero means a time-based
+	// seed.
+	Seed uint64
+	// GeneratedHeader starts code files with a comment naming the tool
+	// version, today's date and the project; see stampHeaders.
+	GeneratedHeader bool
 }
 
+// Project directory layouts. Template and generator frameworks share them.
+const (
+	// LayoutByLanguage nests projects in a language folder: <dir>/<Language>/<slug>.
+	LayoutByLanguage = "by-language"
+	// LayoutFlat puts projects straight in the base dir: <dir>/<slug>.
+	LayoutFlat = "flat"
+)
+
+// DefaultMaxPlanBytes caps the total content a plan may write when the
+// Planner sets no limit of its own.
+const DefaultMaxPlanBytes = 64 << 20
+
 // Planner handles project planning.
 type Planner struct {
 	renderer *template.Renderer
 	options  []domain.Framework
+	// MaxPlanBytes caps the total content of a plan's actions, so a
+	// runaway template cannot make Apply buffer and write hundreds of
+	// megabytes. Zero means DefaultMaxPlanBytes.
+	MaxPlanBytes int
+	// now dates generated-file headers; tests replace it.
+	now func() time.Time
 }
 
 // NewPlanner creates a new planner with the given options.
@@ -39,6 +93,7 @@ func NewPlanner(options []domain.Framework) *Planner {
 	return &Planner{
 		renderer: template.NewRenderer(),
 		options:  options,
+		now:      time.Now,
 	}
 }
 
@@ -47,8 +102,84 @@ func DefaultPlanner() *Planner {
 	return NewPlanner(Frameworks)
 }
 
+// Validate checks a request against the default options without planning it.
+func Validate(req Request) error {
+	return DefaultPlanner().Validate(req)
+}
+
+// Validate checks that the request has a name, targets a known option and
+// selects only libraries that option offers and that are compatible with its
+// framework. Failures are ValidationErrors.
+func (p *Planner) Validate(req Request) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return apperrors.NewValidationError("name", "project name is required")
+	}
+	switch req.Layout {
+	case "", LayoutByLanguage, LayoutFlat:
+	default:
+		return apperrors.NewValidationError("layout", fmt.Sprintf("unknown layout %q: want %s or %s", req.Layout, LayoutByLanguage, LayoutFlat))
+	}
+	if strings.Contains(req.Dir, "{name}") && !usableAsDir(strings.TrimSpace(req.Name)) {
+		return apperrors.NewValidationError("dir", fmt.Sprintf("{name} needs a name usable as a directory, without / or \\; use {slug} for %q", req.Name))
+	}
+	if req.Port < 0 || req.Port > 65535 {
+		return apperrors.NewValidationError("port", fmt.Sprintf("%d is not a TCP port (1-65535)", req.Port))
+	}
+
+	framework, err := p.findFramework(req.Language, req.Framework)
+	if err != nil {
+		return err
+	}
+
+	if err := validateLibraries(framework, req.Libraries); err != nil {
+		return err
+	}
+	if err := validatePackageNames(framework, req); err != nil {
+		return err
+	}
+	if req.OpenAPI {
+		return validateOpenAPI(framework, req.Libraries)
+	}
+	return nil
+}
+
+func validateLibraries(framework domain.Framework, libraries []string) error {
+	libraries = resolveLibraries(framework, libraries)
+	for _, lib := range libraries {
+		lib = strings.TrimSpace(lib)
+		if lib == "" {
+			continue
+		}
+		if library.Incompatible(lib, framework.Name) {
+			return apperrors.NewValidationError("libraries", fmt.Sprintf("library %q cannot be added to the %s framework", lib, framework.Name))
+		}
+		if !offersLibrary(framework, lib) {
+			return apperrors.NewValidationError("libraries", fmt.Sprintf("%s/%s does not offer library %q", framework.Language, framework.Name, lib))
+		}
+		for _, need := range library.Needs(lib) {
+			if !slices.ContainsFunc(libraries, func(l string) bool { return strings.EqualFold(strings.TrimSpace(l), need) }) {
+				return apperrors.NewValidationError("libraries", fmt.Sprintf("library %q needs %q to be selected too", lib, need))
+			}
+		}
+	}
+	return nil
+}
+
+func offersLibrary(framework domain.Framework, name string) bool {
+	for _, lib := range OfferedLibraries(framework) {
+		if strings.EqualFold(lib.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Plan creates a scaffolding plan for the given request.
 func (p *Planner) Plan(req Request) (domain.Plan, error) {
+	if err := p.Validate(req); err != nil {
+		return domain.Plan{}, err
+	}
+
 	framework, err := p.findFramework(req.Language, req.Framework)
 	if err != nil {
 		return domain.Plan{}, err
@@ -59,91 +190,268 @@ func (p *Planner) Plan(req Request) (domain.Plan, error) {
 		return domain.Plan{}, err
 	}
 
-	return p.generatePlan(project, framework)
+	plan, err := p.generatePlan(project, framework)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	if plan.Actions, err = mergeActions(plan.Actions, plan.ProjectDir); err != nil {
+		return domain.Plan{}, err
+	}
+	if req.GeneratedHeader {
+		stampHeaders(plan.Actions, headerText(toolVersion(), p.now(), project.Name))
+	}
+	if err := p.checkPlanSize(plan); err != nil {
+		return domain.Plan{}, err
+	}
+	if notices := p.deprecations(req); len(notices) > 0 {
+		plan.Warnings = append(notices, plan.Warnings...)
+	}
+	return plan, nil
+}
+
+// checkPlanSize fails when the plan's actions hold more content than
+// MaxPlanBytes.
+func (p *Planner) checkPlanSize(plan domain.Plan) error {
+	limit := cmp.Or(p.MaxPlanBytes, DefaultMaxPlanBytes)
+	total := 0
+	for _, action := range plan.Actions {
+		total += len(action.Content)
+	}
+	if total > limit {
+		return apperrors.NewScaffoldError("plan", fmt.Errorf("plan writes %d bytes, over the %d byte limit", total, limit))
+	}
+	return nil
+}
+
+// ProjectPath returns the directory Plan would create for req, after the
+// same validation, layout and slug rules, without generating any actions.
+func (p *Planner) ProjectPath(req Request) (string, error) {
+	if err := p.Validate(req); err != nil {
+		return "", err
+	}
+
+	framework, err := p.findFramework(req.Language, req.Framework)
+	if err != nil {
+		return "", err
+	}
+
+	project, err := p.buildProject(req, framework)
+	if err != nil {
+		return "", err
+	}
+	return project.Dir, nil
+}
+
+// expandDirPlaceholders fills in the {name} and {slug} placeholders a
+// request's Dir may hold, such as "~/work/{slug}".
+func expandDirPlaceholders(dir string, name string, slug string) string {
+	return strings.NewReplacer("{name}", name, "{slug}", slug).Replace(dir)
+}
+
+// ExpandDir fills in the {name} and {slug} placeholders of dir for a
+// directory called name, the way Plan does for a request's Dir.
+func ExpandDir(dir string, name string) string {
+	return expandDirPlaceholders(dir, name, Slugify(name))
+}
+
+// NamedByPlaceholder reports whether the last segment of dir is filled in
+// from the project's name, as in "~/work/{slug}". A project planned under
+// such a dir repeats its name there on purpose.
+func NamedByPlaceholder(dir string) bool {
+	last := filepath.Base(filepath.Clean(dir))
+	return strings.Contains(last, "{name}") || strings.Contains(last, "{slug}")
+}
+
+// usableAsDir reports whether name can stand in for {name} as a single
+// path segment.
+func usableAsDir(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, "/\\\x00")
 }
 
 func (p *Planner) buildProject(req Request, framework domain.Framework) (domain.Project, error) {
 	name := strings.TrimSpace(req.Name)
-	if name == "" {
-		return domain.Project{}, apperrors.NewValidationError("name", "project name is required")
-	}
 
 	dir := strings.TrimSpace(req.Dir)
 	if dir == "" {
 		dir = "."
 	}
 
-	slug := slugify(name)
-	languageDir := cleanLanguageDir(framework.Language)
-	projectDir := filepath.Join(filepath.Clean(dir), languageDir, slug)
+	slug := req.slug()
+	dir = filepath.Clean(expandDirPlaceholders(dir, name, slug))
+	if req.Flatten && Slugify(filepath.Base(dir)) == slug {
+		dir = filepath.Dir(dir)
+	}
+	projectDir := filepath.Join(dir, slug)
+	if req.Layout != LayoutFlat {
+		projectDir = filepath.Join(dir, cleanLanguageDir(framework.Language), slug)
+	}
+
+	module := strings.TrimSpace(req.Module)
+	if module == "" {
+		module = slug
+		if prefix := strings.Trim(strings.TrimSpace(req.ModulePrefix), "/"); prefix != "" {
+			module = prefix + "/" + slug
+		}
+	}
+
+	port := cmp.Or(req.Port, framework.DefaultPort, domain.DefaultPort)
 
 	return domain.Project{
 		Language:  framework.Language,
 		Framework: framework.Name,
 		Name:      name,
 		Slug:      slug,
-		Module:    slug,
+		Module:    module,
 		Dir:       projectDir,
-		Libraries: req.Libraries,
+		BaseDir:   dir,
+		Libraries: resolveLibraries(framework, req.Libraries),
+		Port:      port,
+		OpenAPI:   req.OpenAPI,
+		NPMScope:  req.NPMScope,
+		Seed:      req.Seed,
 	}, nil
 }
 
 func (p *Planner) generatePlan(project domain.Project, framework domain.Framework) (domain.Plan, error) {
 	actions, err := p.generateActions(project, framework)
 	if err != nil {
+		// Render errors already name the template that failed.
+		var scaffoldErr *apperrors.ScaffoldError
+		if errors.As(err, &scaffoldErr) {
+			return domain.Plan{}, err
+		}
 		return domain.Plan{}, apperrors.NewScaffoldError("generate actions", err)
 	}
 
-	return domain.Plan{
+	plan := domain.Plan{
 		ProjectDir: project.Dir,
+		BaseDir:    project.BaseDir,
 		Actions:    actions,
 		Generator:  framework.Generator,
-	}, nil
+	}
+	if usesGoLibraries(project, framework) {
+		plan.Warnings = library.NewManager(project).Warnings()
+	} else if framework.Generator != "" && len(project.Libraries) > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("libraries skipped: %s/%s is created by the %s generator, not from templates: %s", framework.Language, framework.Name, framework.Generator, strings.Join(project.Libraries, ", ")))
+	}
+	if servesPort(project, framework) {
+		plan.Port = project.Port
+	}
+	addOpenAPISpec(&plan, project, framework)
+	return plan, nil
+}
+
+// usesGoLibraries reports whether the project gets the selected Go
+// libraries' files. Generator-backed frameworks build their own tree, so
+// templated library files would be mixed into generated output.
+func usesGoLibraries(project domain.Project, framework domain.Framework) bool {
+	return strings.EqualFold(project.Language, "go") && framework.Generator == ""
+}
+
+// servesPort reports whether the generated project starts a server on the
+// project's port: a template that listens on {{.Port}}, or a Go library
+// that adds a server.
+func servesPort(project domain.Project, framework domain.Framework) bool {
+	if usesGoLibraries(project, framework) && library.NewManager(project).Serves() {
+		return true
+	}
+	for _, tmpl := range framework.Templates {
+		if strings.Contains(tmpl.Content, "{{.Port}}") {
+			return true
+		}
+	}
+	return false
+}
+
+// markScripts makes files that start with a shebang, such as an
+// entrypoint a Makefile runs, executable unless their mode is already set.
+func markScripts(actions []domain.Action) {
+	for i := range actions {
+		if actions[i].Mode == 0 && strings.HasPrefix(actions[i].Content, "#!") {
+			actions[i].Mode = 0o755
+		}
+	}
+}
+
+// CheckTemplates parses every template of framework, content and path,
+// and its next steps, and reports each one that is broken rather than
+// stopping at the first.
+func (p *Planner) CheckTemplates(framework domain.Framework) error {
+	var errs []error
+	for _, tmpl := range framework.Templates {
+		for _, source := range []string{tmpl.RelativePath, tmpl.Content} {
+			if err := p.renderer.Check(tmpl.RelativePath, source); err != nil {
+				errs = append(errs, apperrors.NewScaffoldError("render "+tmpl.RelativePath, err))
+				break
+			}
+		}
+	}
+	for _, step := range framework.NextSteps {
+		if err := p.renderer.Check(step, step); err != nil {
+			errs = append(errs, apperrors.NewScaffoldError("render next step "+step, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (p *Planner) generateActions(project domain.Project, framework domain.Framework) ([]domain.Action, error) {
 	data := p.buildTemplateData(project)
 	actions := make([]domain.Action, 0)
 
+	if err := p.CheckTemplates(framework); err != nil {
+		return nil, err
+	}
+
 	// Generate base template actions
 	for _, tmpl := range framework.Templates {
-		content, err := p.renderer.Render(tmpl.Content, data)
+		content, err := p.renderer.Render(tmpl.RelativePath, tmpl.Content, data)
 		if err != nil {
-			return nil, fmt.Errorf("render template content: %w", err)
+			return nil, apperrors.NewScaffoldError("render "+tmpl.RelativePath, err)
 		}
 
-		relPath, err := p.renderer.Render(tmpl.RelativePath, data)
+		relPath, err := p.renderer.Render(tmpl.RelativePath, tmpl.RelativePath, data)
 		if err != nil {
-			return nil, fmt.Errorf("render template path: %w", err)
+			return nil, apperrors.NewScaffoldError("render "+tmpl.RelativePath, err)
 		}
 
 		path := filepath.Join(project.Dir, filepath.FromSlash(relPath))
-		actions = append(actions, domain.Action{Path: path, Content: content})
+		actions = append(actions, domain.Action{Path: path, Content: content, Mode: tmpl.Mode})
 	}
 
 	// Apply library-specific modifications for Go projects
-	if strings.EqualFold(project.Language, "go") {
+	if usesGoLibraries(project, framework) {
 		actions = p.applyGoLibraries(actions, project)
 	}
+	markScripts(actions)
 
-	return actions, nil
+	vars, err := p.envVars(project, framework, data)
+	if err != nil {
+		return nil, err
+	}
+	return addEnvExample(actions, project.Dir, vars), nil
 }
 
 func (p *Planner) buildTemplateData(project domain.Project) TemplateData {
 	selectedLibs := make(map[string]bool)
 	for _, lib := range project.Libraries {
-		selectedLibs[strings.ToLower(strings.TrimSpace(lib))] = true
+		selectedLibs[strutil.Key(lib)] = true
 	}
 
+	npmName, pypiName := packageNames(project)
+
 	return TemplateData{
 		Name:        project.Name,
 		PackageName: project.Slug,
+		NPMName:     npmName,
+		PyPIName:    pypiName,
 		Module:      project.Module,
 		Framework:   project.Framework,
 		GoVersion:   goVersionTag(),
 		UseGin:      selectedLibs["gin"],
 		UseGorm:     selectedLibs["gorm"],
 		UseSqlc:     selectedLibs["sqlc"],
+		Port:        project.Port,
+		random:      newRandom(project.Seed, project.Slug),
 	}
 }
 
@@ -151,7 +459,7 @@ func (p *Planner) applyGoLibraries(actions []domain.Action, project domain.Proje
 	libMgr := library.NewManager(project)
 
 	// Check if any libraries are enabled
-	if !libMgr.HasLibrary("gin") && !libMgr.HasLibrary("gorm") && !libMgr.HasLibrary("sqlc") {
+	if !libMgr.HasAny() {
 		return actions
 	}
 
@@ -173,12 +481,8 @@ func (p *Planner) applyGoLibraries(actions []domain.Action, project domain.Proje
 	goVersion := goVersionTag()
 
 	// Add library-specific files
-	if libMgr.HasLibrary("gin") || libMgr.HasLibrary("gorm") || libMgr.HasLibrary("sqlc") {
-		// Determine main file path based on framework
-		mainPath := filepath.Join(project.Dir, "main.go")
-		if strings.EqualFold(project.Framework, "cobra") {
-			mainPath = filepath.Join(project.Dir, "cmd", project.Slug, "main.go")
-		}
+	if libMgr.HasAny() {
+		mainPath := filepath.Join(project.Dir, filepath.FromSlash(library.MainFile(project)))
 
 		actions = append(actions, domain.Action{
 			Path:    mainPath,
@@ -195,15 +499,40 @@ func (p *Planner) applyGoLibraries(actions []domain.Action, project domain.Proje
 	}
 
 	// Add library-specific file templates
-	for path, content := range libMgr.FileTemplates() {
+	// Sorted so the same request always plans the same file order.
+	libFiles := libMgr.FileTemplates()
+	for _, path := range slices.Sorted(maps.Keys(libFiles)) {
 		fullPath := filepath.Join(project.Dir, filepath.FromSlash(path))
-		actions = append(actions, domain.Action{Path: fullPath, Content: content})
+		actions = append(actions, domain.Action{Path: fullPath, Content: libFiles[path]})
 	}
 
 	return actions
 }
 
+// findFramework returns the option for lang/framework. A deprecated option
+// with a ReplacedBy resolves to its replacement; deprecations describes the
+// swap for the plan's warnings.
 func (p *Planner) findFramework(lang, framework string) (domain.Framework, error) {
+	opt, err := p.lookupFramework(lang, framework)
+	if err != nil {
+		return opt, err
+	}
+
+	seen := map[string]bool{}
+	for opt.ReplacedBy != "" && !seen[strings.ToLower(opt.Name)] {
+		seen[strings.ToLower(opt.Name)] = true
+		replacement, err := p.lookupFramework(opt.Language, opt.ReplacedBy)
+		if err != nil {
+			return domain.Framework{}, apperrors.NewValidationError("framework", fmt.Sprintf("%s/%s is deprecated and its replacement %s has no template", opt.Language, opt.Name, opt.ReplacedBy))
+		}
+		opt = replacement
+	}
+	return opt, nil
+}
+
+// lookupFramework returns the option named lang/framework as declared,
+// without following ReplacedBy.
+func (p *Planner) lookupFramework(lang, framework string) (domain.Framework, error) {
 	lang = strings.TrimSpace(lang)
 	framework = strings.TrimSpace(framework)
 
@@ -213,59 +542,445 @@ func (p *Planner) findFramework(lang, framework string) (domain.Framework, error
 		}
 	}
 
-	return domain.Framework{}, fmt.Errorf("no template for %s / %s", lang, framework)
+	return domain.Framework{}, apperrors.NewValidationError("framework", fmt.Sprintf("no template for %s / %s", lang, framework))
+}
+
+// resolveLibraries swaps deprecated libraries that have a replacement for
+// it, dropping blanks and any that end up selected twice.
+func resolveLibraries(framework domain.Framework, libraries []string) []string {
+	resolved := make([]string, 0, len(libraries))
+	for _, name := range libraries {
+		if lib, ok := declaredLibrary(framework, name); ok && lib.ReplacedBy != "" {
+			name = lib.ReplacedBy
+		}
+		resolved = append(resolved, name)
+	}
+	return strutil.Unique(resolved)
+}
+
+func declaredLibrary(framework domain.Framework, name string) (domain.Library, bool) {
+	for _, lib := range framework.Libraries {
+		if strings.EqualFold(lib.Name, strings.TrimSpace(name)) {
+			return lib, true
+		}
+	}
+	return domain.Library{}, false
+}
+
+// deprecations describes each deprecated framework or library req names,
+// and what is used instead.
+func (p *Planner) deprecations(req Request) []string {
+	var notices []string
+	if opt, err := p.lookupFramework(req.Language, req.Framework); err == nil {
+		if notice := deprecationNotice(opt.Language+"/"+opt.Name, opt.Deprecated, opt.ReplacedBy, opt.Language+"/"+opt.ReplacedBy); notice != "" {
+			notices = append(notices, notice)
+		}
+	}
+	framework, err := p.findFramework(req.Language, req.Framework)
+	if err != nil {
+		return notices
+	}
+	for _, name := range req.Libraries {
+		if lib, ok := declaredLibrary(framework, name); ok {
+			if notice := deprecationNotice(fmt.Sprintf("library %q", lib.Name), lib.Deprecated, lib.ReplacedBy, fmt.Sprintf("%q", lib.ReplacedBy)); notice != "" {
+				notices = append(notices, notice)
+			}
+		}
+	}
+	return notices
+}
+
+// deprecationNotice reads e.g. `Go/Bun is deprecated: use Elysia; using
+// Go/Elysia instead`, or is empty when nothing is deprecated.
+func deprecationNotice(subject, message, replacedBy, replacement string) string {
+	if message == "" && replacedBy == "" {
+		return ""
+	}
+	notice := subject + " is deprecated"
+	if message != "" {
+		notice += ": " + message
+	}
+	if replacedBy != "" {
+		notice += "; using " + replacement + " instead"
+	}
+	return notice
 }
 
 // TemplateData holds data for template rendering.
 type TemplateData struct {
 	Name        string
 	PackageName string
-	Module      string
-	Framework   string
-	GoVersion   string
-	UseGin      bool
-	UseGorm     bool
-	UseSqlc     bool
+	// NPMName is the package.json name, scoped when NPMScope is set.
+	NPMName string
+	// PyPIName is the PEP 503 normalized name for pyproject.toml.
+	PyPIName  string
+	Module    string
+	Framework string
+	GoVersion string
+	UseGin    bool
+	UseGorm   bool
+	UseSqlc   bool
+	Port      int
+
+	// random backs RandomHex and RandomPort. Every template of a plan
+	// shares it, so they draw distinct values in a fixed order.
+	random *rand.Rand
 }
 
+// DefaultApplyIgnore lists paths, relative to the project dir, that Apply
+// leaves alone unless configured otherwise. A trailing "/" matches a
+// directory and everything under it.
+var DefaultApplyIgnore = []string{".git/", ".idea/", ".vscode/", ".DS_Store"}
+
 // Applier handles applying scaffold plans.
-type Applier struct{}
+type Applier struct {
+	// Force overwrites existing files instead of failing on the first one.
+	Force bool
+	// SkipExisting leaves existing files untouched instead of failing.
+	SkipExisting bool
+	// Ignore holds path.Match patterns, relative to the plan's project dir,
+	// for files that are neither conflict-checked nor written.
+	Ignore []string
+	// FileMode and DirMode, when non-zero, are set on created files and
+	// directories exactly, ignoring the umask. Otherwise files are created
+	// 0666 and directories 0777, narrowed by the umask.
+	FileMode os.FileMode
+	DirMode  os.FileMode
+	// AllowSymlinkedDirs lets Apply write through directories below the
+	// plan's base dir that are symlinks, which it otherwise refuses.
+	AllowSymlinkedDirs bool
+	// DurableWrites writes each file through a synced temp file renamed
+	// into place, so a crash cannot leave it empty or truncated. It is
+	// slower, and meant for network filesystems.
+	DurableWrites bool
+	// Atomic builds a new project in a staging dir beside it and renames
+	// that into place only once every file is written, so a failure
+	// leaves no half-written project. A project dir that already exists is
+	// written in place as usual.
+	Atomic bool
+	// Progress, if set, is called for each planned file as Apply handles it.
+	Progress func(FileEvent)
+}
+
+// FileStatus is what Apply did with one planned file.
+type FileStatus int
+
+const (
+	FileCreated FileStatus = iota
+	FileSkipped
+	FileFailed
+)
+
+// FileEvent reports the outcome for one planned file.
+type FileEvent struct {
+	Path   string
+	Status FileStatus
+	// Reason says why a file was skipped: "identical", "exists" or "ignored".
+	Reason string
+	// Err is set when Status is FileFailed.
+	Err error
+}
 
 // NewApplier creates a new applier.
 func NewApplier() *Applier {
 	return &Applier{}
 }
 
-// Apply executes the plan by writing files to disk.
-func (a *Applier) Apply(plan domain.Plan, dryRun bool) error {
+// Apply executes the plan by writing files to disk. Existing files whose
+// content already matches the plan are skipped rather than treated as
+// conflicts. Every file that ends up with its planned content is recorded
+// in the project's manifest; see ManifestName.
+//
+// Apply returns the files it wrote, in plan order, which leaves out
+// ignored, identical and skipped files. On error they are the files
+// written before it.
+func (a *Applier) Apply(plan domain.Plan, dryRun bool) ([]string, error) {
+	if err := a.checkPaths(plan); err != nil {
+		return nil, err
+	}
+
 	// Check for existing files first
-	for _, action := range plan.Actions {
-		if _, err := os.Stat(action.Path); err == nil {
-			return fmt.Errorf("%w: %s", apperrors.ErrProjectExists, action.Path)
-		} else if !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("check file existence: %w", err)
+	if !a.Force && !a.SkipExisting {
+		conflicts, err := a.Conflicts(plan)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrProjectExists, conflicts[0])
+		}
+	}
+
+	if dryRun {
+		return nil, nil
+	}
+	if a.Atomic && plan.ProjectDir != "" {
+		if _, err := os.Lstat(plan.ProjectDir); errors.Is(err, fs.ErrNotExist) {
+			return a.applyAtomic(plan)
 		}
 	}
+	return a.write(plan)
+}
 
-	// Apply actions
+// write writes plan's files and manifest in place.
+func (a *Applier) write(plan domain.Plan) ([]string, error) {
+	var written []string
+	var current []domain.Action
 	for _, action := range plan.Actions {
-		if dryRun {
+		if a.ignored(plan, action.Path) {
+			a.report(FileEvent{Path: action.Path, Status: FileSkipped, Reason: "ignored"})
 			continue
 		}
+		if existing, err := os.ReadFile(action.Path); err == nil {
+			if string(existing) == action.Content {
+				current = append(current, action)
+				a.report(FileEvent{Path: action.Path, Status: FileSkipped, Reason: "identical"})
+				continue
+			}
+			if a.SkipExisting && !a.Force {
+				a.report(FileEvent{Path: action.Path, Status: FileSkipped, Reason: "exists"})
+				continue
+			}
+		}
 
-		if err := os.MkdirAll(filepath.Dir(action.Path), 0o755); err != nil {
-			return fmt.Errorf("create directory: %w", err)
+		if err := a.writeFile(action); err != nil {
+			a.report(FileEvent{Path: action.Path, Status: FileFailed, Err: err})
+			return written, err
 		}
+		written = append(written, action.Path)
+		current = append(current, action)
+		a.report(FileEvent{Path: action.Path, Status: FileCreated})
+	}
+
+	if plan.ProjectDir == "" {
+		return written, nil
+	}
+	return written, a.writeManifest(plan, current)
+}
 
-		if err := os.WriteFile(action.Path, []byte(action.Content), 0o644); err != nil {
-			return fmt.Errorf("write file: %w", err)
+func (a *Applier) writeFile(action domain.Action) error {
+	if err := a.mkdirAll(filepath.Dir(action.Path)); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if a.DurableWrites {
+		if err := writeDurable(action.Path, []byte(action.Content)); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(action.Path, []byte(action.Content), 0o666); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	mode := cmp.Or(action.Mode, a.FileMode)
+	if mode != 0 {
+		if err := os.Chmod(action.Path, mode); err != nil {
+			return fmt.Errorf("set file permissions: %w", err)
 		}
 	}
+	return nil
+}
+
+func (a *Applier) report(event FileEvent) {
+	if a.Progress != nil {
+		a.Progress(event)
+	}
+}
 
+// Conflicts returns the paths a default Applier would refuse to overwrite.
+func Conflicts(plan domain.Plan) ([]string, error) {
+	return NewApplier().Conflicts(plan)
+}
+
+// checkPaths makes sure every action stays inside the project dir and,
+// unless AllowSymlinkedDirs is set, that no existing directory between the
+// base dir and a file is a symlink that could redirect the write elsewhere.
+// The base dir itself may be a symlink; it is where the user asked to write.
+// Plans without a project dir have nothing to check against.
+func (a *Applier) checkPaths(plan domain.Plan) error {
+	if plan.ProjectDir == "" {
+		return nil
+	}
+	projectDir := filepath.Clean(plan.ProjectDir)
+	for _, action := range plan.Actions {
+		if !within(projectDir, action.Path) {
+			return apperrors.NewScaffoldError("check paths", fmt.Errorf("%s is outside the project dir %s", action.Path, projectDir))
+		}
+	}
+	if a.AllowSymlinkedDirs {
+		return nil
+	}
+
+	base := filepath.Clean(plan.BaseDir)
+	if plan.BaseDir == "" || !within(base, projectDir) {
+		base = filepath.Dir(projectDir)
+	}
+	checked := map[string]bool{}
+	for _, action := range plan.Actions {
+		for dir := filepath.Dir(action.Path); dir != base && within(base, dir); dir = filepath.Dir(dir) {
+			if checked[dir] {
+				break
+			}
+			checked[dir] = true
+			info, err := os.Lstat(dir)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return apperrors.NewScaffoldError("check paths", err)
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				target, _ := filepath.EvalSymlinks(dir)
+				return apperrors.NewScaffoldError("check paths", fmt.Errorf("%w: %s -> %s (set allowSymlinkedDirs to allow it)", apperrors.ErrSymlinkedDir, dir, target))
+			}
+		}
+	}
 	return nil
 }
 
-func slugify(value string) string {
+// within reports whether path is dir or lies under it, comparing cleaned paths.
+func within(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// MkdirAll creates dir and any missing parents with the same modes Apply
+// uses for project directories.
+func (a *Applier) MkdirAll(dir string) error {
+	return a.mkdirAll(dir)
+}
+
+// mkdirAll creates dir and any missing parents. New directories get DirMode
+// if set, and keep the setgid bit of the directory they are created in so
+// group ownership carries down into the project.
+func (a *Applier) mkdirAll(dir string) error {
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return nil
+	}
+	parent := filepath.Dir(dir)
+	if parent != dir {
+		if err := a.mkdirAll(parent); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(dir, 0o777); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	mode := a.DirMode
+	parentInfo, err := os.Stat(parent)
+	if err != nil {
+		return err
+	}
+	setgid := parentInfo.Mode()&os.ModeSetgid != 0
+	if mode == 0 {
+		if !setgid {
+			return nil
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSetgid != 0 {
+			return nil
+		}
+		mode = info.Mode().Perm()
+	}
+	if setgid {
+		mode |= os.ModeSetgid
+	}
+	return os.Chmod(dir, mode)
+}
+
+// Conflicts returns the paths Apply would refuse to overwrite, without
+// writing anything. Ignored paths and files that already hold the planned
+// content are not conflicts. Generator plans conflict when the project dir
+// exists.
+func (a *Applier) Conflicts(plan domain.Plan) ([]string, error) {
+	var conflicts []string
+	for _, action := range plan.Actions {
+		if a.ignored(plan, action.Path) {
+			continue
+		}
+		existing, err := os.ReadFile(action.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			// Directories and unreadable files still block the write.
+			if _, statErr := os.Stat(action.Path); statErr != nil {
+				return nil, fmt.Errorf("check file existence: %w", statErr)
+			}
+			conflicts = append(conflicts, action.Path)
+			continue
+		}
+		if string(existing) != action.Content {
+			conflicts = append(conflicts, action.Path)
+		}
+	}
+
+	if plan.Generator != "" {
+		if _, err := os.Stat(plan.ProjectDir); err == nil {
+			conflicts = append(conflicts, plan.ProjectDir)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("check file existence: %w", err)
+		}
+	}
+	return conflicts, nil
+}
+
+func (a *Applier) ignored(plan domain.Plan, target string) bool {
+	rel, err := filepath.Rel(plan.ProjectDir, target)
+	if err != nil {
+		return false
+	}
+	return Ignored(filepath.ToSlash(rel), a.Ignore)
+}
+
+// Ignored reports whether rel, a slash-separated path relative to the project
+// dir, matches any pattern. Patterns use path.Match semantics; one ending in
+// "/" matches a directory and everything beneath it.
+func Ignored(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+			segments := strings.Split(rel, "/")
+			for i := 1; i < len(segments); i++ {
+				if matched, _ := path.Match(dir, strings.Join(segments[:i], "/")); matched {
+					return true
+				}
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, rel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// DoubleNested reports whether projectDir was planned inside a dir named
+// after the project, e.g. "projects/my-app/Go/my-app": the dir above the
+// language dir, or the parent with LayoutFlat, matches the final segment.
+// That is the repetition Flatten collapses.
+func DoubleNested(projectDir string, layout string) bool {
+	projectDir = filepath.Clean(projectDir)
+	parent := filepath.Dir(projectDir)
+	if layout != LayoutFlat {
+		parent = filepath.Dir(parent)
+	}
+	if parent == "." || parent == filepath.Dir(parent) {
+		return false
+	}
+	return Slugify(filepath.Base(parent)) == filepath.Base(projectDir)
+}
+
+// Slugify turns a project name into the lower-case, kebab-case form used
+// for its directory and package name, e.g. "My Cool App" to "my-cool-app".
+// A name with nothing usable in it becomes "project".
+func Slugify(value string) string {
 	value = strings.TrimSpace(value)
 	value = strings.ToLower(value)
 	value = strings.ReplaceAll(value, " ", "-")
@@ -277,6 +992,37 @@ func slugify(value string) string {
 	return value
 }
 
+var slugWords = regexp.MustCompile(`[^-_]+|[-_]+`)
+
+// collapseDuplicateWords drops each word of a slug that repeats the word
+// before it, with the separator in front of it: "app-app" becomes "app"
+// and "my-my_app" becomes "my_app". Only neighbours collapse, so
+// "app-web-app" is unchanged.
+func collapseDuplicateWords(slug string) string {
+	var b strings.Builder
+	var separator, previous string
+	for _, token := range slugWords.FindAllString(slug, -1) {
+		if strings.Trim(token, "-_") == "" {
+			separator = token
+			continue
+		}
+		if token != previous {
+			b.WriteString(separator + token)
+		}
+		separator, previous = "", token
+	}
+	return b.String()
+}
+
+// slug is the request name's slug, with CollapseDupes applied.
+func (req Request) slug() string {
+	slug := Slugify(req.Name)
+	if req.CollapseDupes {
+		slug = collapseDuplicateWords(slug)
+	}
+	return slug
+}
+
 func cleanLanguageDir(language string) string {
 	value := strings.TrimSpace(language)
 	if value == "" {
@@ -294,7 +1040,8 @@ func cleanLanguageDir(language string) string {
 
 	value = strings.Map(replacer, value)
 	value = strings.TrimSpace(value)
-	if value == "" {
+	// "." and ".." would put projects in the base dir or above it.
+	if value == "" || value == "." || value == ".." {
 		return "language"
 	}
 	return value
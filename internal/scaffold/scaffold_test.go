@@ -1,19 +1,29 @@
 package scaffold
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"project-initiator/internal/domain"
+	apperrors "project-initiator/internal/errors"
+	"project-initiator/internal/library"
 	"project-initiator/internal/template"
 )
 
 // ---------------------------------------------------------------------------
-// slugify
+// Slugify
 // ---------------------------------------------------------------------------
 
 func TestSlugify(t *testing.T) {
@@ -37,9 +47,81 @@ func TestSlugify(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := slugify(tt.input)
+			got := Slugify(tt.input)
 			if got != tt.want {
-				t.Errorf("slugify(%q) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-_]*$`)
+
+func FuzzSlugify(f *testing.F) {
+	for _, seed := range []string{"MyProject", "my cool project", "hello@world!v2", "", "my-project", "--my-project--", "HELLO", "my_project", "  hello  ", "@@@", "  Hello World!  ", "_", "-_-", "İstanbul", "\u212a", "\xff"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		slug := Slugify(name)
+		if slug != "project" && !slugPattern.MatchString(slug) {
+			t.Errorf("Slugify(%q) = %q, want it to match %s", name, slug, slugPattern)
+		}
+		if again := Slugify(slug); again != slug {
+			t.Errorf("Slugify(%q) = %q, but Slugify(%q) = %q", name, slug, slug, again)
+		}
+	})
+}
+
+func TestCollapseDuplicateWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "my-my-app", want: "my-app"},
+		{input: "app-app", want: "app"},
+		{input: "app-app-app", want: "app"},
+		{input: "my-my_app", want: "my_app"},
+		{input: "api-web-api", want: "api-web-api"},
+		{input: "go-gopher", want: "go-gopher"},
+		{input: "a--a", want: "a"},
+		{input: "app", want: "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := collapseDuplicateWords(tt.input); got != tt.want {
+				t.Errorf("collapseDuplicateWords(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_CollapseDupes(t *testing.T) {
+	tests := []struct {
+		name     string
+		collapse bool
+		want     string
+	}{
+		{name: "off by default", want: "my-my-app"},
+		{name: "collapsed", collapse: true, want: "my-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:      "Go",
+				Framework:     "Vanilla",
+				Name:          "My my App",
+				Dir:           dir,
+				Layout:        LayoutFlat,
+				CollapseDupes: tt.collapse,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if want := filepath.Join(dir, tt.want); plan.ProjectDir != want {
+				t.Errorf("ProjectDir = %q, want %q", plan.ProjectDir, want)
 			}
 		})
 	}
@@ -62,6 +144,9 @@ func TestCleanLanguageDir(t *testing.T) {
 		{name: "spaces only", input: "   ", want: "language"},
 		{name: "mixed slashes", input: `foo/bar\baz`, want: "foo-bar-baz"},
 		{name: "surrounding spaces", input: "  Node.js  ", want: "Node.js"},
+		{name: "dot", input: ".", want: "language"},
+		{name: "dot dot", input: " .. ", want: "language"},
+		{name: "dots kept in names", input: "...", want: "..."},
 	}
 
 	for _, tt := range tests {
@@ -74,16 +159,79 @@ func TestCleanLanguageDir(t *testing.T) {
 	}
 }
 
+func FuzzCleanLanguageDir(f *testing.F) {
+	for _, seed := range []string{"Go", "", "a/b/c", `a\b\c`, "   ", `foo/bar\baz`, "  Node.js  ", ".", "..", " / ", "C#"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, language string) {
+		dir := cleanLanguageDir(language)
+		if dir == "" || dir == "." || dir == ".." {
+			t.Fatalf("cleanLanguageDir(%q) = %q, want a directory name", language, dir)
+		}
+		if strings.ContainsAny(dir, `/\`+string(os.PathSeparator)) {
+			t.Errorf("cleanLanguageDir(%q) = %q, want no path separators", language, dir)
+		}
+		if filepath.Base(filepath.Join("base", dir)) != dir {
+			t.Errorf("cleanLanguageDir(%q) = %q, which is not a single path element", language, dir)
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // template renderer
 // ---------------------------------------------------------------------------
 
+func TestTemplateData_RandomValues(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
+		Language: "Node.js",
+		Name:     "Random",
+		Templates: []domain.Template{
+			{RelativePath: ".env.example", Content: "SESSION_SECRET={{.RandomHex 16}}\nPORT={{.RandomPort}}\n"},
+			{RelativePath: "second.txt", Content: "{{.RandomHex 4}}"},
+		},
+	}})
+	render := func(name string, seed uint64) (env string, second string) {
+		t.Helper()
+		plan, err := planner.Plan(Request{Language: "Node.js", Framework: "Random", Name: name, Dir: t.TempDir(), Seed: seed})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		return plan.Actions[0].Content, plan.Actions[1].Content
+	}
+
+	env, second := render("app", 42)
+	if again, _ := render("app", 42); again != env {
+		t.Errorf("seed 42 rendered %q, then %q", env, again)
+	}
+	if other, _ := render("app", 43); other == env {
+		t.Errorf("seeds 42 and 43 both rendered %q", env)
+	}
+	if other, _ := render("worker", 42); other == env {
+		t.Errorf("projects app and worker both rendered %q", env)
+	}
+
+	var secret string
+	var port int
+	if _, err := fmt.Sscanf(env, "SESSION_SECRET=%s\nPORT=%d\n", &secret, &port); err != nil {
+		t.Fatalf("parse %q: %v", env, err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(secret) {
+		t.Errorf("RandomHex 16 = %q, want 32 hex digits", secret)
+	}
+	if port < 10000 || port > 49151 {
+		t.Errorf("RandomPort = %d, want 10000-49151", port)
+	}
+	if len(second) != 8 || strings.Contains(secret, second) {
+		t.Errorf("second template rendered %q, want 8 new hex digits", second)
+	}
+}
+
 func TestTemplateRenderer(t *testing.T) {
 	renderer := template.NewRenderer()
 
 	t.Run("simple template", func(t *testing.T) {
 		data := TemplateData{Name: "world"}
-		got, err := renderer.Render("hello {{.Name}}", data)
+		got, err := renderer.Render("greeting", "hello {{.Name}}", data)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -95,7 +243,7 @@ func TestTemplateRenderer(t *testing.T) {
 	t.Run("multiple vars", func(t *testing.T) {
 		src := "module {{.Module}} go {{.GoVersion}}"
 		data := TemplateData{Module: "mymod", GoVersion: "1.23"}
-		got, err := renderer.Render(src, data)
+		got, err := renderer.Render("go.mod", src, data)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -107,7 +255,7 @@ func TestTemplateRenderer(t *testing.T) {
 
 	t.Run("no vars", func(t *testing.T) {
 		data := TemplateData{}
-		got, err := renderer.Render("hello world", data)
+		got, err := renderer.Render("greeting", "hello world", data)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -118,11 +266,120 @@ func TestTemplateRenderer(t *testing.T) {
 
 	t.Run("invalid template syntax", func(t *testing.T) {
 		data := TemplateData{}
-		_, err := renderer.Render("hello {{.Name", data)
+		_, err := renderer.Render("cmd/main.go", "package main\n\nhello {{.Name", data)
+		if err == nil {
+			t.Fatal("expected error for invalid template syntax")
+		}
+		if !strings.Contains(err.Error(), "cmd/main.go:3:") {
+			t.Errorf("error %q does not name the template and line", err)
+		}
+	})
+
+	t.Run("execution error names line and column", func(t *testing.T) {
+		_, err := renderer.Render("README.md", "# title\n{{.Missing}}", TemplateData{})
 		if err == nil {
-			t.Error("expected error for invalid template syntax")
+			t.Fatal("expected error for unknown field")
+		}
+		if !strings.Contains(err.Error(), "README.md:2:2:") {
+			t.Errorf("error %q does not name the template, line and column", err)
+		}
+	})
+}
+
+func TestPlan_BrokenTemplatesReportPathAndLine(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
+		Language: "Go",
+		Name:     "Broken",
+		Templates: []domain.Template{
+			{RelativePath: "go.mod", Content: "module {{.Module}}\n"},
+			{RelativePath: "main.go", Content: "package main\n\nfunc main() {\n\t{{if .Name}}\n}\n"},
+			{RelativePath: "README.md", Content: "# {{.Name\n"},
+		},
+	}})
+
+	_, err := planner.Plan(Request{Language: "Go", Framework: "Broken", Name: "app", Dir: t.TempDir()})
+	if err == nil {
+		t.Fatal("Plan() error = nil, want the broken templates")
+	}
+	var scaffoldErr *apperrors.ScaffoldError
+	if !errors.As(err, &scaffoldErr) {
+		t.Fatalf("Plan() error = %T, want a ScaffoldError", err)
+	}
+	for _, want := range []string{"scaffold render main.go:", "main.go:6:", "scaffold render README.md:", "README.md:2:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Plan() error %q missing %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "go.mod") {
+		t.Errorf("Plan() error %q reports the valid go.mod", err)
+	}
+}
+
+func TestCheckTemplates_BuiltIns(t *testing.T) {
+	planner := DefaultPlanner()
+	for _, framework := range Frameworks {
+		if err := planner.CheckTemplates(framework); err != nil {
+			t.Errorf("%s/%s: %v", framework.Language, framework.Name, err)
+		}
+	}
+}
+
+func TestEmbeddedTemplates(t *testing.T) {
+	used := map[string]bool{}
+	for _, framework := range Frameworks {
+		if framework.Generator == "" && len(framework.Templates) == 0 {
+			t.Errorf("%s/%s has no templates", framework.Language, framework.Name)
 		}
+		for _, tmpl := range framework.Templates {
+			if tmpl.Content == "" {
+				t.Errorf("%s/%s: %s is empty", framework.Language, framework.Name, tmpl.RelativePath)
+			}
+			used[tmpl.RelativePath+"\x00"+tmpl.Content] = true
+		}
+	}
+
+	// Every file under templates/ belongs to some option; a stray one
+	// is a template nobody lists.
+	var stray []string
+	err := fs.WalkDir(templateFiles, "templates", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !strings.HasSuffix(name, templateSuffix) {
+			t.Errorf("%s lacks the %s suffix", name, templateSuffix)
+		}
+		data, err := templateFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		// templates/<language>/<framework>/<path>.tmpl
+		rel := strings.TrimSuffix(strings.SplitN(name, "/", 4)[3], templateSuffix)
+		if !used[rel+"\x00"+string(data)] {
+			stray = append(stray, name)
+		}
+		return nil
 	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stray) > 0 {
+		t.Errorf("templates no option lists: %q", stray)
+	}
+
+	plan, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Vanilla", Name: "svc", Dir: t.TempDir(), Module: "example.com/svc"})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/svc/internal/app\"\n)\n"
+	var mainGo string
+	for _, action := range plan.Actions {
+		if action.Path == filepath.Join(plan.ProjectDir, "main.go") {
+			mainGo = action.Content
+		}
+	}
+	if !strings.HasPrefix(mainGo, want) {
+		t.Errorf("main.go = %q, want it to start with %q", mainGo, want)
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -182,6 +439,134 @@ func TestFindFramework(t *testing.T) {
 	}
 }
 
+func TestFindFramework_Deprecated(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{
+		{Language: "Go", Name: "Old", Deprecated: "no longer maintained", ReplacedBy: "New"},
+		{Language: "Go", Name: "New"},
+		{Language: "Go", Name: "Legacy", Deprecated: "kept for old scripts"},
+		{Language: "Go", Name: "Orphan", ReplacedBy: "Gone"},
+	})
+
+	tests := []struct {
+		name      string
+		framework string
+		want      string
+		wantErr   bool
+	}{
+		{name: "replaced", framework: "old", want: "New"},
+		{name: "deprecated without replacement", framework: "Legacy", want: "Legacy"},
+		{name: "missing replacement", framework: "Orphan", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planner.findFramework("Go", tt.framework)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findFramework() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Name != tt.want {
+				t.Errorf("findFramework() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_DeprecatedWarnings(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{
+		{Language: "Go", Name: "Old", Deprecated: "no longer maintained", ReplacedBy: "Vanilla"},
+		{
+			Language:  "Go",
+			Name:      "Vanilla",
+			Templates: []domain.Template{{RelativePath: "main.go", Content: "package main\n"}},
+			Libraries: []domain.Library{{Name: "Gorm"}, {Name: "OldORM", ReplacedBy: "Gorm"}},
+		},
+	})
+
+	plan, err := planner.Plan(Request{Language: "Go", Framework: "Old", Name: "app", Dir: t.TempDir(), Libraries: []string{"oldorm"}})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := []string{
+		"Go/Old is deprecated: no longer maintained; using Go/Vanilla instead",
+		`library "OldORM" is deprecated; using "Gorm" instead`,
+	}
+	if len(plan.Warnings) < len(want) || !slices.Equal(plan.Warnings[:len(want)], want) {
+		t.Errorf("Warnings = %q, want them to start with %q", plan.Warnings, want)
+	}
+	if !slices.ContainsFunc(plan.Actions, func(a domain.Action) bool { return strings.HasSuffix(a.Path, "db.go") }) {
+		t.Errorf("replacement library not applied; actions: %v", plan.Actions)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Validate
+// ---------------------------------------------------------------------------
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       Request
+		wantField string
+	}{
+		{
+			name: "valid request",
+			req:  Request{Language: "Go", Framework: "Vanilla", Name: "myapp", Libraries: []string{"gin", "Gorm"}},
+		},
+		{
+			name:      "empty name",
+			req:       Request{Language: "Go", Framework: "Vanilla", Name: "   "},
+			wantField: "name",
+		},
+		{
+			name:      "unknown option",
+			req:       Request{Language: "Go", Framework: "Django", Name: "myapp"},
+			wantField: "framework",
+		},
+		{
+			name:      "unknown library",
+			req:       Request{Language: "Go", Framework: "Vanilla", Name: "myapp", Libraries: []string{"gin", "echo"}},
+			wantField: "libraries",
+		},
+		{
+			name:      "cors without gin",
+			req:       Request{Language: "Go", Framework: "Vanilla", Name: "myapp", Libraries: []string{"cors"}},
+			wantField: "libraries",
+		},
+		{
+			name:      "library on option without libraries",
+			req:       Request{Language: "Python", Framework: "FastAPI", Name: "myapp", Libraries: []string{"gin"}},
+			wantField: "libraries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.req)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestPlan_RejectsUnknownLibrary(t *testing.T) {
+	req := Request{Language: "Go", Framework: "Vanilla", Name: "myapp", Dir: t.TempDir(), Libraries: []string{"echo"}}
+	if _, err := DefaultPlanner().Plan(req); err == nil {
+		t.Error("expected Plan to run validation and reject unknown library")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Plan
 // ---------------------------------------------------------------------------
@@ -217,6 +602,82 @@ func TestPlan_GoVanilla(t *testing.T) {
 	}
 }
 
+func TestPlan_GoVanillaAppTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework string
+		libraries []string
+		want      bool
+	}{
+		{name: "vanilla", framework: "Vanilla", want: true},
+		{name: "vanilla with libraries", framework: "Vanilla", libraries: []string{"gin", "gorm"}, want: true},
+		{name: "cobra", framework: "Cobra", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: tt.framework,
+				Name:      "myapp",
+				Dir:       t.TempDir(),
+				Libraries: tt.libraries,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			testPath := filepath.Join(plan.ProjectDir, "internal", "app", "app_test.go")
+			found := false
+			for _, action := range plan.Actions {
+				if action.Path == testPath {
+					found = true
+					if !strings.Contains(action.Content, "func TestRun(t *testing.T)") {
+						t.Errorf("app_test.go content = %q, want a TestRun test", action.Content)
+					}
+				}
+			}
+			if found != tt.want {
+				t.Errorf("app_test.go planned = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_Module(t *testing.T) {
+	tests := []struct {
+		name   string
+		module string
+		prefix string
+		want   string
+	}{
+		{name: "slug by default", want: "module my-app\n"},
+		{name: "prefix joins slug", prefix: "github.com/acme/", want: "module github.com/acme/my-app\n"},
+		{name: "module overrides prefix", module: "example.com/x", prefix: "github.com/acme", want: "module example.com/x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:     "Go",
+				Framework:    "Vanilla",
+				Name:         "My App",
+				Dir:          t.TempDir(),
+				Module:       tt.module,
+				ModulePrefix: tt.prefix,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			for _, action := range plan.Actions {
+				if filepath.Base(action.Path) == "go.mod" && !strings.HasPrefix(action.Content, tt.want) {
+					t.Errorf("go.mod = %q, want it to start with %q", action.Content, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func TestPlan_JSVanilla(t *testing.T) {
 	tempDir := t.TempDir()
 	req := Request{
@@ -295,49 +756,181 @@ func TestPlan_GoGinLibrary(t *testing.T) {
 	}
 }
 
-func TestPlan_GoGormLibrary(t *testing.T) {
-	tempDir := t.TempDir()
-	req := Request{
-		Language:  "Go",
-		Framework: "Vanilla",
-		Name:      "myapp",
-		Dir:       tempDir,
-		Libraries: []string{"gorm"},
+func TestPlan_GoGinCors(t *testing.T) {
+	tests := []struct {
+		name           string
+		libraries      []string
+		wantMiddleware bool
+	}{
+		{name: "gin only", libraries: []string{"gin"}},
+		{name: "gin and cors", libraries: []string{"gin", "CORS"}, wantMiddleware: true},
 	}
 
-	planner := DefaultPlanner()
-	plan, err := planner.Plan(req)
-	if err != nil {
-		t.Fatalf("Plan() error = %v", err)
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: "Vanilla",
+				Name:      "myapi",
+				Dir:       t.TempDir(),
+				Libraries: tt.libraries,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
 
-	// Should have gorm-specific files
-	hasGormDB := false
-	for _, action := range plan.Actions {
-		if strings.Contains(action.Path, "internal/db/db.go") {
-			hasGormDB = true
-			break
-		}
-	}
-	if !hasGormDB {
-		t.Error("expected gorm db file")
-	}
-}
+			files := map[string]string{}
+			for _, action := range plan.Actions {
+				rel, _ := filepath.Rel(plan.ProjectDir, action.Path)
+				files[filepath.ToSlash(rel)] = action.Content
+			}
 
-func TestPlan_GoAllLibraries(t *testing.T) {
-	tempDir := t.TempDir()
-	req := Request{
-		Language:  "Go",
-		Framework: "Vanilla",
-		Name:      "myapp",
-		Dir:       tempDir,
-		Libraries: []string{"gin", "gorm", "sqlc"},
+			if got := strings.Contains(files["internal/http/server.go"], "router.Use(corsMiddleware())"); got != tt.wantMiddleware {
+				t.Errorf("server.go uses the CORS middleware = %v, want %v:\n%s", got, tt.wantMiddleware, files["internal/http/server.go"])
+			}
+			if _, got := files["internal/http/cors.go"]; got != tt.wantMiddleware {
+				t.Errorf("cors.go planned = %v, want %v", got, tt.wantMiddleware)
+			}
+			if got := strings.Contains(files["go.mod"], "\tgithub.com/gin-contrib/cors v"); got != tt.wantMiddleware {
+				t.Errorf("go.mod requires gin-contrib/cors = %v, want %v:\n%s", got, tt.wantMiddleware, files["go.mod"])
+			}
+		})
 	}
+}
 
-	planner := DefaultPlanner()
-	plan, err := planner.Plan(req)
-	if err != nil {
-		t.Fatalf("Plan() error = %v", err)
+func TestPlan_EnvExample(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		framework string
+		libraries []string
+		port      int
+		want      []string
+	}{
+		{name: "no variables", language: "Go", framework: "Vanilla", libraries: []string{"testify"}},
+		{name: "gorm", language: "Go", framework: "Vanilla", libraries: []string{"gorm"}, want: []string{"DATABASE_URL=envapp.db"}},
+		{name: "gin and gorm", language: "Go", framework: "Vanilla", libraries: []string{"gorm", "gin"}, want: []string{"PORT=3000", "DATABASE_URL=envapp.db"}},
+		{
+			name:      "gin, cors, gorm and graphql",
+			language:  "Go",
+			framework: "Cobra",
+			libraries: []string{"graphql", "gorm", "gin", "cors"},
+			port:      8080,
+			want:      []string{"PORT=8080", "CORS_ALLOWED_ORIGINS=http://localhost:5173", "DATABASE_URL=envapp.db"},
+		},
+		{name: "express", language: "Node.js", framework: "Express", port: 4000, want: []string{"PORT=4000"}},
+		{name: "framework without variables", language: "Python", framework: "FastAPI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  tt.language,
+				Framework: tt.framework,
+				Name:      "envapp",
+				Dir:       t.TempDir(),
+				Libraries: tt.libraries,
+				Port:      tt.port,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			files := map[string]string{}
+			for _, action := range plan.Actions {
+				files[filepath.Base(action.Path)] = action.Content
+			}
+			env, ok := files[".env.example"]
+			if ok != (len(tt.want) > 0) {
+				t.Fatalf(".env.example planned = %v, want %v", ok, len(tt.want) > 0)
+			}
+
+			var got []string
+			for _, line := range strings.Split(env, "\n") {
+				if line != "" && !strings.HasPrefix(line, "#") {
+					got = append(got, line)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf(".env.example variables = %q, want %q:\n%s", got, tt.want, env)
+			}
+			if ok && files[".gitignore"] != ".env\n" {
+				t.Errorf(".gitignore = %q, want .env ignored", files[".gitignore"])
+			}
+		})
+	}
+}
+
+func TestAddEnvExample_ExistingGitignore(t *testing.T) {
+	dir := t.TempDir()
+	vars := []domain.EnvVar{{Name: "DATABASE_URL", Example: "app.db"}}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "appends", content: "node_modules/", want: "node_modules/\n.env\n"},
+		{name: "already ignored", content: "node_modules/\n.env\n", want: "node_modules/\n.env\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitignore := domain.Action{Path: filepath.Join(dir, ".gitignore"), Content: tt.content}
+			actions := addEnvExample([]domain.Action{gitignore}, dir, vars)
+
+			if len(actions) != 2 {
+				t.Fatalf("actions = %d, want the .gitignore and .env.example", len(actions))
+			}
+			if actions[0].Content != tt.want {
+				t.Errorf(".gitignore = %q, want %q", actions[0].Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_GoGormLibrary(t *testing.T) {
+	tempDir := t.TempDir()
+	req := Request{
+		Language:  "Go",
+		Framework: "Vanilla",
+		Name:      "myapp",
+		Dir:       tempDir,
+		Libraries: []string{"gorm"},
+	}
+
+	planner := DefaultPlanner()
+	plan, err := planner.Plan(req)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// Should have gorm-specific files
+	hasGormDB := false
+	for _, action := range plan.Actions {
+		if strings.Contains(action.Path, "internal/db/db.go") {
+			hasGormDB = true
+			break
+		}
+	}
+	if !hasGormDB {
+		t.Error("expected gorm db file")
+	}
+}
+
+func TestPlan_GoAllLibraries(t *testing.T) {
+	tempDir := t.TempDir()
+	req := Request{
+		Language:  "Go",
+		Framework: "Vanilla",
+		Name:      "myapp",
+		Dir:       tempDir,
+		Libraries: []string{"gin", "gorm", "sqlc"},
+	}
+
+	planner := DefaultPlanner()
+	plan, err := planner.Plan(req)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
 	}
 
 	// Should have all library files
@@ -368,6 +961,351 @@ func TestPlan_GoAllLibraries(t *testing.T) {
 	}
 }
 
+func TestPlan_WarnsOnOverlappingLibraries(t *testing.T) {
+	plan, err := DefaultPlanner().Plan(Request{
+		Language:  "Go",
+		Framework: "Vanilla",
+		Name:      "myapp",
+		Dir:       t.TempDir(),
+		Libraries: []string{"graphql", "sqlc"},
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Warnings) != 1 {
+		t.Errorf("Warnings = %q, want one overlap warning", plan.Warnings)
+	}
+}
+
+func TestPlan_AirBuildsPlannedMain(t *testing.T) {
+	for _, framework := range []string{"Vanilla", "Cobra"} {
+		t.Run(framework, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: framework,
+				Name:      "myapp",
+				Dir:       t.TempDir(),
+				Libraries: []string{"air"},
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			var mainDir, airConfig string
+			for _, action := range plan.Actions {
+				switch filepath.Base(action.Path) {
+				case "main.go":
+					rel, _ := filepath.Rel(plan.ProjectDir, filepath.Dir(action.Path))
+					mainDir = filepath.ToSlash(rel)
+				case ".air.toml":
+					airConfig = action.Content
+				}
+			}
+			want := "go build -o ./tmp/myapp ./" + mainDir + `"`
+			if mainDir == "." {
+				want = `go build -o ./tmp/myapp ."`
+			}
+			if !strings.Contains(airConfig, want) {
+				t.Errorf(".air.toml should build the planned main package %q:\n%s", mainDir, airConfig)
+			}
+		})
+	}
+}
+
+func TestPlan_Version(t *testing.T) {
+	for _, framework := range []string{"Vanilla", "Cobra"} {
+		t.Run(framework, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: framework,
+				Name:      "myapp",
+				Module:    "github.com/acme/myapp",
+				Dir:       t.TempDir(),
+				Libraries: []string{"version"},
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			files := map[string]string{}
+			for _, action := range plan.Actions {
+				rel, _ := filepath.Rel(plan.ProjectDir, action.Path)
+				files[filepath.ToSlash(rel)] = action.Content
+			}
+
+			version, ok := files["internal/version/version.go"]
+			if !ok {
+				t.Fatal("internal/version/version.go not planned")
+			}
+			for _, want := range []string{"package version\n", "\tVersion = \"dev\"\n", "\tCommit = \"none\"\n", "\tBuildDate = \"unknown\"\n", "-X github.com/acme/myapp/internal/version.Version="} {
+				if !strings.Contains(version, want) {
+					t.Errorf("version.go missing %q:\n%s", want, version)
+				}
+			}
+			if !strings.Contains(files["README.md"], "-X github.com/acme/myapp/internal/version.Commit=") {
+				t.Errorf("README.md has no ldflags hint:\n%s", files["README.md"])
+			}
+		})
+	}
+}
+
+func TestPlan_Testify(t *testing.T) {
+	for _, framework := range []string{"Vanilla", "Cobra"} {
+		t.Run(framework, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: framework,
+				Name:      "myapp",
+				Dir:       t.TempDir(),
+				Libraries: []string{"testify"},
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			files := map[string][]string{}
+			for _, action := range plan.Actions {
+				rel, _ := filepath.Rel(plan.ProjectDir, action.Path)
+				files[filepath.ToSlash(rel)] = append(files[filepath.ToSlash(rel)], action.Content)
+			}
+
+			if goMod := files["go.mod"]; len(goMod) != 1 || !strings.Contains(goMod[0], "\tgithub.com/stretchr/testify v1.9.0\n") {
+				t.Errorf("go.mod = %q, want the testify require", goMod)
+			}
+			appTest := files["internal/app/app_test.go"]
+			if len(appTest) != 1 {
+				t.Fatalf("planned app_test.go %d times, want once", len(appTest))
+			}
+			for _, want := range []string{`"github.com/stretchr/testify/assert"`, `"github.com/stretchr/testify/require"`, `"myapp/internal/testhelpers"`} {
+				if !strings.Contains(appTest[0], want) {
+					t.Errorf("app_test.go missing import %s:\n%s", want, appTest[0])
+				}
+			}
+			if _, ok := files["internal/testhelpers/testhelpers.go"]; !ok {
+				t.Error("testhelpers package not planned")
+			}
+		})
+	}
+}
+
+func TestPlan_TestifySamplesFollowSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		libraries []string
+		wantGin   bool
+		wantCORS  bool
+	}{
+		{name: "testify alone", libraries: []string{"testify"}},
+		{name: "with gin", libraries: []string{"gin", "testify"}, wantGin: true},
+		{name: "with gin and cors", libraries: []string{"gin", "cors", "testify"}, wantGin: true, wantCORS: true},
+		{name: "gin without testify", libraries: []string{"gin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: "Vanilla",
+				Name:      "myapp",
+				Dir:       t.TempDir(),
+				Libraries: tt.libraries,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			var serverTest string
+			for _, action := range plan.Actions {
+				if strings.HasSuffix(filepath.ToSlash(action.Path), "internal/http/server_test.go") {
+					serverTest = action.Content
+				}
+			}
+			if got := serverTest != ""; got != tt.wantGin {
+				t.Fatalf("server_test.go planned = %v, want %v", got, tt.wantGin)
+			}
+			if !tt.wantGin {
+				return
+			}
+			for _, want := range []string{"func TestHealth(", `"github.com/stretchr/testify/assert"`, `assert.Equal(t, "ok", body["status"])`} {
+				if !strings.Contains(serverTest, want) {
+					t.Errorf("server_test.go missing %s:\n%s", want, serverTest)
+				}
+			}
+			if got := strings.Contains(serverTest, "func TestCORSAllowsConfiguredOrigin("); got != tt.wantCORS {
+				t.Errorf("server_test.go has CORS test = %v, want %v", got, tt.wantCORS)
+			}
+		})
+	}
+}
+
+func TestPlan_OpenAPI(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		framework string
+		libraries []string
+		want      []string
+		wantErr   bool
+		wantWarn  bool
+	}{
+		{
+			name: "gin", language: "Go", framework: "Vanilla", libraries: []string{"gin"},
+			want: []string{`title: "my-api"`, `description: "Gin HTTP server generated by project-initiator."`, "  /health:", "operationId: getHealth", "application/json", "http://localhost:3000"},
+		},
+		{
+			name: "gin on cobra", language: "Go", framework: "Cobra", libraries: []string{"gin"},
+			want: []string{"  /health:"},
+		},
+		{
+			name: "fastapi", language: "Python", framework: "FastAPI",
+			want: []string{`description: "Python API server generated by project-initiator."`, "  /health:", "http://localhost:8000"},
+		},
+		{
+			name: "express", language: "Node.js", framework: "Express",
+			want: []string{"  /health:", "text/plain"},
+		},
+		{name: "go without gin", language: "Go", framework: "Cobra", wantErr: true},
+		{name: "hono", language: "Node.js", framework: "Hono", wantErr: true},
+		{name: "go generator with gin", language: "Go", framework: "Buffalo", libraries: []string{"gin"}, wantErr: true},
+		{name: "openapi library writes its own", language: "Go", framework: "Vanilla", libraries: []string{"gin", "openapi"}, wantWarn: true},
+	}
+	planner := NewPlanner(append(slices.Clone(Frameworks), domain.Framework{
+		Language:  "Go",
+		Name:      "Buffalo",
+		Generator: "buffalo-new",
+		Libraries: []domain.Library{{Name: "Gin"}},
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planner.Plan(Request{
+				Language:  tt.language,
+				Framework: tt.framework,
+				Name:      "my-api",
+				Dir:       t.TempDir(),
+				Libraries: tt.libraries,
+				OpenAPI:   true,
+			})
+			if tt.wantErr {
+				var validationErr *apperrors.ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != "openapi" {
+					t.Fatalf("Plan() error = %v, want an openapi ValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			var spec string
+			for _, action := range plan.Actions {
+				if action.Path == filepath.Join(plan.ProjectDir, "openapi.yaml") {
+					spec = action.Content
+				}
+			}
+			if tt.wantWarn {
+				if spec != "" {
+					t.Error("openapi.yaml planned alongside the OpenAPI library's spec")
+				}
+				if !slices.ContainsFunc(plan.Warnings, func(w string) bool { return strings.Contains(w, "--openapi skipped") }) {
+					t.Errorf("Warnings = %q, want --openapi skipped", plan.Warnings)
+				}
+				return
+			}
+			if spec == "" {
+				t.Fatal("openapi.yaml not planned")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(spec, want) {
+					t.Errorf("openapi.yaml missing %q:\n%s", want, spec)
+				}
+			}
+		})
+	}
+}
+
+func TestPlan_NoOpenAPIByDefault(t *testing.T) {
+	plan, err := DefaultPlanner().Plan(Request{Language: "Python", Framework: "FastAPI", Name: "api", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	for _, action := range plan.Actions {
+		if filepath.Base(action.Path) == "openapi.yaml" {
+			t.Errorf("planned %s without --openapi", action.Path)
+		}
+	}
+}
+
+func TestPlan_PackageNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		framework string
+		project   string
+		scope     string
+		file      string
+		want      []string
+		wantField string
+	}{
+		{
+			name: "npm unscoped", language: "Node.js", framework: "Express", project: "Billing API",
+			file: "package.json", want: []string{`"name": "billing-api"`},
+		},
+		{
+			name: "npm scoped", language: "Bun", framework: "Vanilla", project: "Billing API", scope: "@acme",
+			file: "package.json", want: []string{`"name": "@acme/billing-api"`},
+		},
+		{
+			name: "npm scope in readme", language: "JavaScript", framework: "Vanilla", project: "web", scope: "@acme",
+			file: "README.md", want: []string{"npm package: `@acme/web`"},
+		},
+		{
+			name: "pypi normalized", language: "Python", framework: "FastAPI", project: "Billing_API.v2",
+			file: "pyproject.toml", want: []string{`name = "billing-api-v2"`, "fastapi==0.115.5"},
+		},
+		{
+			name: "pypi in readme", language: "Python", framework: "Vanilla", project: "my__tool",
+			file: "README.md", want: []string{"PyPI package: `my-tool`"},
+		},
+		{name: "bad scope", language: "Node.js", framework: "Hono", project: "web", scope: "acme", wantField: "npmScope"},
+		{name: "npm name too long", language: "Node.js", framework: "Hono", project: strings.Repeat("a", 215), wantField: "name"},
+		{name: "scope ignored outside npm", language: "Go", framework: "Vanilla", project: "svc", scope: "acme", file: "go.mod", want: []string{"module svc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  tt.language,
+				Framework: tt.framework,
+				Name:      tt.project,
+				Dir:       t.TempDir(),
+				NPMScope:  tt.scope,
+			})
+			if tt.wantField != "" {
+				var validationErr *apperrors.ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+					t.Fatalf("Plan() error = %v, want a %s ValidationError", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			var content string
+			for _, action := range plan.Actions {
+				if filepath.Base(action.Path) == tt.file {
+					content = action.Content
+				}
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s missing %q:\n%s", tt.file, want, content)
+				}
+			}
+		})
+	}
+}
+
 func TestPlan_GoCobraFramework(t *testing.T) {
 	tempDir := t.TempDir()
 	req := Request{
@@ -451,10 +1389,270 @@ func TestPlan_LaravelUsesGenerator(t *testing.T) {
 	}
 }
 
-func TestPlan_DirDefaultsToDot(t *testing.T) {
-	req := Request{
+func TestPlan_GeneratorSkipsGoLibraries(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
 		Language:  "Go",
-		Framework: "Vanilla",
+		Name:      "Buffalo",
+		Generator: "buffalo-new",
+		Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}},
+	}})
+
+	plan, err := planner.Plan(Request{Language: "Go", Framework: "Buffalo", Name: "shop", Dir: t.TempDir(), Libraries: []string{"gin", "gorm"}})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Actions) != 0 {
+		t.Errorf("generator plan has %d template actions, want none", len(plan.Actions))
+	}
+	if plan.Port != 0 {
+		t.Errorf("Plan.Port = %d, want 0 without Gin's server", plan.Port)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "libraries skipped: Go/Buffalo is created by the buffalo-new generator") {
+		t.Errorf("Warnings = %q, want the skipped libraries", plan.Warnings)
+	}
+}
+
+func TestPlan_LayoutsMatchAcrossTemplatesAndGenerators(t *testing.T) {
+	base := t.TempDir()
+	options := []struct {
+		name      string
+		language  string
+		framework string
+		langDir   string
+	}{
+		{name: "template", language: "PHP", framework: "Vanilla", langDir: "PHP"},
+		{name: "generator", language: "PHP", framework: "Laravel", langDir: "PHP"},
+	}
+	layouts := []struct {
+		layout    string
+		wantInDir func(langDir string) string
+	}{
+		{layout: "", wantInDir: func(langDir string) string { return filepath.Join(base, langDir, "my-app") }},
+		{layout: LayoutByLanguage, wantInDir: func(langDir string) string { return filepath.Join(base, langDir, "my-app") }},
+		{layout: LayoutFlat, wantInDir: func(string) string { return filepath.Join(base, "my-app") }},
+	}
+
+	for _, option := range options {
+		for _, layout := range layouts {
+			t.Run(option.name+"/"+layoutName(layout.layout), func(t *testing.T) {
+				for _, flatten := range []bool{false, true} {
+					plan, err := DefaultPlanner().Plan(Request{
+						Language:  option.language,
+						Framework: option.framework,
+						Name:      "My App",
+						Dir:       base,
+						Layout:    layout.layout,
+						Flatten:   flatten,
+					})
+					if err != nil {
+						t.Fatalf("Plan() error = %v", err)
+					}
+					if want := layout.wantInDir(option.langDir); plan.ProjectDir != want {
+						t.Errorf("flatten=%v: ProjectDir = %q, want %q", flatten, plan.ProjectDir, want)
+					}
+					if plan.BaseDir != base {
+						t.Errorf("flatten=%v: BaseDir = %q, want %q", flatten, plan.BaseDir, base)
+					}
+					for _, action := range plan.Actions {
+						if !strings.HasPrefix(action.Path, plan.ProjectDir+string(filepath.Separator)) {
+							t.Errorf("action %s is outside ProjectDir %s", action.Path, plan.ProjectDir)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func layoutName(layout string) string {
+	if layout == "" {
+		return "default"
+	}
+	return layout
+}
+
+func TestPlan_RejectsUnknownLayout(t *testing.T) {
+	_, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Vanilla", Name: "app", Dir: t.TempDir(), Layout: "nested"})
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "layout" {
+		t.Errorf("Plan() error = %v, want a layout ValidationError", err)
+	}
+}
+
+func TestPlan_Port(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework string
+		libraries []string
+		port      int
+		files     map[string]string // path suffix -> content it must contain
+		wantPort  int
+	}{
+		{
+			name:      "gin server and README use a custom port",
+			framework: "Vanilla",
+			libraries: []string{"Gin"},
+			port:      8080,
+			files: map[string]string{
+				"main.go":   `port = "8080"`,
+				"README.md": "curl http://localhost:8080/health",
+			},
+			wantPort: 8080,
+		},
+		{
+			name:      "gin defaults to 3000",
+			framework: "Vanilla",
+			libraries: []string{"Gin"},
+			files:     map[string]string{"main.go": `port = "3000"`},
+			wantPort:  3000,
+		},
+		{
+			name:      "templates use the port",
+			framework: "Hono",
+			port:      4000,
+			files:     map[string]string{"src/index.js": "port: 4000"},
+			wantPort:  4000,
+		},
+		{
+			name:      "no server, no port",
+			framework: "Vanilla",
+			port:      8080,
+			wantPort:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language := "Go"
+			if tt.framework == "Hono" {
+				language = "Node.js"
+			}
+			plan, err := DefaultPlanner().Plan(Request{Language: language, Framework: tt.framework, Name: "served", Dir: t.TempDir(), Libraries: tt.libraries, Port: tt.port})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if plan.Port != tt.wantPort {
+				t.Errorf("plan.Port = %d, want %d", plan.Port, tt.wantPort)
+			}
+			for suffix, want := range tt.files {
+				found := false
+				for _, action := range plan.Actions {
+					if strings.HasSuffix(filepath.ToSlash(action.Path), "/"+suffix) {
+						found = true
+						if !strings.Contains(action.Content, want) {
+							t.Errorf("%s missing %q:\n%s", suffix, want, action.Content)
+						}
+					}
+				}
+				if !found {
+					t.Errorf("no %s planned", suffix)
+				}
+			}
+		})
+	}
+}
+
+func TestPlan_PortPerFramework(t *testing.T) {
+	tests := []struct {
+		language    string
+		framework   string
+		libraries   []string
+		defaultPort int
+		files       []string // files that mention the port
+	}{
+		{language: "Node.js", framework: "Express", defaultPort: 3000, files: []string{"src/index.js", "README.md", ".env.example"}},
+		{language: "Node.js", framework: "Hono", defaultPort: 3000, files: []string{"src/index.js", "README.md"}},
+		{language: "Node.js", framework: "NestJS", defaultPort: 3000, files: []string{"src/main.ts", "README.md"}},
+		{language: "Bun", framework: "Bun", defaultPort: 3000, files: []string{"src/index.ts", "README.md"}},
+		{language: "Python", framework: "FastAPI", defaultPort: 8000, files: []string{"README.md"}},
+		{language: "Go", framework: "Cobra", libraries: []string{"gin"}, defaultPort: 3000, files: []string{"cmd/served/main.go", "README.md", ".env.example"}},
+	}
+
+	for _, tt := range tests {
+		for _, port := range []int{0, 4321} {
+			t.Run(fmt.Sprintf("%s/%d", tt.framework, port), func(t *testing.T) {
+				plan, err := DefaultPlanner().Plan(Request{Language: tt.language, Framework: tt.framework, Name: "served", Dir: t.TempDir(), Libraries: tt.libraries, Port: port})
+				if err != nil {
+					t.Fatalf("Plan() error = %v", err)
+				}
+
+				want := cmp.Or(port, tt.defaultPort)
+				if plan.Port != want {
+					t.Errorf("plan.Port = %d, want %d", plan.Port, want)
+				}
+				files := map[string]string{}
+				for _, action := range plan.Actions {
+					rel, _ := filepath.Rel(plan.ProjectDir, action.Path)
+					files[filepath.ToSlash(rel)] = action.Content
+				}
+				for _, path := range tt.files {
+					if !strings.Contains(files[path], strconv.Itoa(want)) {
+						t.Errorf("%s does not mention port %d:\n%s", path, want, files[path])
+					}
+				}
+				if port != 0 {
+					for path, content := range files {
+						if strings.Contains(content, strconv.Itoa(tt.defaultPort)) {
+							t.Errorf("%s still mentions the default port %d:\n%s", path, tt.defaultPort, content)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPlan_RejectsBadPort(t *testing.T) {
+	_, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Vanilla", Name: "app", Dir: t.TempDir(), Port: 70000})
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "port" {
+		t.Errorf("Plan() error = %v, want a port ValidationError", err)
+	}
+}
+
+func TestPlan_DirPlaceholders(t *testing.T) {
+	base := t.TempDir()
+	tests := []struct {
+		name    string
+		dir     string
+		project string
+		layout  string
+		want    string
+		wantErr string
+	}{
+		{name: "slug", dir: filepath.Join(base, "{slug}"), project: "Billing API", layout: LayoutFlat, want: filepath.Join(base, "billing-api", "billing-api")},
+		{name: "slug by language", dir: filepath.Join(base, "work", "{slug}"), project: "svc", want: filepath.Join(base, "work", "svc", "Go", "svc")},
+		{name: "name", dir: filepath.Join(base, "{name}"), project: "Billing API", layout: LayoutFlat, want: filepath.Join(base, "Billing API", "billing-api")},
+		{name: "inside a segment", dir: filepath.Join(base, "team-{slug}-src"), project: "svc", layout: LayoutFlat, want: filepath.Join(base, "team-svc-src", "svc")},
+		{name: "no placeholders", dir: base, project: "svc", layout: LayoutFlat, want: filepath.Join(base, "svc")},
+		{name: "name with a slash", dir: filepath.Join(base, "{name}"), project: "../escape", wantErr: "{name} needs a name usable as a directory"},
+		{name: "slug is always usable", dir: filepath.Join(base, "{slug}"), project: "a/b", layout: LayoutFlat, want: filepath.Join(base, "a-b", "a-b")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Language: "Go", Framework: "Vanilla", Name: tt.project, Dir: tt.dir, Layout: tt.layout}
+			plan, err := DefaultPlanner().Plan(req)
+			if tt.wantErr != "" {
+				var verr *apperrors.ValidationError
+				if !errors.As(err, &verr) || verr.Field != "dir" || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want a dir validation error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if plan.ProjectDir != tt.want {
+				t.Errorf("ProjectDir = %q, want %q", plan.ProjectDir, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_DirDefaultsToDot(t *testing.T) {
+	req := Request{
+		Language:  "Go",
+		Framework: "Vanilla",
 		Name:      "myapp",
 		Dir:       "",
 	}
@@ -527,7 +1725,7 @@ func TestApply_CreatesFiles(t *testing.T) {
 	}
 
 	applier := NewApplier()
-	if err := applier.Apply(plan, false); err != nil {
+	if _, err := applier.Apply(plan, false); err != nil {
 		t.Fatalf("Apply() error = %v", err)
 	}
 
@@ -549,6 +1747,301 @@ func TestApply_CreatesFiles(t *testing.T) {
 	}
 }
 
+func TestApply_Mode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no executable bit")
+	}
+	tempDir := t.TempDir()
+	// What a plain write gets under the test's umask.
+	reference := filepath.Join(tempDir, "reference")
+	if err := os.WriteFile(reference, nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultMode := info.Mode().Perm()
+
+	tests := []struct {
+		name    string
+		applier *Applier
+		mode    os.FileMode
+		want    os.FileMode
+	}{
+		{name: "zero keeps the default", applier: NewApplier(), want: defaultMode},
+		{name: "explicit mode", applier: NewApplier(), mode: 0o755, want: 0o755},
+		{name: "zero uses configured permissions", applier: &Applier{FileMode: 0o640}, want: 0o640},
+		{name: "explicit mode wins over configured permissions", applier: &Applier{FileMode: 0o640}, mode: 0o750, want: 0o750},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tempDir, fmt.Sprintf("file%d", i))
+			plan := domain.Plan{Actions: []domain.Action{{Path: path, Content: "#!/bin/sh\n", Mode: tt.mode}}}
+			if _, err := tt.applier.Apply(plan, false); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != tt.want {
+				t.Errorf("mode = %o, want %o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeActions(t *testing.T) {
+	dir := filepath.Join("projects", "app")
+	at := func(rel string, content string) domain.Action {
+		return domain.Action{Path: filepath.Join(dir, rel), Content: content}
+	}
+	tests := []struct {
+		name    string
+		actions []domain.Action
+		want    []domain.Action
+		wantErr string
+	}{
+		{
+			name:    "gitignore lines deduped",
+			actions: []domain.Action{at(".gitignore", "# deps\nnode_modules/\n.env\n"), at("main.go", "package main\n"), at(".gitignore", "# docker\n.env\n\nbin/\n")},
+			want:    []domain.Action{at(".gitignore", "# deps\nnode_modules/\n.env\n\n# docker\n\nbin/\n"), at("main.go", "package main\n")},
+		},
+		{
+			name:    "readme sections concatenated",
+			actions: []domain.Action{at("README.md", "# app\n"), at("README.md", "## License\n\nMIT\n\n"), at("README.md", "# app\n")},
+			want:    []domain.Action{at("README.md", "# app\n\n## License\n\nMIT\n")},
+		},
+		{
+			name:    "nested gitignore merged",
+			actions: []domain.Action{at("web/.gitignore", "dist/\n"), at("web/.gitignore", "dist/\n.cache/\n")},
+			want:    []domain.Action{at("web/.gitignore", "dist/\n\n.cache/\n")},
+		},
+		{
+			name:    "identical duplicates collapse",
+			actions: []domain.Action{at("go.mod", "module app\n"), at("go.mod", "module app\n")},
+			want:    []domain.Action{at("go.mod", "module app\n")},
+		},
+		{
+			name:    "non-adjacent duplicates count once",
+			actions: []domain.Action{at("go.mod", "module app\n"), at("go.mod", "module web\n"), at("go.mod", "module app\n")},
+			wantErr: "scaffold plan: 2 different contents planned for go.mod",
+		},
+		{
+			name:    "non-adjacent readme parts merged once",
+			actions: []domain.Action{at("README.md", "## Gin\n"), at("README.md", "## Air\n"), at("README.md", "## Gin\n")},
+			want:    []domain.Action{at("README.md", "## Gin\n\n## Air\n")},
+		},
+		{
+			name:    "other files conflict",
+			actions: []domain.Action{at("main.go", "package main\n"), at("main.go", "package app\n")},
+			wantErr: "scaffold plan: 2 different contents planned for main.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeActions(tt.actions, dir)
+			if tt.wantErr != "" {
+				var scaffoldErr *apperrors.ScaffoldError
+				if !errors.As(err, &scaffoldErr) || err.Error() != tt.wantErr {
+					t.Fatalf("mergeActions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeActions() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("mergeActions() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStampHeaders(t *testing.T) {
+	const text = "Generated by project-initiator v1.2.0 on 2024-06-01 for my app — edit freely"
+	tests := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{path: "main.go", content: "package main\n", want: "// " + text + "\n\npackage main\n"},
+		{path: "go.mod", content: "module x\n", want: "// " + text + "\n\nmodule x\n"},
+		{path: "src/index.ts", content: "serve();\n", want: "// " + text + "\n\nserve();\n"},
+		{path: "app/main.py", content: "print()\n", want: "# " + text + "\n\nprint()\n"},
+		{path: "pyproject.toml", content: "[project]\n", want: "# " + text + "\n\n[project]\n"},
+		{path: "sqlc.yaml", content: "version: 2\n", want: "# " + text + "\n\nversion: 2\n"},
+		{path: "Makefile", content: "all:\n", want: "# " + text + "\n\nall:\n"},
+		{path: "db/schema.sql", content: "CREATE TABLE t;\n", want: "-- " + text + "\n\nCREATE TABLE t;\n"},
+		{path: "README.md", content: "# app\n", want: "<!-- " + text + " -->\n\n# app\n"},
+		{path: "src/index.php", content: "<?php\n\necho 1;\n", want: "<?php\n// " + text + "\n\necho 1;\n"},
+		{path: "run.sh", content: "#!/bin/sh\nexit 0\n", want: "#!/bin/sh\n# " + text + "\nexit 0\n"},
+		{path: "scripts/dev.py", content: "#!/usr/bin/env python3", want: "#!/usr/bin/env python3\n# " + text + "\n"},
+		// Skipped: JSON, lockfiles, and files without a known syntax.
+		{path: "package.json", content: "{}\n", want: "{}\n"},
+		{path: "tsconfig.json", content: "{}\n", want: "{}\n"},
+		{path: "go.sum", content: "x v1 h1:y\n", want: "x v1 h1:y\n"},
+		{path: "pnpm-lock.yaml", content: "lockfileVersion: 9\n", want: "lockfileVersion: 9\n"},
+		{path: "yarn.lock", content: "x\n", want: "x\n"},
+		{path: ".gitignore", content: "bin/\n", want: "bin/\n"},
+		{path: ".env.example", content: "PORT=8080\n", want: "PORT=8080\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			actions := []domain.Action{{Path: filepath.Join("/proj", tt.path), Content: tt.content}}
+			stampHeaders(actions, text)
+			if actions[0].Content != tt.want {
+				t.Errorf("content = %q, want %q", actions[0].Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestStampHeaders_HTMLCommentStaysClosed(t *testing.T) {
+	actions := []domain.Action{{Path: "/proj/README.md", Content: "# x\n"}}
+	stampHeaders(actions, headerText("dev", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), "a-->b"))
+	first, _, _ := strings.Cut(actions[0].Content, "\n")
+	if strings.Count(first, "--") != 2 || !strings.HasSuffix(first, " -->") {
+		t.Errorf("header = %q, want the name unable to close the comment", first)
+	}
+}
+
+func TestPlan_GeneratedHeader(t *testing.T) {
+	planner := DefaultPlanner()
+	planner.now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+	req := Request{Language: "Node.js", Framework: "Express", Name: "My API", Dir: t.TempDir()}
+
+	plan, err := planner.Plan(req)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	header := "Generated by project-initiator " + toolVersion() + " on 2024-06-01 for My API — edit freely"
+	for _, action := range plan.Actions {
+		if strings.Contains(action.Content, header) {
+			t.Errorf("%s has a header without GeneratedHeader", action.Path)
+		}
+	}
+
+	req.GeneratedHeader = true
+	plan, err = planner.Plan(req)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	for _, action := range plan.Actions {
+		stamped := strings.Contains(action.Content, header)
+		switch filepath.Base(action.Path) {
+		case "index.js", "README.md":
+			if !stamped {
+				t.Errorf("%s = %q, want the header", action.Path, action.Content)
+			}
+		case "package.json":
+			if stamped {
+				t.Errorf("package.json has a header; JSON has no comments")
+			}
+		}
+	}
+}
+
+func TestPlan_MergesSharedFiles(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
+		Language: "Node.js",
+		Name:     "Merged",
+		Env:      []domain.EnvVar{{Name: "PORT", Example: "3000"}},
+		Templates: []domain.Template{
+			{RelativePath: ".gitignore", Content: "node_modules/\n"},
+			{RelativePath: "README.md", Content: "# {{.Name}}\n"},
+			{RelativePath: ".gitignore", Content: "node_modules/\ncoverage/\n"},
+			{RelativePath: "README.md", Content: "## Docker\n"},
+		},
+	}})
+	plan, err := planner.Plan(Request{Language: "Node.js", Framework: "Merged", Name: "app", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := map[string]string{
+		".gitignore":   "node_modules/\n.env\n\ncoverage/\n",
+		"README.md":    "# app\n\n## Docker\n",
+		".env.example": "",
+	}
+	if len(plan.Actions) != len(want) {
+		t.Fatalf("planned %d actions, want %d", len(plan.Actions), len(want))
+	}
+	for _, action := range plan.Actions {
+		name := filepath.Base(action.Path)
+		if content := want[name]; content != "" && action.Content != content {
+			t.Errorf("%s = %q, want %q", name, action.Content, content)
+		}
+	}
+}
+
+func TestPlan_SizeLimit(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
+		Language: "Go",
+		Name:     "Assets",
+		Templates: []domain.Template{
+			{RelativePath: "main.go", Content: "package main\n"},
+			{RelativePath: "assets/blob.bin", Content: strings.Repeat("x", 2048)},
+		},
+	}})
+	req := Request{Language: "Go", Framework: "Assets", Name: "app", Dir: t.TempDir()}
+
+	if _, err := planner.Plan(req); err != nil {
+		t.Fatalf("Plan() with the default limit error = %v", err)
+	}
+
+	planner.MaxPlanBytes = 1024
+	_, err := planner.Plan(req)
+	var scaffoldErr *apperrors.ScaffoldError
+	if !errors.As(err, &scaffoldErr) || scaffoldErr.Op != "plan" {
+		t.Fatalf("Plan() error = %v, want a plan ScaffoldError", err)
+	}
+	if !strings.Contains(err.Error(), "over the 1024 byte limit") {
+		t.Errorf("Plan() error = %q, want the limit named", err)
+	}
+}
+
+func TestPlan_ActionModes(t *testing.T) {
+	planner := NewPlanner([]domain.Framework{{
+		Language: "Go",
+		Name:     "Scripts",
+		Templates: []domain.Template{
+			{RelativePath: "main.go", Content: "package main\n"},
+			{RelativePath: "scripts/entrypoint.sh", Content: "#!/bin/sh\nexec ./{{.Name}}\n"},
+			{RelativePath: "scripts/setup", Content: "set -e\n", Mode: 0o700},
+			{RelativePath: "scripts/readonly.sh", Content: "#!/bin/sh\n", Mode: 0o444},
+		},
+	}})
+
+	plan, err := planner.Plan(Request{Language: "Go", Framework: "Scripts", Name: "app", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := map[string]os.FileMode{
+		"main.go":               0,
+		"scripts/entrypoint.sh": 0o755,
+		"scripts/setup":         0o700,
+		"scripts/readonly.sh":   0o444,
+	}
+	for _, action := range plan.Actions {
+		rel, _ := filepath.Rel(plan.ProjectDir, action.Path)
+		mode, ok := want[filepath.ToSlash(rel)]
+		if !ok {
+			continue
+		}
+		if action.Mode != mode {
+			t.Errorf("%s mode = %o, want %o", rel, action.Mode, mode)
+		}
+		delete(want, filepath.ToSlash(rel))
+	}
+	if len(want) > 0 {
+		t.Errorf("files not planned: %v", want)
+	}
+}
+
 func TestApply_DryRunNoFiles(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -562,7 +2055,7 @@ func TestApply_DryRunNoFiles(t *testing.T) {
 	}
 
 	applier := NewApplier()
-	if err := applier.Apply(plan, true); err != nil {
+	if _, err := applier.Apply(plan, true); err != nil {
 		t.Fatalf("Apply() error = %v", err)
 	}
 
@@ -592,12 +2085,77 @@ func TestApply_ErrorIfFileExists(t *testing.T) {
 	}
 
 	applier := NewApplier()
-	err := applier.Apply(plan, false)
+	_, err := applier.Apply(plan, false)
 	if err == nil {
 		t.Error("expected error when file exists")
 	}
 }
 
+func TestApply_ForceOverwrites(t *testing.T) {
+	tempDir := t.TempDir()
+	existingFile := filepath.Join(tempDir, "existing.txt")
+	if err := os.WriteFile(existingFile, []byte("existing"), 0o644); err != nil {
+		t.Fatalf("failed to create existing file: %v", err)
+	}
+
+	plan := domain.Plan{Actions: []domain.Action{{Path: existingFile, Content: "new content"}}}
+
+	applier := NewApplier()
+	applier.Force = true
+	if _, err := applier.Apply(plan, false); err != nil {
+		t.Fatalf("Apply() with Force error = %v", err)
+	}
+	got, _ := os.ReadFile(existingFile)
+	if string(got) != "new content" {
+		t.Errorf("file content = %q, want overwritten", got)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	tempDir := t.TempDir()
+	existingFile := filepath.Join(tempDir, "existing.txt")
+	if err := os.WriteFile(existingFile, []byte("existing"), 0o644); err != nil {
+		t.Fatalf("failed to create existing file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		plan domain.Plan
+		want []string
+	}{
+		{
+			name: "clean plan",
+			plan: domain.Plan{Actions: []domain.Action{{Path: filepath.Join(tempDir, "new.txt")}}},
+		},
+		{
+			name: "existing file",
+			plan: domain.Plan{Actions: []domain.Action{{Path: existingFile}, {Path: filepath.Join(tempDir, "new.txt")}}},
+			want: []string{existingFile},
+		},
+		{
+			name: "generator into existing dir",
+			plan: domain.Plan{ProjectDir: tempDir, Generator: "composer-laravel"},
+			want: []string{tempDir},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Conflicts(tt.plan)
+			if err != nil {
+				t.Fatalf("Conflicts() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Conflicts() = %v, want %v", got, tt.want)
+			}
+			// Checking must not create anything.
+			if _, err := os.Stat(filepath.Join(tempDir, "new.txt")); !errors.Is(err, os.ErrNotExist) {
+				t.Error("Conflicts() wrote to disk")
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Library code generation
 // ---------------------------------------------------------------------------
@@ -768,6 +2326,18 @@ func TestGoLibrariesMain(t *testing.T) {
 			libraries: []string{"gin", "gorm", "sqlc"},
 			want:      []string{"http.NewServer", "db.Open", "sqlc generate"},
 		},
+		{
+			name:      "graphql on gin",
+			libraries: []string{"gin", "graphql"},
+			want:      []string{`"github.com/gin-gonic/gin"`, "/graph\"", `server.Any("/query", gin.WrapH(graph.Handler()))`, "server.Run"},
+			notWant:   []string{`"net/http"`, "http.NewServeMux"},
+		},
+		{
+			name:      "graphql on net/http",
+			libraries: []string{"graphql"},
+			want:      []string{`"net/http"`, "/graph\"", "http.NewServeMux()", `mux.Handle("/query", graph.Handler())`, "http.ListenAndServe"},
+			notWant:   []string{"internal/http", "gin"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -813,3 +2383,755 @@ func TestGoLibrariesMain(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// ListOptions
+// ---------------------------------------------------------------------------
+
+func TestListOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled Disabled
+		hidden   []string
+		visible  []string
+	}{
+		{
+			name:    "nothing disabled lists everything",
+			visible: []string{"Go/Cobra", "Go/Vanilla", "PHP/Laravel"},
+		},
+		{
+			name:     "disabled language hides all its frameworks",
+			disabled: Disabled{Languages: []string{"php"}},
+			hidden:   []string{"PHP/Vanilla", "PHP/Laravel"},
+			visible:  []string{"Go/Cobra", "Python/FastAPI"},
+		},
+		{
+			name:     "disabled framework hides only that combo",
+			disabled: Disabled{Frameworks: []string{"go/cobra"}},
+			hidden:   []string{"Go/Cobra"},
+			visible:  []string{"Go/Vanilla", "PHP/Laravel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed := map[string]bool{}
+			for _, opt := range ListOptions(Frameworks, tt.disabled) {
+				listed[opt.Language+"/"+opt.Name] = true
+			}
+			for _, combo := range tt.hidden {
+				if listed[combo] {
+					t.Errorf("%s should be hidden", combo)
+				}
+			}
+			for _, combo := range tt.visible {
+				if !listed[combo] {
+					t.Errorf("%s should be listed", combo)
+				}
+			}
+		})
+	}
+}
+
+func TestFrameworks_HaveDescriptions(t *testing.T) {
+	for _, fw := range Frameworks {
+		if strings.TrimSpace(fw.Description) == "" {
+			t.Errorf("%s/%s has no Description", fw.Language, fw.Name)
+		}
+	}
+}
+
+func TestFrameworks_AtMostOneRecommendedPerLanguage(t *testing.T) {
+	recommended := map[string]string{}
+	for _, fw := range Frameworks {
+		if !fw.Recommended {
+			continue
+		}
+		if prev, ok := recommended[fw.Language]; ok {
+			t.Errorf("%s recommends both %s and %s", fw.Language, prev, fw.Name)
+		}
+		recommended[fw.Language] = fw.Name
+	}
+	if recommended["Go"] != "Cobra" {
+		t.Errorf("Go recommends %q, want Cobra", recommended["Go"])
+	}
+}
+
+func TestDoubleNested(t *testing.T) {
+	tests := []struct {
+		dir    string
+		layout string
+		want   bool
+	}{
+		{dir: filepath.Join("projects", "go", "my-app"), want: false},
+		{dir: filepath.Join("projects", "my-app", "go", "my-app"), want: true},
+		{dir: filepath.Join("projects", "My-App", "go", "my-app"), want: true},
+		{dir: filepath.Join("my-app", "go", "my-app"), want: true},
+		{dir: filepath.Join("api", "services", "go", "api"), want: false},
+		{dir: filepath.Join("projects", "my-app", "my-app"), layout: LayoutFlat, want: true},
+		{dir: filepath.Join("api", "services", "api"), layout: LayoutFlat, want: false},
+		{dir: filepath.Join("go", "my-app"), want: false},
+		{dir: "my-app", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := DoubleNested(tt.dir, tt.layout); got != tt.want {
+				t.Errorf("DoubleNested(%q, %q) = %v, want %v", tt.dir, tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamedByPlaceholder(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{dir: filepath.Join("work", "{slug}"), want: true},
+		{dir: filepath.Join("work", "{name}") + string(filepath.Separator), want: true},
+		{dir: filepath.Join("work", "app-{slug}"), want: true},
+		{dir: filepath.Join("{slug}", "work"), want: false},
+		{dir: filepath.Join("work", "my-app"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := NamedByPlaceholder(tt.dir); got != tt.want {
+				t.Errorf("NamedByPlaceholder(%q) = %v, want %v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_Flatten(t *testing.T) {
+	base := filepath.Join("projects", "my-app")
+	tests := []struct {
+		name    string
+		flatten bool
+		want    string
+	}{
+		{name: "nests by default", flatten: false, want: filepath.Join("projects", "my-app", "Go", "my-app")},
+		{name: "flatten drops repeated segment", flatten: true, want: filepath.Join("projects", "Go", "my-app")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlanner().Plan(Request{
+				Language:  "Go",
+				Framework: "Vanilla",
+				Name:      "my-app",
+				Dir:       base,
+				Flatten:   tt.flatten,
+			})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if plan.ProjectDir != tt.want {
+				t.Errorf("ProjectDir = %q, want %q", plan.ProjectDir, tt.want)
+			}
+		})
+	}
+}
+
+func TestLibrariesFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		framework string
+		want      []string
+	}{
+		{name: "combo with libraries", language: "Go", framework: "Cobra", want: []string{"Gin", "CORS", "Gorm", "Sqlc", "GraphQL", "Air", "Testify", "Version"}},
+		{name: "case-insensitive lookup", language: "go", framework: "vanilla", want: []string{"Gin", "CORS", "Gorm", "Sqlc", "OpenAPI", "GraphQL", "Air", "Testify", "Version"}},
+		{name: "combo without libraries", language: "Python", framework: "FastAPI", want: nil},
+		{name: "unknown combo", language: "Rust", framework: "Axum", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, lib := range LibrariesFor(tt.language, tt.framework) {
+				got = append(got, lib.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LibrariesFor(%q, %q) = %v, want %v", tt.language, tt.framework, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOfferedLibraries_DropsDuplicates(t *testing.T) {
+	opt := domain.Framework{Libraries: []domain.Library{{Name: "Gin"}, {Name: " "}, {Name: "gin"}, {Name: "Gorm"}}}
+	var got []string
+	for _, lib := range OfferedLibraries(opt) {
+		got = append(got, lib.Name)
+	}
+	if want := []string{"Gin", "Gorm"}; !slices.Equal(got, want) {
+		t.Errorf("OfferedLibraries() = %v, want %v", got, want)
+	}
+}
+
+func TestPlan_RejectsLibraryMatchingFramework(t *testing.T) {
+	orig := library.Specs
+	library.Specs = append(append([]library.Spec{}, orig...), library.Spec{Name: "cobra", IncompatibleFrameworks: []string{"Cobra"}}, library.Spec{Name: "viper", IncompatibleFrameworks: []string{"cobra"}})
+	t.Cleanup(func() { library.Specs = orig })
+
+	options := []domain.Framework{
+		{Language: "Go", Name: "Gin", Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}}},
+		{Language: "Go", Name: "Cobra", Libraries: []domain.Library{{Name: "Viper"}, {Name: "Gorm"}}},
+	}
+	tests := []struct {
+		name      string
+		framework string
+		libraries []string
+		wantErr   string
+	}{
+		{name: "library named after framework", framework: "Gin", libraries: []string{"gin"}, wantErr: `library "gin" cannot be added to the Gin framework`},
+		{name: "spec lists framework", framework: "Cobra", libraries: []string{"Viper"}, wantErr: `library "Viper" cannot be added to the Cobra framework`},
+		{name: "compatible library", framework: "Gin", libraries: []string{"Gorm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Language: "Go", Framework: tt.framework, Name: "myapp", Dir: t.TempDir(), Libraries: tt.libraries}
+			_, err := NewPlanner(options).Plan(req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Plan() error = %v", err)
+				}
+				return
+			}
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Plan() error = %v, want ValidationError containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOfferedLibraries_DropsIncompatible(t *testing.T) {
+	opt := domain.Framework{Language: "Go", Name: "Gin", Libraries: []domain.Library{{Name: "gin"}, {Name: "Gorm"}}}
+	var got []string
+	for _, lib := range OfferedLibraries(opt) {
+		got = append(got, lib.Name)
+	}
+	if want := []string{"Gorm"}; !slices.Equal(got, want) {
+		t.Errorf("OfferedLibraries() = %v, want %v", got, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// manifest
+// ---------------------------------------------------------------------------
+
+func TestApply_WritesManifest(t *testing.T) {
+	plan, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Vanilla", Name: "tracked", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := NewApplier().Apply(plan, false)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	manifest, err := ReadManifest(plan.ProjectDir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	var got []string
+	for _, file := range manifest.Files {
+		got = append(got, file.Path)
+	}
+	var want []string
+	for _, path := range written {
+		rel, _ := filepath.Rel(plan.ProjectDir, path)
+		want = append(want, filepath.ToSlash(rel))
+	}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("manifest files = %v, want %v", got, want)
+	}
+	if !slices.Contains(got, "main.go") {
+		t.Errorf("manifest files = %v, want main.go", got)
+	}
+}
+
+func TestDiffManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	first := domain.Plan{ProjectDir: dir, Actions: []domain.Action{
+		{Path: path("kept.txt"), Content: "v1\n"},
+		{Path: path("edited.txt"), Content: "v1\n"},
+		{Path: path("removed.txt"), Content: "v1\n"},
+	}}
+	if _, err := NewApplier().Apply(first, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path("edited.txt"), []byte("mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path("removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path("own.txt"), []byte("mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := domain.Plan{ProjectDir: dir, Actions: []domain.Action{
+		{Path: path("kept.txt"), Content: "v2\n"},
+		{Path: path("edited.txt"), Content: "v2\n"},
+		{Path: path("removed.txt"), Content: "v1\n"},
+		{Path: path("own.txt"), Content: "v2\n"},
+		{Path: path("added.txt"), Content: "v2\n"},
+	}}
+	diffs, err := DiffManifest(second, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FileDiff{
+		{Path: path("kept.txt"), State: FileGenerated, Changed: true},
+		{Path: path("edited.txt"), State: FileModified, Changed: true},
+		{Path: path("removed.txt"), State: FileDeleted, Changed: false},
+		{Path: path("own.txt"), State: FileUntracked, Changed: true},
+		{Path: path("added.txt"), State: FileNew, Changed: true},
+	}
+	if !slices.Equal(diffs, want) {
+		t.Errorf("DiffManifest() =\n%v\nwant\n%v", diffs, want)
+	}
+}
+
+func TestReadManifest_Missing(t *testing.T) {
+	if _, err := ReadManifest(t.TempDir()); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadManifest() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// durable writes
+// ---------------------------------------------------------------------------
+
+func TestApply_DurableWrites(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(existing, []byte("old content that is longer than the new one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A temp file left behind by a crash must not get in the way.
+	if err := os.WriteFile(filepath.Join(dir, ".main.go.tmp"), []byte("stale"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	plan := domain.Plan{ProjectDir: dir, Actions: []domain.Action{
+		{Path: existing, Content: "package main\n"},
+		{Path: filepath.Join(dir, "sub", "big.txt"), Content: strings.Repeat("0123456789abcdef", 1<<14)},
+	}}
+
+	applier := NewApplier()
+	applier.Force = true
+	applier.DurableWrites = true
+	if _, err := applier.Apply(plan, false); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	for _, action := range plan.Actions {
+		got, err := os.ReadFile(action.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != action.Content {
+			t.Errorf("%s has %d bytes, want %d", action.Path, len(got), len(action.Content))
+		}
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*", ".*.tmp"))
+	top, _ := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	if leftovers = append(leftovers, top...); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestWriteDurable_ErrorNamesPhaseAndPath(t *testing.T) {
+	dir := t.TempDir()
+	// Renaming a file over a non-empty directory fails.
+	target := filepath.Join(dir, "taken")
+	if err := os.MkdirAll(filepath.Join(target, "child"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeDurable(target, []byte("data"))
+	if err == nil || !strings.HasPrefix(err.Error(), "rename "+target+":") {
+		t.Errorf("writeDurable() error = %v, want it to start with %q", err, "rename "+target+":")
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, ".taken.tmp")); !errors.Is(statErr, fs.ErrNotExist) {
+		t.Errorf("temp file not cleaned up after a failed rename: %v", statErr)
+	}
+}
+
+// trickleWriter accepts at most one byte per Write, like a filesystem that
+// returns short writes.
+type trickleWriter struct{ strings.Builder }
+
+func (w *trickleWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return w.Builder.Write(p[:1])
+}
+
+func TestWriteAll_RetriesShortWrites(t *testing.T) {
+	var w trickleWriter
+	if err := writeAll(&w, []byte("complete")); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.String(); got != "complete" {
+		t.Errorf("wrote %q, want %q", got, "complete")
+	}
+}
+
+func BenchmarkApply(b *testing.B) {
+	plan, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Cobra", Name: "bench", Dir: b.TempDir()})
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, durable := range []bool{false, true} {
+		name := "direct"
+		if durable {
+			name = "durable"
+		}
+		b.Run(name, func(b *testing.B) {
+			applier := NewApplier()
+			applier.Force = true
+			applier.DurableWrites = durable
+			for b.Loop() {
+				// Remove the files so every iteration writes them all.
+				if err := os.RemoveAll(plan.ProjectDir); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := applier.Apply(plan, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// atomic apply
+// ---------------------------------------------------------------------------
+
+func TestApply_AtomicFailureLeavesNoProject(t *testing.T) {
+	base := t.TempDir()
+	projectDir := filepath.Join(base, "svc")
+	plan := domain.Plan{ProjectDir: projectDir, BaseDir: base, Actions: []domain.Action{
+		{Path: filepath.Join(projectDir, "main.go"), Content: "package main\n"},
+		{Path: filepath.Join(projectDir, "README.md"), Content: "# svc\n"},
+		// main.go is a file, so nothing can be written below it.
+		{Path: filepath.Join(projectDir, "main.go", "nested.go"), Content: "package nested\n"},
+	}}
+
+	var events []FileEvent
+	applier := NewApplier()
+	applier.Atomic = true
+	applier.Progress = func(event FileEvent) { events = append(events, event) }
+	written, err := applier.Apply(plan, false)
+	if err == nil {
+		t.Fatal("Apply() succeeded, want an error")
+	}
+	if len(written) != 0 {
+		t.Errorf("written = %v, want none reported for an atomic failure", written)
+	}
+	if _, err := os.Lstat(projectDir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("project dir after a failed atomic apply: %v, want it absent", err)
+	}
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("base dir holds %v, want the staging dir removed", entries)
+	}
+	for _, event := range events {
+		if !strings.HasPrefix(event.Path, projectDir+string(filepath.Separator)) {
+			t.Errorf("event path %s, want it under the project dir, not the staging dir", event.Path)
+		}
+	}
+}
+
+func TestApply_AtomicWritesProject(t *testing.T) {
+	tests := []struct {
+		name        string
+		crossDevice bool
+	}{
+		{name: "rename"},
+		{name: "cross-device copy", crossDevice: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.crossDevice {
+				orig := rename
+				rename = func(string, string) error {
+					return &os.LinkError{Op: "rename", Err: syscall.EXDEV}
+				}
+				t.Cleanup(func() { rename = orig })
+			}
+			base := t.TempDir()
+			projectDir := filepath.Join(base, "svc")
+			// A staging dir left by a crash is replaced.
+			if err := os.MkdirAll(filepath.Join(stagingDir(projectDir), "stale"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			plan := domain.Plan{ProjectDir: projectDir, BaseDir: base, Actions: []domain.Action{
+				{Path: filepath.Join(projectDir, "main.go"), Content: "package main\n"},
+				{Path: filepath.Join(projectDir, "scripts", "run.sh"), Content: "#!/bin/sh\n", Mode: 0o755},
+			}}
+
+			applier := NewApplier()
+			applier.Atomic = true
+			written, err := applier.Apply(plan, false)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if want := []string{plan.Actions[0].Path, plan.Actions[1].Path}; !slices.Equal(written, want) {
+				t.Errorf("written = %v, want %v", written, want)
+			}
+			for _, action := range plan.Actions {
+				got, err := os.ReadFile(action.Path)
+				if err != nil || string(got) != action.Content {
+					t.Errorf("%s = %q, %v; want %q", action.Path, got, err, action.Content)
+				}
+			}
+			if info, err := os.Stat(plan.Actions[1].Path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o755 {
+				t.Errorf("run.sh mode = %v, want 0755", info.Mode())
+			}
+			if _, err := os.Stat(filepath.Join(projectDir, ManifestName)); err != nil {
+				t.Errorf("manifest missing: %v", err)
+			}
+			if _, err := os.Lstat(stagingDir(projectDir)); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("staging dir left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestApply_AtomicExistingDirWritesInPlace(t *testing.T) {
+	projectDir := t.TempDir()
+	keep := filepath.Join(projectDir, "notes.txt")
+	if err := os.WriteFile(keep, []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plan := domain.Plan{ProjectDir: projectDir, Actions: []domain.Action{
+		{Path: filepath.Join(projectDir, "main.go"), Content: "package main\n"},
+	}}
+
+	applier := NewApplier()
+	applier.Atomic = true
+	if _, err := applier.Apply(plan, false); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	for _, path := range []string{keep, plan.Actions[0].Path} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// apply ignore list
+// ---------------------------------------------------------------------------
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{rel: ".git/config", want: true},
+		{rel: ".git/hooks/pre-commit", want: true},
+		{rel: ".gitignore", want: false},
+		{rel: ".DS_Store", want: true},
+		{rel: "src/.DS_Store", want: false},
+		{rel: ".vscode/settings.json", want: true},
+		{rel: "README.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			if got := Ignored(tt.rel, DefaultApplyIgnore); got != tt.want {
+				t.Errorf("Ignored(%q) = %v, want %v", tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_PrepopulatedDir(t *testing.T) {
+	writeFile := func(t *testing.T, path string, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readFile := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	setup := func(t *testing.T) (string, domain.Plan) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, ".vscode", "settings.json"), "{\"mine\": true}")
+		writeFile(t, filepath.Join(dir, "README.md"), "# my notes\n")
+		writeFile(t, filepath.Join(dir, "go.mod"), "module demo\n")
+		plan := domain.Plan{
+			ProjectDir: dir,
+			Actions: []domain.Action{
+				{Path: filepath.Join(dir, ".vscode", "settings.json"), Content: "{}"},
+				{Path: filepath.Join(dir, "README.md"), Content: "# demo\n"},
+				{Path: filepath.Join(dir, "go.mod"), Content: "module demo\n"},
+				{Path: filepath.Join(dir, "main.go"), Content: "package main\n"},
+			},
+		}
+		return dir, plan
+	}
+
+	t.Run("only non-ignored differing files conflict", func(t *testing.T) {
+		dir, plan := setup(t)
+		applier := NewApplier()
+		applier.Ignore = DefaultApplyIgnore
+
+		conflicts, err := applier.Conflicts(plan)
+		if err != nil {
+			t.Fatalf("Conflicts() error = %v", err)
+		}
+		if want := []string{filepath.Join(dir, "README.md")}; !slices.Equal(conflicts, want) {
+			t.Errorf("Conflicts() = %v, want %v", conflicts, want)
+		}
+		if _, err := applier.Apply(plan, false); !errors.Is(err, apperrors.ErrProjectExists) {
+			t.Errorf("Apply() error = %v, want ErrProjectExists", err)
+		}
+	})
+
+	t.Run("skip existing keeps user files", func(t *testing.T) {
+		dir, plan := setup(t)
+		applier := NewApplier()
+		applier.Ignore = DefaultApplyIgnore
+		applier.SkipExisting = true
+
+		written, err := applier.Apply(plan, false)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if want := []string{filepath.Join(dir, "main.go")}; !slices.Equal(written, want) {
+			t.Errorf("Apply() = %v, want only the files it wrote: %v", written, want)
+		}
+		if got := readFile(t, filepath.Join(dir, "README.md")); got != "# my notes\n" {
+			t.Errorf("README.md = %q, want it untouched", got)
+		}
+		if got := readFile(t, filepath.Join(dir, ".vscode", "settings.json")); got != "{\"mine\": true}" {
+			t.Errorf("ignored file was overwritten: %q", got)
+		}
+		if got := readFile(t, filepath.Join(dir, "main.go")); got != "package main\n" {
+			t.Errorf("main.go = %q, want it written", got)
+		}
+	})
+
+	t.Run("force still skips ignored files", func(t *testing.T) {
+		dir, plan := setup(t)
+		applier := NewApplier()
+		applier.Ignore = DefaultApplyIgnore
+		applier.Force = true
+
+		if _, err := applier.Apply(plan, false); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got := readFile(t, filepath.Join(dir, "README.md")); got != "# demo\n" {
+			t.Errorf("README.md = %q, want it overwritten", got)
+		}
+		if got := readFile(t, filepath.Join(dir, ".vscode", "settings.json")); got != "{\"mine\": true}" {
+			t.Errorf("ignored file was overwritten: %q", got)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// symlinked directories
+// ---------------------------------------------------------------------------
+
+// symlinkDir links link to target, skipping the test where symlinks cannot
+// be created (e.g. Windows without developer mode).
+func symlinkDir(t *testing.T, target string, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+}
+
+func TestApply_SymlinkedDirs(t *testing.T) {
+	plan := func(t *testing.T, base string) domain.Plan {
+		t.Helper()
+		p, err := DefaultPlanner().Plan(Request{Language: "Go", Framework: "Vanilla", Name: "demo", Dir: base})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		return p
+	}
+
+	t.Run("refuses a symlinked language dir", func(t *testing.T) {
+		base, outside := t.TempDir(), t.TempDir()
+		symlinkDir(t, outside, filepath.Join(base, "Go"))
+
+		_, err := NewApplier().Apply(plan(t, base), false)
+		var scaffoldErr *apperrors.ScaffoldError
+		if !errors.As(err, &scaffoldErr) || !errors.Is(err, apperrors.ErrSymlinkedDir) {
+			t.Fatalf("Apply() error = %v, want a ScaffoldError wrapping ErrSymlinkedDir", err)
+		}
+		if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+			t.Errorf("symlink target was written to: %v", entries)
+		}
+	})
+
+	t.Run("allowSymlinkedDirs opts in", func(t *testing.T) {
+		base, outside := t.TempDir(), t.TempDir()
+		symlinkDir(t, outside, filepath.Join(base, "Go"))
+
+		applier := NewApplier()
+		applier.AllowSymlinkedDirs = true
+		if _, err := applier.Apply(plan(t, base), false); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "demo", "go.mod")); err != nil {
+			t.Errorf("project not written through the symlink: %v", err)
+		}
+	})
+
+	t.Run("symlinked base dir is trusted", func(t *testing.T) {
+		link := filepath.Join(t.TempDir(), "projects")
+		symlinkDir(t, t.TempDir(), link)
+
+		if _, err := NewApplier().Apply(plan(t, link), false); err != nil {
+			t.Errorf("Apply() error = %v, want the base dir symlink allowed", err)
+		}
+	})
+
+	t.Run("actions must stay inside the project dir", func(t *testing.T) {
+		dir := t.TempDir()
+		escaping := domain.Plan{
+			ProjectDir: filepath.Join(dir, "Go", "demo"),
+			BaseDir:    dir,
+			Actions:    []domain.Action{{Path: filepath.Join(dir, "Go", "evil.txt"), Content: "x"}},
+		}
+		applier := NewApplier()
+		applier.AllowSymlinkedDirs = true
+		if _, err := applier.Apply(escaping, false); err == nil || !strings.Contains(err.Error(), "outside the project dir") {
+			t.Errorf("Apply() error = %v, want an outside-the-project-dir refusal", err)
+		}
+	})
+}
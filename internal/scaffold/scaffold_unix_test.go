@@ -0,0 +1,100 @@
+//go:build unix
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"project-initiator/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// file permissions (umask and setgid are Unix-only)
+// ---------------------------------------------------------------------------
+
+// withUmask sets the process umask for the rest of the test.
+func withUmask(t *testing.T, mask int) {
+	t.Helper()
+	orig := syscall.Umask(mask)
+	t.Cleanup(func() { syscall.Umask(orig) })
+}
+
+func permissionPlan(root string) domain.Plan {
+	projectDir := filepath.Join(root, "Go", "demo")
+	return domain.Plan{
+		ProjectDir: projectDir,
+		Actions:    []domain.Action{{Path: filepath.Join(projectDir, "cmd", "main.go"), Content: "package main\n"}},
+	}
+}
+
+func TestApply_Permissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		umask    int
+		fileMode os.FileMode
+		dirMode  os.FileMode
+		wantFile os.FileMode
+		wantDir  os.FileMode
+	}{
+		{name: "umask narrows defaults", umask: 0o027, wantFile: 0o640, wantDir: 0o750},
+		{name: "group-writable umask", umask: 0o002, wantFile: 0o664, wantDir: 0o775},
+		{name: "explicit modes ignore umask", umask: 0o077, fileMode: 0o664, dirMode: 0o775, wantFile: 0o664, wantDir: 0o775},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withUmask(t, tt.umask)
+			plan := permissionPlan(t.TempDir())
+			applier := &Applier{FileMode: tt.fileMode, DirMode: tt.dirMode}
+			if _, err := applier.Apply(plan, false); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			file := statMode(t, plan.Actions[0].Path)
+			if file.Perm() != tt.wantFile {
+				t.Errorf("file mode = %o, want %o", file.Perm(), tt.wantFile)
+			}
+			for _, dir := range []string{filepath.Dir(plan.ProjectDir), plan.ProjectDir, filepath.Join(plan.ProjectDir, "cmd")} {
+				if got := statMode(t, dir).Perm(); got != tt.wantDir {
+					t.Errorf("%s mode = %o, want %o", dir, got, tt.wantDir)
+				}
+			}
+		})
+	}
+}
+
+func TestApply_KeepsParentSetgid(t *testing.T) {
+	withUmask(t, 0o002)
+	for _, dirMode := range []os.FileMode{0, 0o770} {
+		root := t.TempDir()
+		if err := os.Chmod(root, os.ModeSetgid|0o775); err != nil {
+			t.Fatal(err)
+		}
+		if statMode(t, root)&os.ModeSetgid == 0 {
+			t.Skip("filesystem does not support setgid directories")
+		}
+
+		plan := permissionPlan(root)
+		applier := &Applier{DirMode: dirMode}
+		if _, err := applier.Apply(plan, false); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		for _, dir := range []string{filepath.Dir(plan.ProjectDir), plan.ProjectDir, filepath.Join(plan.ProjectDir, "cmd")} {
+			if statMode(t, dir)&os.ModeSetgid == 0 {
+				t.Errorf("DirMode %o: %s lost the setgid bit", dirMode, dir)
+			}
+		}
+	}
+}
+
+func statMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Mode()
+}
@@ -0,0 +1,54 @@
+// Package strutil holds the string list helpers shared by the wizard and
+// option listing, where names such as "Go" and "go" are the same option.
+package strutil
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Key is the form names are matched by: trimmed and lower-cased.
+func Key(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// Unique returns values in order without blank entries or entries whose
+// Key repeats an earlier one. The first spelling wins.
+func Unique(values []string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		key := Key(value)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+// Compare orders strings case-insensitively, breaking ties such as "Go"
+// and "go" case-sensitively so the order never depends on the input's.
+func Compare(a, b string) int {
+	return cmp.Or(
+		cmp.Compare(strings.ToLower(a), strings.ToLower(b)),
+		cmp.Compare(a, b),
+	)
+}
+
+// Sort sorts values in place by Compare.
+func Sort(values []string) {
+	slices.SortStableFunc(values, Compare)
+}
+
+// ContainsFold reports whether values holds target, ignoring case.
+func ContainsFold(values []string, target string) bool {
+	return slices.ContainsFunc(values, func(value string) bool {
+		return strings.EqualFold(value, target)
+	})
+}
@@ -0,0 +1,154 @@
+package strutil
+
+import "testing"
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{
+			name:  "no duplicates",
+			input: []string{"a", "b", "c"},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "exact duplicates",
+			input: []string{"a", "b", "a", "c", "b"},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "case insensitive dedup keeps first occurrence",
+			input: []string{"Go", "go", "GO"},
+			want:  []string{"Go"},
+		},
+		{
+			name:  "blank and space-padded entries",
+			input: []string{"", "  ", "Go", " go "},
+			want:  []string{"Go"},
+		},
+		{
+			name:  "empty list",
+			input: []string{},
+			want:  []string{},
+		},
+		{
+			name:  "nil list",
+			input: nil,
+			want:  []string{},
+		},
+		{
+			name:  "whitespace only entries are removed",
+			input: []string{"a", "  ", "b", "\t", "c"},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "entries with leading trailing whitespace dedup on trimmed key",
+			input: []string{"  Go ", "Go"},
+			want:  []string{"  Go "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unique(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Unique(%v) returned %d elements, want %d: got %v", tt.input, len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Unique(%v)[%d] = %q, want %q", tt.input, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{
+			name:  "already sorted",
+			input: []string{"alpha", "beta", "gamma"},
+			want:  []string{"alpha", "beta", "gamma"},
+		},
+		{
+			name:  "reverse order",
+			input: []string{"gamma", "beta", "alpha"},
+			want:  []string{"alpha", "beta", "gamma"},
+		},
+		{
+			name:  "case insensitive sort",
+			input: []string{"Banana", "apple", "Cherry"},
+			want:  []string{"apple", "Banana", "Cherry"},
+		},
+		{
+			name:  "case-only ties break case-sensitively",
+			input: []string{"go", "Go", "GO"},
+			want:  []string{"GO", "Go", "go"},
+		},
+		{
+			name:  "ties sort the same whatever the input order",
+			input: []string{"rust", "Go", "Rust", "go"},
+			want:  []string{"Go", "go", "Rust", "rust"},
+		},
+		{
+			name:  "single element",
+			input: []string{"only"},
+			want:  []string{"only"},
+		},
+		{
+			name:  "empty",
+			input: []string{},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// copy to avoid mutating test data
+			values := make([]string, len(tt.input))
+			copy(values, tt.input)
+
+			Sort(values)
+
+			if len(values) != len(tt.want) {
+				t.Fatalf("Sort(%v) produced %d elements, want %d", tt.input, len(values), len(tt.want))
+			}
+			for i := range values {
+				if values[i] != tt.want[i] {
+					t.Errorf("Sort(%v)[%d] = %q, want %q", tt.input, i, values[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContainsFold(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		target string
+		want   bool
+	}{
+		{"found exact", []string{"Go", "Python", "Rust"}, "Python", true},
+		{"not found", []string{"Go", "Python", "Rust"}, "Java", false},
+		{"case insensitive match", []string{"Go", "Python"}, "go", true},
+		{"case insensitive match reverse", []string{"go"}, "Go", true},
+		{"empty list", []string{}, "Go", false},
+		{"nil list", nil, "Go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ContainsFold(tt.values, tt.target)
+			if got != tt.want {
+				t.Errorf("ContainsFold(%v, %q) = %v, want %v", tt.values, tt.target, got, tt.want)
+			}
+		})
+	}
+}
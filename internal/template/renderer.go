@@ -19,17 +19,34 @@ func NewRenderer() *Renderer {
 	}
 }
 
-// Render parses and executes a template with the given data.
-func (r *Renderer) Render(source string, data any) (string, error) {
-	tmpl, err := template.New("template").Funcs(r.funcMap).Parse(source)
+// Render parses and executes a template with the given data. name, such
+// as the template's relative path, appears in errors along with the line
+// (and column, where known) that failed.
+func (r *Renderer) Render(name string, source string, data any) (string, error) {
+	tmpl, err := r.parse(name, source)
 	if err != nil {
-		return "", fmt.Errorf("parse template: %w", err)
+		return "", err
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("execute template: %w", err)
+		return "", fmt.Errorf("execute: %w", err)
 	}
 
 	return buf.String(), nil
 }
+
+// Check parses a template without executing it, reporting syntax errors
+// the way Render does.
+func (r *Renderer) Check(name string, source string) error {
+	_, err := r.parse(name, source)
+	return err
+}
+
+func (r *Renderer) parse(name string, source string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(r.funcMap).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	return tmpl, nil
+}
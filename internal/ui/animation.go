@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -105,11 +106,20 @@ func buildAnimCache(s styles) animCache {
 		}
 	}
 
+	flash := lipgloss.NewStyle().Foreground(lipgloss.Color("#c0caf5")).Bold(true).Background(panelBg)
+	if s.highContrast {
+		// One strong color instead of the gradient.
+		for i := range normal {
+			normal[i] = lipgloss.NewStyle().Foreground(s.accent).Bold(true).Background(panelBg)
+		}
+		flash = normal[0]
+	}
+
 	return animCache{
 		dim:    lipgloss.NewStyle().Foreground(s.soft).Background(panelBg),
 		glow:   glow,
 		bg:     lipgloss.NewStyle().Background(panelBg),
-		flash:  lipgloss.NewStyle().Foreground(lipgloss.Color("#c0caf5")).Bold(true).Background(panelBg),
+		flash:  flash,
 		normal: normal,
 	}
 }
@@ -150,6 +160,14 @@ func renderAnimatedBorder(width int, frame int, cache animCache) string {
 	return b.String()
 }
 
+// renderStaticBorder returns the border line without the spark.
+func renderStaticBorder(width int, cache animCache) string {
+	if width < 2 {
+		return ""
+	}
+	return cache.dim.Render("╾" + strings.Repeat("═", width-2) + "╼")
+}
+
 // titleBlockHeight is the fixed number of lines the title block occupies:
 // top border (1) + 9 art lines + bottom border (1) = 11.
 const titleBlockHeight = 11
@@ -160,6 +178,9 @@ const titleBlockHeight = 11
 // When width is smaller than the art (e.g. during panel entrance animation),
 // the art is horizontally clipped to fit and each line is capped at width.
 func (m model) renderAnimatedTitle(width int) string {
+	if m.ascii {
+		return m.renderPlainTitle(width)
+	}
 	if width < 4 {
 		// Panel too small for any meaningful title — return empty lines
 		// so the title block still occupies the expected height.
@@ -174,6 +195,12 @@ func (m model) renderAnimatedTitle(width int) string {
 	art := asciiArt()
 	aw := artWidth()
 	frame := m.titleFrame
+	border := func(frame int) string { return renderAnimatedBorder(width, frame, m.animCache) }
+	if m.noAnimation {
+		// Fully revealed, with no flash column or spark.
+		frame = revealTotalTicks()
+		border = func(int) string { return renderStaticBorder(width, m.animCache) }
+	}
 	revealedCols := frame * revealColumns
 	if revealedCols > aw {
 		revealedCols = aw
@@ -196,7 +223,7 @@ func (m model) renderAnimatedTitle(width int) string {
 	var lines []string
 
 	// Top border
-	lines = append(lines, renderAnimatedBorder(width, frame, cache))
+	lines = append(lines, border(frame))
 
 	// Render each art line with typing reveal
 	for lineIdx, artLine := range art {
@@ -250,7 +277,69 @@ func (m model) renderAnimatedTitle(width int) string {
 	}
 
 	// Bottom border
-	lines = append(lines, renderAnimatedBorder(width, frame+width/2, cache))
+	lines = append(lines, border(frame+width/2))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
+
+// ---------------------------------------------------------------------------
+// Plain banner fallback
+// ---------------------------------------------------------------------------
+
+// plainBanner is shown instead of the block-letter art on terminals that
+// cannot draw block glyphs.
+const plainBanner = "SCAFFOLD WIZARD"
+
+// renderPlainTitle renders the banner between "=" rules using only ASCII.
+func (m model) renderPlainTitle(width int) string {
+	if width < 1 {
+		width = 1
+	}
+	rule := m.animCache.dim.Render(strings.Repeat("=", width))
+	text := plainBanner
+	if runeLen(text) > width {
+		text = string([]rune(text)[:width])
+	}
+	banner := m.animCache.normal[0].Width(width).Align(lipgloss.Center).Render(text)
+	return lipgloss.JoinVertical(lipgloss.Left, rule, banner, rule)
+}
+
+// AccessibleRequested reports whether the ACCESSIBLE environment variable
+// asks for accessible mode.
+func AccessibleRequested() bool {
+	return envEnabled(os.Getenv("ACCESSIBLE"))
+}
+
+// AnimationDisabled reports whether the NO_ANIMATION environment variable
+// asks for animation to be turned off.
+func AnimationDisabled() bool {
+	return envEnabled(os.Getenv("NO_ANIMATION"))
+}
+
+// envEnabled treats any value but empty, "0" and "false" as on.
+func envEnabled(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
+
+// SupportsBlockGlyphs guesses whether the terminal can draw the block
+// characters used by the title art: it needs a UTF-8 locale and a terminal
+// other than the bare Linux console or a dumb terminal.
+func SupportsBlockGlyphs() bool {
+	return blockGlyphsSupported(os.Getenv)
+}
+
+func blockGlyphsSupported(getenv func(string) string) bool {
+	switch getenv("TERM") {
+	case "dumb", "linux":
+		return false
+	}
+	// The first locale variable that is set wins, as in setlocale(3).
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/atotto/clipboard"
+
+	"project-initiator/internal/scaffold"
+)
+
+// Clipboard reads text from a clipboard. The wizard offers slug-like
+// clipboard text as the project name.
+type Clipboard interface {
+	ReadAll() (string, error)
+}
+
+// SystemClipboard reads the system clipboard, through pbpaste, xclip, xsel,
+// wl-paste or the Windows API.
+type SystemClipboard struct{}
+
+func (SystemClipboard) ReadAll() (string, error) {
+	return clipboard.ReadAll()
+}
+
+// clipboardName returns the clipboard text when it is already a slug, such
+// as a repository name copied from a browser, or "" when it is anything
+// else or cannot be read.
+func clipboardName(c Clipboard, charLimit int) string {
+	if c == nil {
+		return ""
+	}
+	text, err := c.ReadAll()
+	if err != nil {
+		return ""
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > charLimit || scaffold.Slugify(text) != text {
+		return ""
+	}
+	return text
+}
@@ -3,19 +3,33 @@ package ui
 import (
 	"cmp"
 	"fmt"
+	"path/filepath"
 	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+
+	"project-initiator/internal/domain"
+	"project-initiator/internal/strutil"
 )
 
 // newCleanList creates a list.Model with all chrome (title, filter, help,
 // status bar, pagination) disabled — the standard configuration used by
-// every list in the wizard.
+// every list in the wizard. Navigation uses the wizard's arrow and vim keys;
+// paging is left to pgup/pgdown because the list's letter shortcuts would
+// shadow b (back) and type-ahead.
 func newCleanList(items []list.Item, delegate list.ItemDelegate, w, h int) list.Model {
 	l := list.New(items, delegate, w, h)
+	l.KeyMap.CursorUp = keys.Up
+	l.KeyMap.CursorDown = keys.Down
+	l.KeyMap.GoToStart = keys.Top
+	l.KeyMap.GoToEnd = keys.Bottom
+	l.KeyMap.PrevPage = key.NewBinding(key.WithKeys("pgup"))
+	l.KeyMap.NextPage = key.NewBinding(key.WithKeys("pgdown"))
 	l.Title = ""
 	l.SetShowTitle(false)
 	l.SetShowFilter(false)
@@ -26,17 +40,44 @@ func newCleanList(items []list.Item, delegate list.ItemDelegate, w, h int) list.
 	return l
 }
 
-func buildFrameworkList(language string, options map[string][]string, defaultFramework string, s styles) list.Model {
-	frameworks := options[language]
-	if len(frameworks) == 0 {
-		frameworks = []string{"Vanilla"}
-	}
-	frameworks = uniqueStrings(frameworks)
-	sortStrings(frameworks)
+// frameworkInfo is the per-framework metadata summarised on each framework row.
+type frameworkInfo struct {
+	description string // option-provided summary; empty for legacy options
+	libraries   int    // number of optional libraries offered
+	generator   bool   // scaffolded by an external generator, not templates
+	requires    string // external tool needed by a generator, if any
+	recommended bool   // badged as the suggested framework for the language
+	deprecated  bool   // listed last with a "(deprecated)" suffix
+	// deprecatedLibraries holds the lower-cased names of the framework's
+	// deprecated libraries.
+	deprecatedLibraries map[string]bool
+}
+
+func optionKey(language string, framework string) string {
+	return language + "::" + framework
+}
+
+func buildFrameworkList(language string, options map[string][]string, info map[string]frameworkInfo, pinned map[string]bool, defaultFramework string, s styles) list.Model {
+	frameworks := strutil.Unique(options[language])
+	strutil.Sort(frameworks)
+	// Pinned frameworks move to the top and deprecated ones to the bottom;
+	// the stable sort keeps each group alphabetical.
+	slices.SortStableFunc(frameworks, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(pinRank(pinned, language, a), pinRank(pinned, language, b)),
+			compareDeprecated(info[optionKey(language, a)].deprecated, info[optionKey(language, b)].deprecated),
+		)
+	})
 	items := make([]list.Item, 0, len(frameworks))
 	for _, framework := range frameworks {
-		description := frameworkDescription(language, framework)
-		items = append(items, listItem{label: framework, description: description})
+		description := frameworkRowDescription(language, framework, info[optionKey(language, framework)])
+		items = append(items, listItem{
+			label:       framework,
+			description: description,
+			pinned:      pinned[pinKey(language, framework)],
+			recommended: info[optionKey(language, framework)].recommended,
+			deprecated:  info[optionKey(language, framework)].deprecated,
+		})
 	}
 
 	model := newCleanList(items, listDelegate{styles: s}, 0, 0)
@@ -48,49 +89,250 @@ func buildFrameworkList(language string, options map[string][]string, defaultFra
 	return model
 }
 
-func buildLibraryItems(language string, framework string, options map[string][]string, selected map[string]bool) []list.Item {
-	key := language + "::" + framework
-	libraries := uniqueStrings(options[key])
-	sortStrings(libraries)
+// buildLibraryItems lists a framework's libraries alphabetically, with the
+// deprecated ones, keyed by lower-cased name, last.
+func buildLibraryItems(language string, framework string, options map[string][]string, selected map[string]bool, deprecated map[string]bool) []list.Item {
+	key := optionKey(language, framework)
+	libraries := strutil.Unique(options[key])
+	strutil.Sort(libraries)
+	slices.SortStableFunc(libraries, func(a, b string) int {
+		return compareDeprecated(deprecated[strings.ToLower(a)], deprecated[strings.ToLower(b)])
+	})
 	items := make([]list.Item, 0, len(libraries))
 	for _, lib := range libraries {
 		label := "[ ] " + lib
 		if selected[lib] {
 			label = "[x] " + lib
 		}
-		items = append(items, listItem{label: label, description: "optional package"})
+		items = append(items, listItem{label: label, description: "optional package", deprecated: deprecated[strings.ToLower(lib)]})
 	}
 	return items
 }
 
-func buildLibrariesList(language string, framework string, options map[string][]string, selected map[string]bool, s styles) list.Model {
-	items := buildLibraryItems(language, framework, options, selected)
+// compareDeprecated orders current entries before deprecated ones.
+func compareDeprecated(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func buildLibrariesList(language string, framework string, options map[string][]string, selected map[string]bool, deprecated map[string]bool, s styles) list.Model {
+	items := buildLibraryItems(language, framework, options, selected, deprecated)
 	return newCleanList(items, listDelegate{styles: s}, 0, 0)
 }
 
-func uniqueStrings(values []string) []string {
-	seen := map[string]struct{}{}
-	result := make([]string, 0, len(values))
-	for _, value := range values {
-		key := strings.ToLower(strings.TrimSpace(value))
-		if key == "" {
-			continue
+// libraryDelegate draws library rows, without descriptions once they are
+// collapsed.
+func (m model) libraryDelegate() listDelegate {
+	return listDelegate{styles: m.styles, compact: m.compactLibraries}
+}
+
+// libraryRows is how many library rows fit in the list, keeping a line for
+// the position indicator when they don't all fit.
+func (m model) libraryRows() int {
+	height := m.libraries.Height()
+	rowHeight := m.libraryDelegate().Height()
+	if len(m.libraries.Items())*rowHeight > height {
+		height--
+	}
+	return max(height/rowHeight, 1)
+}
+
+// libraryOffset scrolls the library list the least needed to keep the
+// cursor in view.
+func (m model) libraryOffset() int {
+	rows := m.libraryRows()
+	cursor := m.libraries.Index()
+	offset := m.libOffset
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+rows {
+		offset = cursor - rows + 1
+	}
+	return clamp(offset, 0, max(len(m.libraries.Items())-rows, 0))
+}
+
+// renderLibraries draws the rows in view and, when the list overflows, a
+// position indicator under them.
+func (m model) renderLibraries() string {
+	items := m.libraries.Items()
+	rows := m.libraryRows()
+	offset := m.libraryOffset()
+	end := min(offset+rows, len(items))
+
+	var b strings.Builder
+	delegate := m.libraryDelegate()
+	for i := offset; i < end; i++ {
+		delegate.Render(&b, m.libraries, i, items[i])
+	}
+	view := strings.TrimSuffix(b.String(), "\n")
+	if len(items) <= rows {
+		return view
+	}
+
+	dash := "–"
+	if m.ascii {
+		dash = "-"
+	}
+	indicator := fmt.Sprintf("  %d%s%d of %d", offset+1, dash, end, len(items))
+	return view + "\n" + m.styles.listDesc.Render(indicator)
+}
+
+// planSummary describes how much a plan will write.
+type planSummary struct {
+	files     int
+	bytes     int
+	generator string     // set when an external generator does the writing
+	largest   []fileSize // biggest files first
+}
+
+type fileSize struct {
+	path  string // relative to the project dir
+	bytes int
+}
+
+// summarizePlan totals a plan's file actions and keeps the limit largest.
+func summarizePlan(plan domain.Plan, limit int) planSummary {
+	summary := planSummary{generator: plan.Generator}
+	sizes := make([]fileSize, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		path := action.Path
+		if rel, err := filepath.Rel(plan.ProjectDir, action.Path); err == nil {
+			path = filepath.ToSlash(rel)
 		}
-		if _, ok := seen[key]; ok {
+		sizes = append(sizes, fileSize{path: path, bytes: len(action.Content)})
+		summary.files++
+		summary.bytes += len(action.Content)
+	}
+	slices.SortStableFunc(sizes, func(a, b fileSize) int {
+		return cmp.Compare(b.bytes, a.bytes)
+	})
+	if len(sizes) > limit {
+		sizes = sizes[:limit]
+	}
+	summary.largest = sizes
+	return summary
+}
+
+// humanSize formats a byte count as "512 B", "8.2 KB" or "1.4 MB".
+func humanSize(n int) string {
+	const kb, mb = 1024, 1024 * 1024
+	switch {
+	case n < kb:
+		return fmt.Sprintf("%d B", n)
+	case n < mb:
+		return fmt.Sprintf("%.1f KB", float64(n)/kb)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/mb)
+	}
+}
+
+func (s planSummary) headline() string {
+	if s.generator != "" && s.files == 0 {
+		return "files are created by the " + s.generator + " generator"
+	}
+	return fmt.Sprintf("will create %s (%s)", pluralize(s.files, "file", "files"), humanSize(s.bytes))
+}
+
+// pinKey formats a pin the way it is stored in config, e.g. "Go/Gin".
+func pinKey(language string, framework string) string {
+	return language + "/" + framework
+}
+
+func pinRank(pinned map[string]bool, language string, framework string) int {
+	if pinned[pinKey(language, framework)] {
+		return 0
+	}
+	return 1
+}
+
+// pinSet indexes the configured pins, matching them case-insensitively
+// against the options so lookups can use the options' own spelling. Pins for
+// unknown options are kept so they survive being saved back to config.
+func pinSet(pins []string, frameworks []domain.Framework) map[string]bool {
+	set := map[string]bool{}
+	for _, pin := range pins {
+		pin = strings.TrimSpace(pin)
+		if pin == "" {
 			continue
 		}
-		seen[key] = struct{}{}
-		result = append(result, value)
+		for _, opt := range frameworks {
+			if strings.EqualFold(pinKey(opt.Language, opt.Name), pin) {
+				pin = pinKey(opt.Language, opt.Name)
+				break
+			}
+		}
+		set[pin] = true
 	}
-	return result
+	return set
 }
 
-func sortStrings(values []string) {
-	slices.SortFunc(values, func(a, b string) int {
-		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
-	})
+func sortedPins(pinned map[string]bool) []string {
+	return selectedLibraries(pinned)
+}
+
+// languageDescription summarises a language's options for the language list,
+// e.g. "3 templates · 1 generator", or names the option when there is only one.
+func languageDescription(language string, frameworks []string, info map[string]frameworkInfo) string {
+	frameworks = strutil.Unique(frameworks)
+	if len(frameworks) == 1 {
+		kind := "template"
+		if info[optionKey(language, frameworks[0])].generator {
+			kind = "generator"
+		}
+		return fmt.Sprintf("%s (%s)", frameworks[0], kind)
+	}
+
+	templates, generators := 0, 0
+	for _, framework := range frameworks {
+		if info[optionKey(language, framework)].generator {
+			generators++
+		} else {
+			templates++
+		}
+	}
+	var parts []string
+	if templates > 0 {
+		parts = append(parts, pluralize(templates, "template", "templates"))
+	}
+	if generators > 0 {
+		parts = append(parts, pluralize(generators, "generator", "generators"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func pluralize(n int, singular string, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// frameworkRowDescription appends library and tooling chips to the framework
+// description, e.g. "CLI app structure · 3 libraries".
+func frameworkRowDescription(language string, framework string, info frameworkInfo) string {
+	description := info.description
+	if description == "" {
+		description = frameworkDescription(language, framework)
+	}
+	parts := []string{description}
+	if info.libraries > 0 {
+		parts = append(parts, pluralize(info.libraries, "library", "libraries"))
+	}
+	if info.requires != "" {
+		parts = append(parts, "requires "+info.requires)
+	}
+	return strings.Join(parts, " · ")
 }
 
+// frameworkDescription is the fallback for options registered without a
+// Description of their own.
 func frameworkDescription(language string, framework string) string {
 	switch strings.ToLower(framework) {
 	case "vanilla":
@@ -125,6 +367,23 @@ func selectListItem(model *list.Model, label string) {
 	}
 }
 
+// typeAheadIndex returns the index of the first item whose label starts with
+// the given letter (case-insensitive), or -1 when none does.
+func typeAheadIndex(items []list.Item, letter rune) int {
+	if !unicode.IsLetter(letter) {
+		return -1
+	}
+	prefix := strings.ToLower(string(letter))
+	for i, item := range items {
+		if candidate, ok := item.(listItem); ok {
+			if strings.HasPrefix(strings.ToLower(candidate.label), prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 func selectedLibraries(selected map[string]bool) []string {
 	values := make([]string, 0, len(selected))
 	for name, isSelected := range selected {
@@ -132,23 +391,98 @@ func selectedLibraries(selected map[string]bool) []string {
 			values = append(values, name)
 		}
 	}
-	sortStrings(values)
+	strutil.Sort(values)
 	return values
 }
 
 func (m model) listHeightFixed() int {
-	reservedRows := 14
-	return clamp(m.panelH-reservedRows, 6, 30)
+	return clamp(m.panelH-m.reservedRows(), 6, 30)
+}
+
+// panelChromeRows is the panel's border and padding, which renderFrame takes
+// off panelH to get the body height.
+const panelChromeRows = 4
+
+// reservedRows counts the rows renderFrame draws around the content block,
+// measured from the rendered header and status bar so the list keeps fitting
+// when their composition changes.
+func (m model) reservedRows() int {
+	contentWidth := 82 // default panelW(88) - 6
+	if m.panelW > 0 {
+		contentWidth = m.panelW - 6
+	}
+	titleBlock, stageTitleLine, stageSubtitleLine := m.renderHeader(contentWidth)
+	return panelChromeRows +
+		lipgloss.Height(titleBlock) +
+		lipgloss.Height(stageTitleLine) +
+		lipgloss.Height(stageSubtitleLine) +
+		lipgloss.Height(m.renderStatus(m.stepLabel(), contentWidth))
+}
+
+// resizeLists fits the lists to the panel. The list height depends on the
+// status bar, so toggling the full help resizes them too.
+func (m *model) resizeLists() {
+	listWidth := clamp(m.panelW-8, 56, 100)
+	listHeight := m.listHeightFixed()
+	m.languages.SetSize(listWidth, listHeight)
+	m.framework.SetSize(listWidth, listHeight)
+	m.libraries.SetSize(listWidth, listHeight)
+}
+
+// renderHeader renders the title block and the current stage's title and
+// subtitle lines.
+func (m model) renderHeader(contentWidth int) (titleBlock string, stageTitleLine string, stageSubtitleLine string) {
+	titleBlock = m.renderAnimatedTitle(contentWidth)
+	stageTitleLine = m.styles.listTitle.Render(stageTitle(m.stage))
+	stageSubtitleLine = m.styles.subheader.Render(stageSubtitle(m.stage))
+	return titleBlock, stageTitleLine, stageSubtitleLine
 }
 
-func contains(values []string, target string) bool {
-	for _, value := range values {
-		if strings.EqualFold(value, target) {
-			return true
+// renderStatus renders the status bar: step label, progress bar and help
+// bindings, with the config file names right-aligned in width columns. The
+// full help lists every binding and the full config paths instead.
+func (m model) renderStatus(step string, width int) string {
+	prog := m.progress.ViewAs(m.stageProgress())
+	if m.help.ShowAll {
+		lines := []string{step + "  " + prog, m.help.FullHelpView(keys.FullHelp())}
+		if len(m.configFiles) > 0 {
+			lines = append(lines, "Config: "+strings.Join(m.configFiles, ", "))
 		}
+		return m.styles.status.Render(strings.Join(lines, "\n"))
 	}
+	prefix := step + "  " + prog + "  •  "
+	// The help view drops trailing bindings that do not fit, so a narrow
+	// panel cuts the line short on the right instead of wrapping it.
+	bar := m.help
+	bar.Width = max(width-lipgloss.Width(prefix), 0)
+	left := ansi.Truncate(prefix+bar.ShortHelpView(keys.forStage(m.stage).ShortHelp()), width, "…")
+	return m.styles.status.Render(left + m.configSegment(width-lipgloss.Width(left)))
+}
 
-	return false
+// minConfigSegment is the narrowest the status bar's config segment gets
+// before it is dropped.
+const minConfigSegment = 8
+
+// configSegment right-aligns the config file names in room columns after
+// a two-space gap, truncating them on narrow panels.
+func (m model) configSegment(room int) string {
+	if len(m.configFiles) == 0 {
+		return ""
+	}
+	room -= 2
+	if room < minConfigSegment {
+		return ""
+	}
+	names := make([]string, len(m.configFiles))
+	for i, path := range m.configFiles {
+		names[i] = filepath.Base(path)
+	}
+	tail := "…"
+	if m.ascii {
+		tail = "..."
+	}
+	segment := ansi.Truncate(strings.Join(names, " + "), room, tail)
+	return strings.Repeat(" ", room+2-lipgloss.Width(segment)) + segment
 }
 
 func clamp(value int, min int, max int) int {
@@ -173,6 +507,8 @@ func stageTitle(s stage) string {
 		return "Name your project"
 	case stageConfirm:
 		return "Confirm your selections"
+	case stageEmpty:
+		return "No templates available"
 	default:
 		return ""
 	}
@@ -190,55 +526,59 @@ func stageSubtitle(s stage) string {
 		return "This will create the folder name"
 	case stageConfirm:
 		return "Review before creating the project"
+	case stageEmpty:
+		return "Your template config filtered out every option"
 	default:
 		return ""
 	}
 }
 
-func (m model) stageProgress() float64 {
-	hasLibs := len(m.libraries.Items()) > 0
-	totalSteps := 3
-	if hasLibs {
-		totalSteps = 4
+// pipeline returns the input stages the current flow walks through, in order.
+// Optional stages are only included when they have something to offer, so
+// step labels and progress are derived from the same source.
+func (m model) pipeline() []stage {
+	stages := []stage{stageLanguage, stageFramework}
+	if len(m.libraries.Items()) > 0 {
+		stages = append(stages, stageLibraries)
 	}
-	switch m.stage {
-	case stageLanguage:
-		return 0.0
-	case stageFramework:
-		return 1.0 / float64(totalSteps)
-	case stageLibraries:
-		return 2.0 / float64(totalSteps)
-	case stageName:
-		if hasLibs {
-			return 3.0 / float64(totalSteps)
-		}
-		return 2.0 / float64(totalSteps)
-	case stageConfirm:
+	return append(stages, stageName)
+}
+
+func (m model) stageProgress() float64 {
+	if m.stage == stageConfirm {
 		return 1.0
-	default:
+	}
+	stages := m.pipeline()
+	idx := slices.Index(stages, m.stage)
+	if idx < 0 {
 		return 0.0
 	}
+	return float64(idx) / float64(len(stages))
 }
 
 func (m model) stepLabel() string {
-	hasLibs := len(m.libraries.Items()) > 0
-	switch m.stage {
-	case stageLanguage:
-		return "Step 1"
-	case stageFramework:
-		return "Step 2"
-	case stageLibraries:
-		return "Step 3/4"
-	case stageName:
-		if hasLibs {
-			return "Step 4/4"
-		}
-		return "Step 3/3"
-	case stageConfirm:
+	if m.stage == stageConfirm {
 		return "Review"
-	default:
+	}
+	stages := m.pipeline()
+	idx := slices.Index(stages, m.stage)
+	if idx < 0 {
 		return ""
 	}
+	return fmt.Sprintf("Step %d/%d", idx+1, len(stages))
+}
+
+// answeredSteps counts the stages of the current pipeline the user has
+// answered. A libraries stage answered for a framework since changed to
+// one without libraries drops out with the pipeline.
+func (m model) answeredSteps() int {
+	n := 0
+	for _, s := range m.pipeline() {
+		if m.answered[s] {
+			n++
+		}
+	}
+	return n
 }
 
 // ---------------------------------------------------------------------------
@@ -277,15 +617,8 @@ func (m model) renderFrame(content string, step string) string {
 	if contentWidth < 1 {
 		contentWidth = 1
 	}
-	titleBlock := m.renderAnimatedTitle(contentWidth)
-
-	// Status bar: step label + progress bar + help bindings.
-	prog := m.progress.ViewAs(m.stageProgress())
-	helpView := m.help.ShortHelpView(keys.ShortHelp())
-	status := m.styles.status.Render(step + "  " + prog + "  •  " + helpView)
-
-	stageTitleLine := m.styles.listTitle.Render(stageTitle(m.stage))
-	stageSubtitleLine := m.styles.subheader.Render(stageSubtitle(m.stage))
+	titleBlock, stageTitleLine, stageSubtitleLine := m.renderHeader(contentWidth)
+	status := m.renderStatus(step, contentWidth)
 	contentBlock := m.renderContentBlock(content, contentWidth)
 
 	// Stage transition — shift the content area horizontally.
@@ -326,29 +659,30 @@ func (m model) renderFrame(content string, step string) string {
 
 // shiftHorizontal shifts ANSI-styled text by offset columns within maxWidth.
 // Positive offset shifts right (content slides in from right); negative shifts left.
-// Uses ANSI-aware operations to preserve escape sequences.
+// Uses ANSI-aware operations to preserve escape sequences. Every line comes
+// back at most maxWidth columns wide, whatever the offset. Invalid UTF-8 is
+// replaced first so cutting it cannot join stray bytes into a wide rune.
 func shiftHorizontal(text string, offset int, maxWidth int) string {
-	if offset == 0 || maxWidth <= 0 {
+	if maxWidth <= 0 {
 		return text
 	}
+	// Content shifted a full width or more is out of view either way.
+	offset = max(-maxWidth, min(offset, maxWidth))
 	lines := strings.Split(text, "\n")
 	result := make([]string, 0, len(lines))
 	for _, line := range lines {
+		line = strings.ToValidUTF8(line, "\uFFFD")
 		if offset > 0 {
 			// Shift right: prepend spaces, then clip to maxWidth.
-			spacer := strings.Repeat(" ", offset)
-			shifted := lipgloss.JoinHorizontal(lipgloss.Top, spacer, line)
-			result = append(result, lipgloss.NewStyle().MaxWidth(maxWidth).Render(shifted))
-		} else {
+			line = strings.Repeat(" ", offset) + line
+		} else if offset < 0 {
 			// Shift left: drop leading visible characters, pad right to maxWidth.
-			drop := -offset
-			truncated := ansi.TruncateLeft(line, drop, "")
-			visW := ansi.StringWidth(truncated)
-			if visW < maxWidth {
-				truncated += strings.Repeat(" ", maxWidth-visW)
+			line = ansi.TruncateLeft(line, -offset, "")
+			if visW := ansi.StringWidth(line); visW < maxWidth {
+				line += strings.Repeat(" ", maxWidth-visW)
 			}
-			result = append(result, truncated)
 		}
+		result = append(result, ansi.Truncate(line, maxWidth, ""))
 	}
 	return strings.Join(result, "\n")
 }
@@ -365,18 +699,33 @@ func (m model) renderContentBlock(content string, width int) string {
 		Render(content)
 }
 
+// nameCounterMargin is how close to the name's character limit the
+// counter appears.
+const nameCounterMargin = 16
+
 func (m model) renderNameInput() string {
 	rowBg := m.styles.panelBg
 	blankLine := lipgloss.NewStyle().Background(rowBg).Render(" ")
 	label := m.styles.inputLabel.Render("Project name")
+	if count := runeLen(m.name.Value()); count >= m.name.CharLimit-nameCounterMargin {
+		label += m.styles.inputLabel.Render(fmt.Sprintf("  %d/%d", count, m.name.CharLimit))
+	}
 	box := m.styles.inputFocused.Render(m.name.View())
 	help := m.styles.help.Render("Tip: Use a short, kebab-case name")
+	if slug, ok := m.nameSuggestion(); ok {
+		dash := "—"
+		if m.ascii {
+			dash = "-"
+		}
+		hint := "Will be created as"
+		if strings.TrimSpace(m.name.Value()) == "" {
+			hint = "From the clipboard:"
+		}
+		help = m.styles.help.Render(fmt.Sprintf("%s %s %s press Tab to use this", hint, slug, dash))
+	}
 
 	if m.nameErr != "" {
-		errStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#f52a65", Dark: "#f7768e"}).
-			Background(rowBg)
-		errLine := errStyle.Render("  " + m.nameErr)
+		errLine := m.styles.err.Render("  " + m.nameErr)
 		return lipgloss.JoinVertical(lipgloss.Left, label, blankLine, box, errLine, blankLine, help)
 	}
 
@@ -401,7 +750,50 @@ func (m model) renderConfirmation() string {
 
 	lines = append(lines, labelStyle.Render("Name        ")+valueStyle.Render(m.result.Name))
 
+	if m.summary != nil {
+		lines = append(lines, labelStyle.Render("Files       ")+valueStyle.Render(m.summary.headline()))
+		if len(m.summary.largest) > 0 {
+			largest := make([]string, 0, len(m.summary.largest))
+			for _, file := range m.summary.largest {
+				largest = append(largest, fmt.Sprintf("%s (%s)", file.path, humanSize(file.bytes)))
+			}
+			lines = append(lines, labelStyle.Render("Largest     ")+valueStyle.Render(strings.Join(largest, ", ")))
+		}
+	}
+
+	if len(m.queued) > 0 {
+		names := make([]string, 0, len(m.queued))
+		for _, project := range m.queued {
+			names = append(names, project.Name)
+		}
+		lines = append(lines, labelStyle.Render("Queued      ")+valueStyle.Render(strings.Join(names, ", ")))
+	}
+
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	switch {
+	case m.planning:
+		planning := "Planning…"
+		if m.ascii {
+			planning = "Planning..."
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, content, blankLine, m.styles.help.Render(planning))
+	case m.planErr != nil:
+		message := m.styles.err.Render("Could not plan the project: " + m.planErr.Error())
+		hint := m.styles.help.Render("Press Enter to retry, or b to edit the name")
+		return lipgloss.JoinVertical(lipgloss.Left, content, blankLine, message, blankLine, hint)
+	}
 	hint := m.styles.help.Render("Press Enter to create project")
+	if m.monorepo {
+		hint = m.styles.help.Render("Press Enter to create all projects, or a to add another")
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, content, blankLine, hint)
 }
+
+func (m model) renderNoOptions() string {
+	rowBg := m.styles.panelBg
+	blankLine := lipgloss.NewStyle().Background(rowBg).Render(" ")
+	message := m.styles.err.Render("There are no languages or frameworks to choose from.")
+	detail := m.styles.help.Render("Check your template config for overrides that hide every option.")
+	hint := m.styles.help.Render("Press any key to exit")
+	return lipgloss.JoinVertical(lipgloss.Left, message, blankLine, detail, blankLine, hint)
+}
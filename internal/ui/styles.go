@@ -12,6 +12,9 @@ import (
 type listItem struct {
 	label       string
 	description string
+	pinned      bool
+	recommended bool
+	deprecated  bool
 }
 
 func (i listItem) Title() string       { return i.label }
@@ -35,11 +38,14 @@ type styles struct {
 	inputFocused lipgloss.Style
 	help         lipgloss.Style
 	status       lipgloss.Style
+	err          lipgloss.Style
 	accent       lipgloss.AdaptiveColor
 	muted        lipgloss.AdaptiveColor
 	soft         lipgloss.AdaptiveColor
 	background   lipgloss.AdaptiveColor
 	panelBg      lipgloss.AdaptiveColor
+	// highContrast is set on the accessible style set.
+	highContrast bool
 }
 
 // Exported color constants used by the wizard UI and post-run output.
@@ -48,51 +54,104 @@ var (
 	Muted  = lipgloss.AdaptiveColor{Light: "#8c8c8c", Dark: "#6b7280"}
 	Text   = lipgloss.AdaptiveColor{Light: "#3760bf", Dark: "#c0caf5"}
 	Green  = lipgloss.AdaptiveColor{Light: "#587539", Dark: "#9ece6a"}
+	Yellow = lipgloss.AdaptiveColor{Light: "#8c6c3e", Dark: "#e0af68"}
+	Red    = lipgloss.AdaptiveColor{Light: "#f52a65", Dark: "#f7768e"}
 )
 
+// palette is the set of colors a style set is built from.
+type palette struct {
+	accent     lipgloss.AdaptiveColor
+	muted      lipgloss.AdaptiveColor
+	soft       lipgloss.AdaptiveColor
+	background lipgloss.AdaptiveColor
+	panelBg    lipgloss.AdaptiveColor
+	text       lipgloss.AdaptiveColor
+	textSoft   lipgloss.AdaptiveColor
+	chipText   lipgloss.AdaptiveColor
+	ghostText  lipgloss.AdaptiveColor
+}
+
 func defaultStyles() styles {
-	accent := Accent
-	muted := Muted
-	soft := lipgloss.AdaptiveColor{Light: "#c4c8da", Dark: "#3b4261"}
-	background := lipgloss.AdaptiveColor{Light: "#d5d6db", Dark: "#1f2335"}
-	panelBg := lipgloss.AdaptiveColor{Light: "#e1e2e7", Dark: "#24283b"}
-	text := Text
-	textSoft := lipgloss.AdaptiveColor{Light: "#6172b0", Dark: "#a9b1d6"}
+	return newStyles(palette{
+		accent:     Accent,
+		muted:      Muted,
+		soft:       lipgloss.AdaptiveColor{Light: "#c4c8da", Dark: "#3b4261"},
+		background: lipgloss.AdaptiveColor{Light: "#d5d6db", Dark: "#1f2335"},
+		panelBg:    lipgloss.AdaptiveColor{Light: "#e1e2e7", Dark: "#24283b"},
+		text:       Text,
+		textSoft:   lipgloss.AdaptiveColor{Light: "#6172b0", Dark: "#a9b1d6"},
+		chipText:   lipgloss.AdaptiveColor{Light: "#ffffff", Dark: "#1a1b26"},
+		ghostText:  lipgloss.AdaptiveColor{Light: "#6172b0", Dark: "#a9b1d6"},
+	})
+}
+
+// highContrastStyles draws black on white, or white on black on dark
+// terminals, with a single strong accent.
+func highContrastStyles() styles {
+	fg := lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"}
+	bg := lipgloss.AdaptiveColor{Light: "#ffffff", Dark: "#000000"}
+	s := newStyles(palette{
+		accent:     lipgloss.AdaptiveColor{Light: "#0000cc", Dark: "#ffff00"},
+		muted:      fg,
+		soft:       fg,
+		background: bg,
+		panelBg:    bg,
+		text:       fg,
+		textSoft:   fg,
+		chipText:   lipgloss.AdaptiveColor{Light: "#ffffff", Dark: "#000000"},
+		ghostText:  bg,
+	})
+	s.highContrast = true
+	return s
+}
+
+func newStyles(p palette) styles {
 	return styles{
-		frame:        lipgloss.NewStyle().Background(background),
-		panel:        lipgloss.NewStyle().Padding(1, 3).BorderStyle(lipgloss.RoundedBorder()).BorderForeground(soft).Background(panelBg),
-		header:       lipgloss.NewStyle().Bold(true).Foreground(text).Background(panelBg),
-		subheader:    lipgloss.NewStyle().Foreground(muted).Background(panelBg),
-		chip:         lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#ffffff", Dark: "#1a1b26"}).Background(accent).Padding(0, 1),
-		chipGhost:    lipgloss.NewStyle().Foreground(textSoft).Background(soft).Padding(0, 1),
-		listTitle:    lipgloss.NewStyle().Bold(true).Foreground(textSoft).Background(panelBg),
-		listSelected: lipgloss.NewStyle().Foreground(text).Bold(true).Background(panelBg),
-		listNormal:   lipgloss.NewStyle().Foreground(textSoft).Background(panelBg),
-		listDesc:     lipgloss.NewStyle().Foreground(muted).Background(panelBg),
-		marker:       lipgloss.NewStyle().Foreground(accent).Bold(true).Background(panelBg),
-		inputLabel:   lipgloss.NewStyle().Foreground(muted).Background(panelBg),
-		inputBox:     lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(soft).Padding(0, 1).Background(panelBg),
-		inputFocused: lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(accent).Padding(0, 1).Background(panelBg),
-		help:         lipgloss.NewStyle().Foreground(muted).Background(panelBg),
-		status:       lipgloss.NewStyle().Foreground(muted).Background(panelBg),
-		accent:       accent,
-		muted:        muted,
-		soft:         soft,
-		background:   background,
-		panelBg:      panelBg,
+		frame:        lipgloss.NewStyle().Background(p.background),
+		panel:        lipgloss.NewStyle().Padding(1, 3).BorderStyle(lipgloss.RoundedBorder()).BorderForeground(p.soft).Background(p.panelBg),
+		header:       lipgloss.NewStyle().Bold(true).Foreground(p.text).Background(p.panelBg),
+		subheader:    lipgloss.NewStyle().Foreground(p.muted).Background(p.panelBg),
+		chip:         lipgloss.NewStyle().Foreground(p.chipText).Background(p.accent).Padding(0, 1),
+		chipGhost:    lipgloss.NewStyle().Foreground(p.ghostText).Background(p.soft).Padding(0, 1),
+		listTitle:    lipgloss.NewStyle().Bold(true).Foreground(p.textSoft).Background(p.panelBg),
+		listSelected: lipgloss.NewStyle().Foreground(p.text).Bold(true).Background(p.panelBg),
+		listNormal:   lipgloss.NewStyle().Foreground(p.textSoft).Background(p.panelBg),
+		listDesc:     lipgloss.NewStyle().Foreground(p.muted).Background(p.panelBg),
+		marker:       lipgloss.NewStyle().Foreground(p.accent).Bold(true).Background(p.panelBg),
+		inputLabel:   lipgloss.NewStyle().Foreground(p.muted).Background(p.panelBg),
+		inputBox:     lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(p.soft).Padding(0, 1).Background(p.panelBg),
+		inputFocused: lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(p.accent).Padding(0, 1).Background(p.panelBg),
+		help:         lipgloss.NewStyle().Foreground(p.muted).Background(p.panelBg),
+		status:       lipgloss.NewStyle().Foreground(p.muted).Background(p.panelBg),
+		err:          lipgloss.NewStyle().Foreground(Red).Background(p.panelBg),
+		accent:       p.accent,
+		muted:        p.muted,
+		soft:         p.soft,
+		background:   p.background,
+		panelBg:      p.panelBg,
 	}
 }
 
 type listDelegate struct {
 	styles styles
+	// compact leaves out descriptions, for one line per item.
+	compact bool
 }
 
-func (d listDelegate) Height() int  { return 2 }
+func (d listDelegate) Height() int {
+	if d.compact {
+		return 1
+	}
+	return 2
+}
 func (d listDelegate) Spacing() int { return 0 }
 func (d listDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
 	return nil
 }
 
+// recommendedBadge is the chip shown next to a language's recommended framework.
+const recommendedBadge = "recommended"
+
 func (d listDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	i, ok := item.(listItem)
 	if !ok {
@@ -112,10 +171,19 @@ func (d listDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		marker = d.styles.marker.Render("› ")
 	}
 	nameLine := marker + nameStyle.Render(i.label)
+	if i.pinned {
+		nameLine = marker + d.styles.marker.Render("★ ") + nameStyle.Render(i.label)
+	}
+	if i.recommended {
+		nameLine += d.styles.listNormal.Render(" ") + d.styles.chip.Render(recommendedBadge)
+	}
+	if i.deprecated {
+		nameLine += d.styles.listDesc.Render(" (deprecated)")
+	}
 	descLine := d.styles.listDesc.Render(i.description)
 	rowStyle := lipgloss.NewStyle().Width(m.Width()).Background(rowBg)
 	_, _ = fmt.Fprintln(w, rowStyle.Render(nameLine))
-	if i.description != "" {
+	if i.description != "" && !d.compact {
 		indent := d.styles.listDesc.Render("  ")
 		_, _ = fmt.Fprintln(w, rowStyle.Render(indent+descLine))
 	}
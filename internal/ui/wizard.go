@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,7 +18,9 @@ import (
 	"github.com/charmbracelet/harmonica"
 	"github.com/charmbracelet/lipgloss"
 
+	"project-initiator/internal/domain"
 	"project-initiator/internal/scaffold"
+	"project-initiator/internal/strutil"
 )
 
 // Result holds the user's selections from the wizard.
@@ -25,6 +29,23 @@ type Result struct {
 	Framework string
 	Name      string
 	Libraries []string
+	// Pinned is the updated "Language/Framework" pin list to persist.
+	Pinned []string
+	// Projects lists every confirmed selection in order. Outside monorepo
+	// mode it holds just the one project described by the fields above.
+	Projects []Project
+	// Steps is how many input stages of the last project were answered,
+	// counted over the same stages the "Step n/N" labels number. Going back
+	// to a stage does not count it twice; the confirm stage is not a step.
+	Steps int
+}
+
+// Project is one queued selection in a monorepo run.
+type Project struct {
+	Language  string
+	Framework string
+	Name      string
+	Libraries []string
 }
 
 type stage int
@@ -36,6 +57,7 @@ const (
 	stageName
 	stageConfirm
 	stageDone
+	stageEmpty
 )
 
 type keyMap struct {
@@ -43,23 +65,90 @@ type keyMap struct {
 	Back  key.Binding
 	Enter key.Binding
 	Space key.Binding
+	Pin   key.Binding
+	Add   key.Binding
+	// Slug replaces the typed name with its suggested slug.
+	Slug key.Binding
+	// Details shows or hides the library descriptions.
+	Details key.Binding
+	// Older and Newer step through recently used names in the name input,
+	// like a shell history.
+	Older key.Binding
+	Newer key.Binding
+	// Help toggles the full help, which also shows the config file paths.
+	// It is left out of the compact help bar to leave room for the config
+	// file names.
+	Help key.Binding
+
+	// List navigation, installed into every wizard list. The vim letters
+	// take precedence over framework type-ahead.
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+}
+
+// forStage returns the compact help for one stage, listing only the keys
+// that do something there, such as space on the libraries stage.
+func (k keyMap) forStage(s stage) stageKeyMap {
+	return stageKeyMap{keyMap: k, stage: s}
+}
+
+// stageKeyMap is a keyMap whose ShortHelp depends on the wizard stage.
+type stageKeyMap struct {
+	keyMap
+	stage stage
 }
 
-// ShortHelp returns bindings for the compact help view.
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Enter, k.Space, k.Back, k.Quit}
+// ShortHelp returns the stage's bindings. Disabled ones, such as add
+// outside monorepo mode, are hidden by the help view.
+func (k stageKeyMap) ShortHelp() []key.Binding {
+	switch k.stage {
+	case stageLanguage:
+		return []key.Binding{k.Enter, k.Quit}
+	case stageFramework:
+		return []key.Binding{k.Enter, k.Pin, k.Back, k.Quit}
+	case stageLibraries:
+		return []key.Binding{k.Space, k.Details, k.Enter, k.Back, k.Quit}
+	case stageName:
+		return []key.Binding{k.Enter, k.Slug, k.Older, k.Quit}
+	case stageConfirm:
+		return []key.Binding{k.Enter, k.Add, k.Back, k.Quit}
+	}
+	return []key.Binding{k.Quit}
 }
 
 // FullHelp returns grouped bindings for the expanded help view.
 func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{k.ShortHelp()}
+	return [][]key.Binding{
+		{k.Enter, k.Space, k.Pin, k.Add},
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Back, k.Help, k.Quit},
+	}
 }
 
 var keys = keyMap{
-	Quit:  key.NewBinding(key.WithKeys("ctrl+c", "esc"), key.WithHelp("esc", "cancel")),
-	Back:  key.NewBinding(key.WithKeys("b", "left", "backspace"), key.WithHelp("b", "back")),
-	Enter: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "continue")),
-	Space: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+	Quit:    key.NewBinding(key.WithKeys("ctrl+c", "esc"), key.WithHelp("esc", "cancel")),
+	Back:    key.NewBinding(key.WithKeys("b", "left", "backspace"), key.WithHelp("b", "back")),
+	Enter:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "continue")),
+	Space:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+	Pin:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pin")),
+	Add:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add another")),
+	Slug:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "use suggested name")),
+	Details: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "descriptions")),
+	Older:   key.NewBinding(key.WithKeys("up"), key.WithHelp("↑/↓", "recent names")),
+	Newer:   key.NewBinding(key.WithKeys("down")),
+	Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "more")),
+
+	Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	Top:    key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("g", "first")),
+	Bottom: key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("G", "last")),
+}
+
+// isNavigation reports whether msg moves the list selection.
+func isNavigation(msg tea.KeyMsg) bool {
+	return key.Matches(msg, keys.Up, keys.Down, keys.Top, keys.Bottom)
 }
 
 type model struct {
@@ -73,17 +162,53 @@ type model struct {
 	result        Result
 	options       map[string][]string
 	libOptions    map[string][]string
-	selectedLibs  map[string]bool
-	err           error
-	width         int
-	height        int
-	panelW        int
-	panelH        int
-	styles        styles
-	animCache     animCache
-	titleFrame    int
-	animationDone bool
-	nameErr       string
+	frameworkInfo map[string]frameworkInfo
+	pinned        map[string]bool
+	monorepo      bool
+	queued        []Project
+	preview       func(Result) (domain.Plan, error)
+	summary       *planSummary
+	// planning is set while Enter on the confirm stage re-plans every
+	// project; planErr holds the failure that keeps the wizard on confirm
+	// for a retry. planSeq discards results from an abandoned attempt.
+	planning     bool
+	planErr      error
+	planSeq      int
+	ascii        bool
+	selectedLibs map[string]bool
+	// answered records the input stages confirmed with Enter for the
+	// current project, for Result.Steps.
+	answered map[stage]bool
+	// libOffset is the first library row in view. The wizard scrolls the
+	// library list itself, line by line, because toggling rebuilds the
+	// items and the list would snap back to a page boundary.
+	libOffset int
+	// compactLibraries collapses the library list to one line per
+	// library, for short terminals. It lasts for the whole wizard.
+	compactLibraries bool
+	configFiles      []string
+	err              error
+	width            int
+	height           int
+	panelW           int
+	panelH           int
+	styles           styles
+	animCache        animCache
+	titleFrame       int
+	animationDone    bool
+	nameErr          string
+	// clipName is the name suggested from the clipboard, if any.
+	clipName string
+	// nameHistory holds recently used names, newest first. historyPos is
+	// the entry shown in the name input, or -1 for what was typed, which
+	// nameDraft keeps while browsing.
+	nameHistory []string
+	historyPos  int
+	nameDraft   string
+	// lastFrameworks is Options.LastFrameworks.
+	lastFrameworks map[string]string
+	// defaultLibs is Options.DefaultLibraries.
+	defaultLibs []string
 
 	// Spring-animated panel entrance.
 	panelSpring harmonica.Spring
@@ -96,48 +221,80 @@ type model struct {
 	transOffset float64 // horizontal offset in columns
 	transVel    float64
 	transActive bool
+	// noAnimation draws the title fully revealed without the border spark
+	// and skips the panel entrance and stage slides.
+	noAnimation bool
+}
+
+// ErrNoOptions is returned when the wizard has no templates to offer.
+var ErrNoOptions = errors.New("no templates available: the template config filtered out every option")
+
+// Options configures the wizard's starting state.
+type Options struct {
+	// Frameworks are the options offered; languages without any are hidden.
+	Frameworks       []domain.Framework
+	DefaultLanguage  string
+	DefaultFramework string
+	// Pinned lists "Language/Framework" combos sorted to the top of their list.
+	Pinned []string
+	// Monorepo lets the confirm stage queue the project and start another.
+	Monorepo bool
+	// ASCII swaps the block-letter title for a plain text banner.
+	ASCII bool
+	// Preview plans the current selection so the confirm screen can show
+	// what will be written. Optional.
+	Preview func(Result) (domain.Plan, error)
+	// AskFramework leaves the framework unselected instead of falling back
+	// to Vanilla, and opens on the framework stage when DefaultLanguage is
+	// one of the offered languages.
+	AskFramework bool
+	// Transitions sets the animation speed: "slow", "fast", or "off" to
+	// stop all animation. Anything else is normal.
+	Transitions string
+	// Accessible turns animation off and switches to high-contrast styles
+	// and the plain text title, for screen readers and low vision.
+	Accessible bool
+	// SkipSplash starts with the title fully revealed instead of typing it
+	// out. Any keypress skips the reveal as well.
+	SkipSplash bool
+	// ConfigFiles lists the config files in merge order. The status bar
+	// shows their names and the full help their paths.
+	ConfigFiles []string
+	// Clipboard is read once at start; slug-like text becomes the name
+	// placeholder, which Tab accepts. Nil leaves the usual placeholder.
+	Clipboard Clipboard
+	// NameHistory lists recently used names, newest first. Up and down in
+	// the name input step through it.
+	NameHistory []string
+	// LastFrameworks maps languages to the framework last created with
+	// each; choosing a language preselects its entry. Languages without
+	// one, or whose framework is no longer offered, get DefaultFramework.
+	LastFrameworks map[string]string
+	// DefaultLibraries are checked on the libraries stage when the chosen
+	// framework offers them, as --libraries asks. Names match without
+	// regard to case; ones the framework does not offer are ignored.
+	DefaultLibraries []string
 }
 
 // NewWizard creates the Bubble Tea model for the project wizard.
-func NewWizard(defaultLanguage string, defaultFramework string) tea.Model {
+func NewWizard(opts Options) tea.Model {
+	return newWizard(opts)
+}
+
+func newWizard(opts Options) model {
+	frameworks := opts.Frameworks
+	defaultLanguage := opts.DefaultLanguage
+	defaultFramework := opts.DefaultFramework
 	s := defaultStyles()
-	options := map[string][]string{}
-	libOptions := map[string][]string{}
-	for _, opt := range scaffold.Frameworks {
-		options[opt.Language] = append(options[opt.Language], opt.Name)
-		if len(opt.Libraries) > 0 {
-			key := opt.Language + "::" + opt.Name
-			for _, lib := range opt.Libraries {
-				libOptions[key] = append(libOptions[key], lib.Name)
-			}
-		}
-	}
-	for lang, frameworks := range options {
-		if !contains(frameworks, "Vanilla") {
-			options[lang] = append([]string{"Vanilla"}, frameworks...)
-		}
+	if opts.Accessible {
+		s = highContrastStyles()
 	}
-	if defaultFramework == "" {
+	options, libOptions, info := indexOptions(frameworks)
+	if defaultFramework == "" && !opts.AskFramework {
 		defaultFramework = "Vanilla"
 	}
 
-	langNames := make([]string, 0, len(options))
-	for lang := range options {
-		langNames = append(langNames, lang)
-	}
-	sortStrings(langNames)
-
-	langItems := make([]list.Item, 0, len(langNames))
-	for _, lang := range langNames {
-		frameworks := options[lang]
-		noun := "templates"
-		if len(frameworks) == 1 {
-			noun = "template"
-		}
-		description := fmt.Sprintf("%d %s", len(frameworks), noun)
-		langItems = append(langItems, listItem{label: lang, description: description})
-	}
-
+	langItems := languageItems(options, info)
 	langList := newCleanList(langItems, listDelegate{styles: s}, 0, 0)
 
 	if defaultLanguage != "" {
@@ -152,6 +309,10 @@ func NewWizard(defaultLanguage string, defaultFramework string) tea.Model {
 	nameInput.Prompt = ""
 	nameInput.Focus()
 	nameInput.CharLimit = 64
+	clipName := clipboardName(opts.Clipboard, nameInput.CharLimit)
+	if clipName != "" {
+		nameInput.Placeholder = clipName
+	}
 
 	// Help model styled to match the status bar.
 	h := help.New()
@@ -170,25 +331,122 @@ func NewWizard(defaultLanguage string, defaultFramework string) tea.Model {
 	// Spring for panel entrance — slightly under-damped for a subtle bounce.
 	panelSpring := harmonica.NewSpring(harmonica.FPS(60), 5.0, 0.7)
 	// Spring for stage transitions — fast, minimal overshoot.
-	transSpring := harmonica.NewSpring(harmonica.FPS(60), 8.0, 0.85)
+	transSpring := harmonica.NewSpring(harmonica.FPS(60), transitionFrequency(opts.Transitions), 0.85)
+	noAnimation := opts.Transitions == "off" || opts.Accessible
+	titleFrame := 0
+	if opts.SkipSplash {
+		titleFrame = revealTotalTicks()
+	}
+
+	pinned := pinSet(opts.Pinned, frameworks)
+
+	startStage := stageLanguage
+	if len(langItems) == 0 {
+		startStage = stageEmpty
+	} else if opts.AskFramework && len(options[defaultLanguage]) > 0 {
+		frameworkList = buildFrameworkList(defaultLanguage, options, info, pinned, "", s)
+		startStage = stageFramework
+	}
 
 	return model{
-		stage:        stageLanguage,
-		languages:    langList,
-		framework:    frameworkList,
-		libraries:    libraryList,
-		name:         nameInput,
-		help:         h,
-		progress:     p,
-		options:      options,
-		libOptions:   libOptions,
-		selectedLibs: map[string]bool{},
-		result:       Result{Language: defaultLanguage, Framework: defaultFramework},
-		styles:       s,
-		animCache:    buildAnimCache(s),
-		panelSpring:  panelSpring,
-		panelScale:   0.0,
-		transSpring:  transSpring,
+		stage:          startStage,
+		languages:      langList,
+		framework:      frameworkList,
+		libraries:      libraryList,
+		name:           nameInput,
+		clipName:       clipName,
+		nameHistory:    opts.NameHistory,
+		lastFrameworks: opts.LastFrameworks,
+		defaultLibs:    opts.DefaultLibraries,
+		historyPos:     -1,
+		help:           h,
+		progress:       p,
+		options:        options,
+		libOptions:     libOptions,
+		frameworkInfo:  info,
+		selectedLibs:   map[string]bool{},
+		answered:       map[stage]bool{},
+		pinned:         pinned,
+		monorepo:       opts.Monorepo,
+		preview:        opts.Preview,
+		configFiles:    opts.ConfigFiles,
+		ascii:          opts.ASCII || opts.Accessible,
+		result:         Result{Language: defaultLanguage, Framework: defaultFramework, Pinned: sortedPins(pinned)},
+		styles:         s,
+		animCache:      buildAnimCache(s),
+		titleFrame:     titleFrame,
+		animationDone:  noAnimation,
+		panelSpring:    panelSpring,
+		panelScale:     0.0,
+		panelReady:     noAnimation,
+		transSpring:    transSpring,
+		noAnimation:    noAnimation,
+	}
+}
+
+// indexOptions copies what the wizard shows out of frameworks: framework
+// names by language, library names and details by optionKey. The wizard
+// keeps only this snapshot, never the slice, so options registered after
+// it starts appear only once a RefreshOptionsMsg brings them in.
+func indexOptions(frameworks []domain.Framework) (map[string][]string, map[string][]string, map[string]frameworkInfo) {
+	options := map[string][]string{}
+	libOptions := map[string][]string{}
+	info := map[string]frameworkInfo{}
+	for _, opt := range frameworks {
+		if strings.TrimSpace(opt.Language) == "" || strings.TrimSpace(opt.Name) == "" {
+			continue
+		}
+		options[opt.Language] = append(options[opt.Language], opt.Name)
+		key := optionKey(opt.Language, opt.Name)
+		var deprecatedLibs map[string]bool
+		for _, lib := range scaffold.OfferedLibraries(opt) {
+			libOptions[key] = append(libOptions[key], lib.Name)
+			if lib.Deprecated != "" || lib.ReplacedBy != "" {
+				if deprecatedLibs == nil {
+					deprecatedLibs = map[string]bool{}
+				}
+				deprecatedLibs[strings.ToLower(lib.Name)] = true
+			}
+		}
+		info[key] = frameworkInfo{
+			description: opt.Description,
+			libraries:   len(libOptions[key]),
+			generator:   opt.Generator != "",
+			requires:    opt.Requires,
+			recommended: opt.Recommended,
+			deprecated:  opt.Deprecated != "" || opt.ReplacedBy != "",
+
+			deprecatedLibraries: deprecatedLibs,
+		}
+	}
+	return options, libOptions, info
+}
+
+// languageItems lists the languages of options alphabetically.
+func languageItems(options map[string][]string, info map[string]frameworkInfo) []list.Item {
+	langNames := make([]string, 0, len(options))
+	for lang := range options {
+		langNames = append(langNames, lang)
+	}
+	strutil.Sort(langNames)
+
+	items := make([]list.Item, 0, len(langNames))
+	for _, lang := range langNames {
+		items = append(items, listItem{label: lang, description: languageDescription(lang, options[lang], info)})
+	}
+	return items
+}
+
+// transitionFrequency returns the stage transition spring's angular
+// frequency for a Transitions speed; higher settles faster.
+func transitionFrequency(speed string) float64 {
+	switch speed {
+	case "slow":
+		return 4.5
+	case "fast":
+		return 14.0
+	default:
+		return 8.0
 	}
 }
 
@@ -212,6 +470,19 @@ type animationTickMsg time.Time
 // smoothTickMsg drives spring animations at 60fps.
 type smoothTickMsg time.Time
 
+// RefreshOptionsMsg replaces the options the wizard offers, for callers
+// that register options after it has started. Lists are rebuilt with the
+// highlighted language, framework and library kept by label.
+type RefreshOptionsMsg struct {
+	Frameworks []domain.Framework
+}
+
+// planCheckedMsg reports the outcome of checkPlans for attempt seq.
+type planCheckedMsg struct {
+	seq int
+	err error
+}
+
 func tickAnimation() tea.Cmd {
 	return tea.Tick(150*time.Millisecond, func(t time.Time) tea.Msg {
 		return animationTickMsg(t)
@@ -228,20 +499,33 @@ func tickSmooth() tea.Cmd {
 func (m *model) updateBindings() {
 	keys.Back.SetEnabled(m.stage != stageLanguage && m.stage != stageName)
 	keys.Space.SetEnabled(m.stage == stageLibraries)
+	keys.Pin.SetEnabled(m.stage == stageFramework)
+	keys.Add.SetEnabled(m.stage == stageConfirm && m.monorepo)
+	keys.Slug.SetEnabled(m.stage == stageName)
+	keys.Details.SetEnabled(m.stage == stageLibraries)
+	keys.Older.SetEnabled(m.stage == stageName && len(m.nameHistory) > 0)
+	keys.Newer.SetEnabled(m.stage == stageName && len(m.nameHistory) > 0)
+	// ? is typed into the name, not a shortcut there.
+	keys.Help.SetEnabled(m.stage != stageName)
 }
 
 func (m model) Init() tea.Cmd {
+	if m.noAnimation {
+		return m.name.Cursor.SetMode(cursor.CursorStatic)
+	}
 	return tea.Batch(tickAnimation(), tickSmooth(), m.name.Cursor.SetMode(cursor.CursorBlink))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// The first key ends the title reveal; it still reaches the stage below.
+		m.titleFrame = max(m.titleFrame, revealTotalTicks())
 		switch {
 		case key.Matches(msg, keys.Quit):
 			m.err = errors.New("cancelled")
 			return m, tea.Quit
-		case key.Matches(msg, keys.Back) && m.stage != stageName:
+		case key.Matches(msg, keys.Back) && m.stage != stageName && m.stage != stageEmpty:
 			prevStage := m.stage
 			m = m.back()
 			if m.stage != prevStage {
@@ -249,17 +533,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.updateBindings()
 			return m, tickSmooth()
+		case key.Matches(msg, keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			m.resizeLists()
+			return m, nil
 		}
+	case RefreshOptionsMsg:
+		m.refreshOptions(msg.Frameworks)
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.panelW = clamp(int(float64(m.width)*0.80), 64, m.width-4)
 		m.panelH = clamp(int(float64(m.height)*0.80), 28, m.height-4)
-		listWidth := clamp(m.panelW-8, 56, 100)
-		listHeight := m.listHeightFixed()
-		m.languages.SetSize(listWidth, listHeight)
-		m.framework.SetSize(listWidth, listHeight)
-		m.libraries.SetSize(listWidth, listHeight)
+		m.resizeLists()
 		m.name.Width = clamp(m.panelW-14, 24, 72)
 		m.help.Width = m.panelW - 6
 	}
@@ -334,6 +621,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return modelValue, tea.Batch(cmd, animCmd, smoothCmd)
 	case stageDone:
 		return m, tea.Quit
+	case stageEmpty:
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.err = ErrNoOptions
+			return m, tea.Quit
+		}
+		return m, tea.Batch(animCmd, smoothCmd)
 	default:
 		return m, tea.Batch(animCmd, smoothCmd)
 	}
@@ -350,13 +643,15 @@ func (m model) View() string {
 	case stageFramework:
 		return m.renderFrame(m.framework.View(), m.stepLabel())
 	case stageLibraries:
-		return m.renderFrame(m.libraries.View(), m.stepLabel())
+		return m.renderFrame(m.renderLibraries(), m.stepLabel())
 	case stageName:
 		return m.renderFrame(m.renderNameInput(), m.stepLabel())
 	case stageConfirm:
 		return m.renderFrame(m.renderConfirmation(), m.stepLabel())
 	case stageDone:
 		return "done\n"
+	case stageEmpty:
+		return m.renderFrame(m.renderNoOptions(), "")
 	default:
 		return ""
 	}
@@ -378,7 +673,8 @@ func (m model) updateLanguage(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 			m.result.Language = item.label
-			m.framework = buildFrameworkList(m.result.Language, m.options, m.result.Framework, m.styles)
+			m.answered[stageLanguage] = true
+			m.framework = buildFrameworkList(m.result.Language, m.options, m.frameworkInfo, m.pinned, m.frameworkDefault(item.label), m.styles)
 			m.framework.SetSize(m.languages.Width(), m.listHeightFixed())
 			m.stage = stageFramework
 			m.triggerTransition(true)
@@ -390,7 +686,38 @@ func (m model) updateLanguage(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// frameworkDefault returns the framework to preselect for language: the
+// one last created with it while still offered, or else the current one.
+func (m model) frameworkDefault(language string) string {
+	for known, framework := range m.lastFrameworks {
+		if !strings.EqualFold(known, language) {
+			continue
+		}
+		for _, offered := range m.options[language] {
+			if strings.EqualFold(offered, framework) {
+				return offered
+			}
+		}
+	}
+	return m.result.Framework
+}
+
 func (m model) updateFramework(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Pin) {
+		m.togglePin()
+		return m, nil
+	}
+
+	// Type-ahead: a letter jumps to the first framework starting with it.
+	// Letters with no match, and the j/k/g/G navigation keys, fall through
+	// to the list's own key handling.
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyRunes && len(keyMsg.Runes) == 1 && !isNavigation(keyMsg) {
+		if idx := typeAheadIndex(m.framework.Items(), keyMsg.Runes[0]); idx >= 0 {
+			m.framework.Select(idx)
+			return m, nil
+		}
+	}
+
 	var cmd tea.Cmd
 	m.framework, cmd = m.framework.Update(msg)
 
@@ -402,9 +729,12 @@ func (m model) updateFramework(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 			m.result.Framework = item.label
-			m.selectedLibs = map[string]bool{}
-			m.libraries = buildLibrariesList(m.result.Language, m.result.Framework, m.libOptions, m.selectedLibs, m.styles)
+			m.answered[stageFramework] = true
+			m.selectedLibs = m.presetLibraries()
+			m.libraries = buildLibrariesList(m.result.Language, m.result.Framework, m.libOptions, m.selectedLibs, m.frameworkInfo[optionKey(m.result.Language, m.result.Framework)].deprecatedLibraries, m.styles)
+			m.libraries.SetDelegate(m.libraryDelegate())
 			m.libraries.SetSize(m.framework.Width(), m.listHeightFixed())
+			m.libOffset = 0
 			if len(m.libraries.Items()) == 0 {
 				m.stage = stageName
 			} else {
@@ -419,6 +749,18 @@ func (m model) updateFramework(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// presetLibraries returns the default libraries the chosen framework
+// offers, checked, under the names the framework lists them by.
+func (m model) presetLibraries() map[string]bool {
+	selected := map[string]bool{}
+	for _, offered := range m.libOptions[optionKey(m.result.Language, m.result.Framework)] {
+		if slices.ContainsFunc(m.defaultLibs, func(name string) bool { return strings.EqualFold(name, offered) }) {
+			selected[offered] = true
+		}
+	}
+	return selected
+}
+
 func (m model) updateLibraries(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.libraries, cmd = m.libraries.Update(msg)
@@ -432,12 +774,16 @@ func (m model) updateLibraries(msg tea.Msg) (tea.Model, tea.Cmd) {
 				name := strings.TrimPrefix(item.label, "[x] ")
 				name = strings.TrimPrefix(name, "[ ] ")
 				m.selectedLibs[name] = !m.selectedLibs[name]
-				m.libraries.SetItems(buildLibraryItems(m.result.Language, m.result.Framework, m.libOptions, m.selectedLibs))
+				m.libraries.SetItems(buildLibraryItems(m.result.Language, m.result.Framework, m.libOptions, m.selectedLibs, m.frameworkInfo[optionKey(m.result.Language, m.result.Framework)].deprecatedLibraries))
 				if idx < len(m.libraries.Items()) {
 					m.libraries.Select(idx)
 				}
 			}
+		case key.Matches(keyMsg, keys.Details):
+			m.compactLibraries = !m.compactLibraries
+			m.libraries.SetDelegate(m.libraryDelegate())
 		case key.Matches(keyMsg, keys.Enter):
+			m.answered[stageLibraries] = true
 			m.stage = stageName
 			m.triggerTransition(true)
 			m.updateBindings()
@@ -445,10 +791,28 @@ func (m model) updateLibraries(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	m.libOffset = m.libraryOffset()
 	return m, cmd
 }
 
 func (m model) updateName(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Slug) {
+		if slug, ok := m.nameSuggestion(); ok {
+			m.name.SetValue(slug)
+			m.name.CursorEnd()
+			m.nameErr = ""
+		}
+		return m, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Older, keys.Newer) {
+		step := 1
+		if key.Matches(keyMsg, keys.Newer) {
+			step = -1
+		}
+		m.recallName(step)
+		return m, nil
+	}
+
 	var cmd tea.Cmd
 	m.name, cmd = m.name.Update(msg)
 
@@ -459,9 +823,16 @@ func (m model) updateName(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.nameErr = "Name is required"
 				return m, cmd
 			}
+			if m.nameQueued(value) {
+				m.nameErr = "A queued project already uses this name"
+				return m, cmd
+			}
 			m.nameErr = ""
 			m.result.Name = value
 			m.result.Libraries = selectedLibraries(m.selectedLibs)
+			m.answered[stageName] = true
+			m.result.Steps = m.answeredSteps()
+			m.refreshSummary()
 			m.stage = stageConfirm
 			m.triggerTransition(true)
 			m.updateBindings()
@@ -472,19 +843,225 @@ func (m model) updateName(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// recallName moves step entries through the name history, older for a
+// positive step, and shows that entry in the name input. Stepping newer
+// than the newest entry brings back what was typed.
+func (m *model) recallName(step int) {
+	pos := min(max(m.historyPos+step, -1), len(m.nameHistory)-1)
+	if pos == m.historyPos {
+		return
+	}
+	if m.historyPos == -1 {
+		m.nameDraft = m.name.Value()
+	}
+	m.historyPos = pos
+	if pos == -1 {
+		m.name.SetValue(m.nameDraft)
+	} else {
+		m.name.SetValue(m.nameHistory[pos])
+	}
+	m.name.CursorEnd()
+	m.nameErr = ""
+}
+
+// nameSuggestion returns the slug the typed name will be created as, when
+// it differs from what was typed, or the clipboard name while nothing is
+// typed.
+func (m model) nameSuggestion() (string, bool) {
+	value := strings.TrimSpace(m.name.Value())
+	if value == "" {
+		return m.clipName, m.clipName != ""
+	}
+	slug := scaffold.Slugify(value)
+	return slug, slug != value
+}
+
 func (m model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if checked, ok := msg.(planCheckedMsg); ok {
+		if !m.planning || checked.seq != m.planSeq {
+			return m, nil
+		}
+		m.planning = false
+		if checked.err != nil {
+			m.planErr = checked.err
+			return m, nil
+		}
+		m.stage = stageDone
+		return m, tea.Quit
+	}
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		if key.Matches(keyMsg, keys.Enter) {
-			m.stage = stageDone
-			return m, tea.Quit
+		if m.planning {
+			return m, nil
+		}
+		switch {
+		case key.Matches(keyMsg, keys.Enter):
+			m.result.Projects = append(m.queued, m.currentProject())
+			if m.preview == nil {
+				m.stage = stageDone
+				return m, tea.Quit
+			}
+			m.planning = true
+			m.planErr = nil
+			m.planSeq++
+			return m, checkPlans(m.preview, m.planSeq, m.result.Projects)
+		case m.monorepo && key.Matches(keyMsg, keys.Add):
+			m.queueProject()
+			m.triggerTransition(true)
+			m.updateBindings()
+			return m, tickSmooth()
 		}
 	}
 	return m, nil
 }
 
+// refreshSummary re-plans the current selection for the confirm screen. It
+// runs on every entry to confirm so going back to change libraries is
+// reflected; a failed preview simply hides the summary.
+func (m *model) refreshSummary() {
+	m.summary = nil
+	if m.preview == nil {
+		return
+	}
+	plan, err := m.preview(m.result)
+	if err != nil {
+		return
+	}
+	summary := summarizePlan(plan, 3)
+	m.summary = &summary
+}
+
+// refreshOptions re-indexes frameworks and rebuilds the lists built so far
+// from it, keeping each list's highlighted entry. Selected libraries the
+// framework no longer offers are dropped.
+func (m *model) refreshOptions(frameworks []domain.Framework) {
+	m.options, m.libOptions, m.frameworkInfo = indexOptions(frameworks)
+
+	language := selectedLabel(m.languages)
+	m.languages.SetItems(languageItems(m.options, m.frameworkInfo))
+	selectListItem(&m.languages, language)
+
+	if m.stage != stageLanguage && m.stage != stageEmpty {
+		framework := cmp.Or(selectedLabel(m.framework), m.result.Framework)
+		width, height := m.framework.Width(), m.framework.Height()
+		m.framework = buildFrameworkList(m.result.Language, m.options, m.frameworkInfo, m.pinned, framework, m.styles)
+		m.framework.SetSize(width, height)
+	}
+
+	if len(m.libraries.Items()) > 0 {
+		key := optionKey(m.result.Language, m.result.Framework)
+		for name := range m.selectedLibs {
+			if !slices.Contains(m.libOptions[key], name) {
+				delete(m.selectedLibs, name)
+			}
+		}
+		library := strings.TrimPrefix(strings.TrimPrefix(selectedLabel(m.libraries), "[x] "), "[ ] ")
+		m.libraries.SetItems(buildLibraryItems(m.result.Language, m.result.Framework, m.libOptions, m.selectedLibs, m.frameworkInfo[key].deprecatedLibraries))
+		for i, item := range m.libraries.Items() {
+			if label := item.(listItem).label; strings.HasSuffix(label, "] "+library) {
+				m.libraries.Select(i)
+				break
+			}
+		}
+		m.libOffset = m.libraryOffset()
+	}
+
+	if m.stage == stageEmpty && len(m.languages.Items()) > 0 {
+		m.stage = stageLanguage
+	}
+	m.updateBindings()
+}
+
+// selectedLabel returns the label of the highlighted item, or "" when the
+// list is empty.
+func selectedLabel(l list.Model) string {
+	if item, ok := l.SelectedItem().(listItem); ok {
+		return item.label
+	}
+	return ""
+}
+
+// checkPlans plans every project off the update loop and reports the first
+// failure, so a plan error keeps the wizard open for a retry instead of
+// ending the program.
+func checkPlans(preview func(Result) (domain.Plan, error), seq int, projects []Project) tea.Cmd {
+	return func() tea.Msg {
+		for _, project := range projects {
+			_, err := preview(Result{
+				Language:  project.Language,
+				Framework: project.Framework,
+				Name:      project.Name,
+				Libraries: project.Libraries,
+			})
+			if err != nil {
+				if len(projects) > 1 {
+					err = fmt.Errorf("%s: %w", project.Name, err)
+				}
+				return planCheckedMsg{seq: seq, err: err}
+			}
+		}
+		return planCheckedMsg{seq: seq}
+	}
+}
+
+func (m model) currentProject() Project {
+	return Project{
+		Language:  m.result.Language,
+		Framework: m.result.Framework,
+		Name:      m.result.Name,
+		Libraries: m.result.Libraries,
+	}
+}
+
+// queueProject stores the confirmed selection and restarts the wizard at the
+// language stage for the next project of a monorepo.
+func (m *model) queueProject() {
+	m.queued = append(m.queued, m.currentProject())
+	m.planErr = nil
+	m.result.Name = ""
+	m.result.Libraries = nil
+	m.selectedLibs = map[string]bool{}
+	m.answered = map[stage]bool{}
+	m.libraries.SetItems(nil)
+	m.libOffset = 0
+	m.name.SetValue("")
+	m.stage = stageLanguage
+}
+
+func (m model) nameQueued(name string) bool {
+	for _, project := range m.queued {
+		if strings.EqualFold(project.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// togglePin pins or unpins the highlighted framework and rebuilds the list so
+// pinned entries sort first, keeping the cursor on the same framework.
+func (m *model) togglePin() {
+	item, ok := m.framework.SelectedItem().(listItem)
+	if !ok {
+		return
+	}
+	pin := pinKey(m.result.Language, item.label)
+	if m.pinned[pin] {
+		delete(m.pinned, pin)
+	} else {
+		m.pinned[pin] = true
+	}
+	m.result.Pinned = sortedPins(m.pinned)
+
+	width, height := m.framework.Width(), m.framework.Height()
+	m.framework = buildFrameworkList(m.result.Language, m.options, m.frameworkInfo, m.pinned, item.label, m.styles)
+	m.framework.SetSize(width, height)
+}
+
 // triggerTransition sets up a horizontal slide animation.
 // forward=true slides content in from the right; false from the left.
 func (m *model) triggerTransition(forward bool) {
+	if m.noAnimation {
+		return
+	}
 	contentWidth := 82 // default panelW(88) - 6
 	if m.panelW > 0 {
 		contentWidth = m.panelW - 6
@@ -519,6 +1096,8 @@ func (m model) back() model {
 		}
 	case stageConfirm:
 		m.stage = stageName
+		m.planning = false
+		m.planErr = nil
 	}
 
 	return m
@@ -1,10 +1,21 @@
 package ui
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"project-initiator/internal/domain"
+	"project-initiator/internal/scaffold"
+	"project-initiator/internal/strutil"
 )
 
 func TestFrameworkDescription(t *testing.T) {
@@ -37,142 +48,6 @@ func TestFrameworkDescription(t *testing.T) {
 	}
 }
 
-func TestUniqueStrings(t *testing.T) {
-	tests := []struct {
-		name  string
-		input []string
-		want  []string
-	}{
-		{
-			name:  "no duplicates",
-			input: []string{"a", "b", "c"},
-			want:  []string{"a", "b", "c"},
-		},
-		{
-			name:  "exact duplicates",
-			input: []string{"a", "b", "a", "c", "b"},
-			want:  []string{"a", "b", "c"},
-		},
-		{
-			name:  "case insensitive dedup keeps first occurrence",
-			input: []string{"Go", "go", "GO"},
-			want:  []string{"Go"},
-		},
-		{
-			name:  "empty list",
-			input: []string{},
-			want:  []string{},
-		},
-		{
-			name:  "nil list",
-			input: nil,
-			want:  []string{},
-		},
-		{
-			name:  "whitespace only entries are removed",
-			input: []string{"a", "  ", "b", "\t", "c"},
-			want:  []string{"a", "b", "c"},
-		},
-		{
-			name:  "entries with leading trailing whitespace dedup on trimmed key",
-			input: []string{"  Go ", "Go"},
-			want:  []string{"  Go "},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := uniqueStrings(tt.input)
-			if len(got) != len(tt.want) {
-				t.Fatalf("uniqueStrings(%v) returned %d elements, want %d: got %v", tt.input, len(got), len(tt.want), got)
-			}
-			for i := range got {
-				if got[i] != tt.want[i] {
-					t.Errorf("uniqueStrings(%v)[%d] = %q, want %q", tt.input, i, got[i], tt.want[i])
-				}
-			}
-		})
-	}
-}
-
-func TestSortStrings(t *testing.T) {
-	tests := []struct {
-		name  string
-		input []string
-		want  []string
-	}{
-		{
-			name:  "already sorted",
-			input: []string{"alpha", "beta", "gamma"},
-			want:  []string{"alpha", "beta", "gamma"},
-		},
-		{
-			name:  "reverse order",
-			input: []string{"gamma", "beta", "alpha"},
-			want:  []string{"alpha", "beta", "gamma"},
-		},
-		{
-			name:  "case insensitive sort",
-			input: []string{"Banana", "apple", "Cherry"},
-			want:  []string{"apple", "Banana", "Cherry"},
-		},
-		{
-			name:  "single element",
-			input: []string{"only"},
-			want:  []string{"only"},
-		},
-		{
-			name:  "empty",
-			input: []string{},
-			want:  []string{},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// copy to avoid mutating test data
-			values := make([]string, len(tt.input))
-			copy(values, tt.input)
-
-			sortStrings(values)
-
-			if len(values) != len(tt.want) {
-				t.Fatalf("sortStrings(%v) produced %d elements, want %d", tt.input, len(values), len(tt.want))
-			}
-			for i := range values {
-				if values[i] != tt.want[i] {
-					t.Errorf("sortStrings(%v)[%d] = %q, want %q", tt.input, i, values[i], tt.want[i])
-				}
-			}
-		})
-	}
-}
-
-func TestContains(t *testing.T) {
-	tests := []struct {
-		name   string
-		values []string
-		target string
-		want   bool
-	}{
-		{"found exact", []string{"Go", "Python", "Rust"}, "Python", true},
-		{"not found", []string{"Go", "Python", "Rust"}, "Java", false},
-		{"case insensitive match", []string{"Go", "Python"}, "go", true},
-		{"case insensitive match reverse", []string{"go"}, "Go", true},
-		{"empty list", []string{}, "Go", false},
-		{"nil list", nil, "Go", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := contains(tt.values, tt.target)
-			if got != tt.want {
-				t.Errorf("contains(%v, %q) = %v, want %v", tt.values, tt.target, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestSelectedLibraries(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -315,6 +190,44 @@ func TestStageProgress(t *testing.T) {
 	}
 }
 
+func TestStepLabelMatchesProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		stage     stage
+		hasLibs   bool
+		wantLabel string
+		wantProg  float64
+	}{
+		{"language no libs", stageLanguage, false, "Step 1/3", 0.0},
+		{"framework no libs", stageFramework, false, "Step 2/3", 1.0 / 3.0},
+		{"name no libs", stageName, false, "Step 3/3", 2.0 / 3.0},
+		{"language with libs", stageLanguage, true, "Step 1/4", 0.0},
+		{"framework with libs", stageFramework, true, "Step 2/4", 1.0 / 4.0},
+		{"libraries", stageLibraries, true, "Step 3/4", 2.0 / 4.0},
+		{"name with libs", stageName, true, "Step 4/4", 3.0 / 4.0},
+		{"confirm", stageConfirm, true, "Review", 1.0},
+		{"libraries skipped", stageLibraries, false, "", 0.0},
+		{"done", stageDone, false, "", 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{stage: tt.stage}
+			if tt.hasLibs {
+				m.libraries = newCleanList([]list.Item{listItem{label: "test", description: "d"}}, listDelegate{}, 0, 0)
+			} else {
+				m.libraries = newCleanList([]list.Item{}, listDelegate{}, 0, 0)
+			}
+			if got := m.stepLabel(); got != tt.wantLabel {
+				t.Errorf("stepLabel() = %q, want %q", got, tt.wantLabel)
+			}
+			if got := m.stageProgress(); got != tt.wantProg {
+				t.Errorf("stageProgress() = %f, want %f", got, tt.wantProg)
+			}
+		})
+	}
+}
+
 func TestTriggerTransition(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -348,6 +261,33 @@ func TestTriggerTransition(t *testing.T) {
 	}
 }
 
+func TestTransitionsOff_StageChangeDoesNotAnimate(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Go", Transitions: "off"})
+	if !m.panelReady {
+		t.Error("panel entrance should be skipped with transitions off")
+	}
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage != stageFramework {
+		t.Fatalf("stage = %d, want stageFramework", m.stage)
+	}
+	if m.transActive || m.transOffset != 0 {
+		t.Errorf("transActive = %v, transOffset = %f; want no transition", m.transActive, m.transOffset)
+	}
+}
+
+func TestTransitionFrequency(t *testing.T) {
+	if !(transitionFrequency("slow") < transitionFrequency("") && transitionFrequency("") < transitionFrequency("fast")) {
+		t.Errorf("frequencies should increase slow < normal < fast: %v, %v, %v",
+			transitionFrequency("slow"), transitionFrequency(""), transitionFrequency("fast"))
+	}
+	if transitionFrequency("normal") != transitionFrequency("") {
+		t.Error("normal should match the default speed")
+	}
+}
+
 func TestAbsF(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -561,6 +501,140 @@ func TestRenderAnimatedTitle_FullRevealContainsArtChars(t *testing.T) {
 	}
 }
 
+func TestAccessible_TitleHasNoSpark(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, Accessible: true})
+	if !m.noAnimation || !m.panelReady || !m.animationDone {
+		t.Errorf("accessible mode should start with animation off: noAnimation=%v panelReady=%v animationDone=%v",
+			m.noAnimation, m.panelReady, m.animationDone)
+	}
+	if !m.styles.highContrast {
+		t.Error("accessible mode should use the high-contrast styles")
+	}
+
+	title := m.renderAnimatedTitle(60)
+	for _, r := range "═╾╼█" {
+		if containsRune(title, r) {
+			t.Errorf("accessible title contains %q:\n%s", r, title)
+		}
+	}
+	if !strings.Contains(title, plainBanner) {
+		t.Errorf("accessible title should show the plain banner:\n%s", title)
+	}
+}
+
+func TestTransitionsOff_TitleIsStatic(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, Transitions: "off"})
+	first := m.renderAnimatedTitle(60)
+	if !containsRune(first, '█') {
+		t.Errorf("title should be fully revealed from the first frame:\n%s", first)
+	}
+
+	m.titleFrame = 7
+	if got := m.renderAnimatedTitle(60); got != first {
+		t.Errorf("title changed between frames with animation off:\n%s\n---\n%s", first, got)
+	}
+	if got, want := renderStaticBorder(10, m.animCache), m.animCache.dim.Render("╾════════╼"); got != want {
+		t.Errorf("renderStaticBorder(10) = %q, want %q", got, want)
+	}
+}
+
+func TestUpdate_IdleSchedulesNoTicks(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(model)
+
+	// Run the title animation and the entrance spring to the end, as the
+	// program would.
+	var cmd tea.Cmd
+	for range 10000 {
+		if m.animationDone {
+			break
+		}
+		updated, cmd = m.Update(animationTickMsg{})
+		m = updated.(model)
+	}
+	if !m.animationDone {
+		t.Fatal("title animation never finished")
+	}
+	if cmd != nil {
+		t.Error("the last animation tick scheduled another")
+	}
+	for range 10000 {
+		if m.panelReady {
+			break
+		}
+		updated, _ = m.Update(smoothTickMsg{})
+		m = updated.(model)
+	}
+	if !m.panelReady {
+		t.Fatal("panel entrance never settled")
+	}
+
+	// A stray tick once everything has settled schedules nothing more.
+	for _, msg := range []tea.Msg{animationTickMsg{}, smoothTickMsg{}} {
+		if _, cmd := m.Update(msg); cmd != nil {
+			t.Errorf("Update(%T) when idle returned a command, want none", msg)
+		}
+	}
+
+	m = newWizard(Options{Frameworks: scaffold.Frameworks, Transitions: "off"})
+	if cmd := m.Init(); cmd != nil {
+		switch msg := cmd().(type) {
+		case animationTickMsg, smoothTickMsg:
+			t.Errorf("Init() with animation off scheduled %T", msg)
+		}
+	}
+}
+
+func TestUpdate_KeySkipsTitleReveal(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	updated, _ := m.Update(animationTickMsg{})
+	m = updated.(model)
+	if m.titleFrame >= revealTotalTicks() {
+		t.Fatalf("titleFrame = %d before any key, want the reveal in progress", m.titleFrame)
+	}
+	before := m.languages.Index()
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(model)
+
+	if m.titleFrame < revealTotalTicks() {
+		t.Errorf("titleFrame = %d after a key, want at least %d", m.titleFrame, revealTotalTicks())
+	}
+	if got := m.languages.Index(); got != before+1 {
+		t.Errorf("language index = %d after down, want %d", got, before+1)
+	}
+}
+
+func TestSkipSplash_StartsRevealed(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, SkipSplash: true})
+	if m.titleFrame < revealTotalTicks() {
+		t.Errorf("titleFrame = %d, want at least %d", m.titleFrame, revealTotalTicks())
+	}
+	if m.animationDone {
+		t.Error("animationDone = true, want the border spark still to play")
+	}
+}
+
+func TestEnvEnabled(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"0", false},
+		{"false", false},
+		{"FALSE", false},
+		{"1", true},
+		{"yes", true},
+	}
+	for _, tt := range tests {
+		if got := envEnabled(tt.value); got != tt.want {
+			t.Errorf("envEnabled(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
 func containsRune(s string, target rune) bool {
 	for _, r := range s {
 		if r == target {
@@ -600,3 +674,1464 @@ func TestClamp(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Option filtering
+// ---------------------------------------------------------------------------
+
+func TestNewWizard_DoesNotFabricateVanilla(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Cobra"},
+		{Language: "PHP", Name: "Laravel", Generator: "composer-laravel"},
+	}
+	m := newWizard(Options{Frameworks: options, DefaultLanguage: "PHP"})
+
+	m.framework = buildFrameworkList("PHP", m.options, m.frameworkInfo, m.pinned, m.result.Framework, m.styles)
+	labels := itemLabels(m.framework.Items())
+	if !slices.Equal(labels, []string{"Laravel"}) {
+		t.Errorf("framework list = %v, want [Laravel]", labels)
+	}
+}
+
+func TestNewWizard_AskFramework(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		wantStage stage
+	}{
+		{name: "known language opens framework stage", language: "Go", wantStage: stageFramework},
+		{name: "no language starts at language stage", language: "", wantStage: stageLanguage},
+		{name: "unknown language starts at language stage", language: "Cobol", wantStage: stageLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: tt.language, AskFramework: true})
+			if m.stage != tt.wantStage {
+				t.Errorf("stage = %v, want %v", m.stage, tt.wantStage)
+			}
+			if m.result.Framework != "" {
+				t.Errorf("framework = %q, want none preselected", m.result.Framework)
+			}
+		})
+	}
+
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Go", AskFramework: true})
+	if got := itemLabels(m.framework.Items()); !slices.Equal(got, []string{"Cobra", "Vanilla"}) {
+		t.Errorf("framework list = %v, want [Cobra Vanilla]", got)
+	}
+	if m.framework.Index() != 0 {
+		t.Errorf("cursor = %d, want the first framework", m.framework.Index())
+	}
+}
+
+func TestNewWizard_HidesLanguagesWithoutFrameworks(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Cobra"},
+		{Language: "Ruby", Name: ""},
+	}
+	m := newWizard(Options{Frameworks: options})
+
+	labels := itemLabels(m.languages.Items())
+	if !slices.Equal(labels, []string{"Go"}) {
+		t.Errorf("language list = %v, want [Go]", labels)
+	}
+	if m.stage != stageLanguage {
+		t.Errorf("stage = %d, want stageLanguage", m.stage)
+	}
+}
+
+func TestNewWizard_EmptyOptionsShowsErrorScreen(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []domain.Framework
+	}{
+		{name: "nil options", options: nil},
+		{name: "only blank entries", options: []domain.Framework{{Language: "Go"}, {Name: "Vanilla"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: tt.options, DefaultLanguage: "Go", DefaultFramework: "Vanilla"})
+			if m.stage != stageEmpty {
+				t.Fatalf("stage = %d, want stageEmpty", m.stage)
+			}
+
+			m.panelReady = true
+			view := m.View()
+			if !strings.Contains(view, "No templates available") {
+				t.Errorf("view should explain that no templates are available:\n%s", view)
+			}
+
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			if cmd == nil {
+				t.Error("expected a quit command after a key press")
+			}
+			if _, err := ResultFromModel(updated); !errors.Is(err, ErrNoOptions) {
+				t.Errorf("ResultFromModel() error = %v, want ErrNoOptions", err)
+			}
+		})
+	}
+}
+
+func itemLabels(items []list.Item) []string {
+	labels := make([]string, 0, len(items))
+	for _, item := range items {
+		if li, ok := item.(listItem); ok {
+			labels = append(labels, li.label)
+		}
+	}
+	return labels
+}
+
+func TestNewWizard_HidesDisabledOptions(t *testing.T) {
+	disabled := scaffold.Disabled{Languages: []string{"PHP"}, Frameworks: []string{"Go/Cobra"}}
+	m := newWizard(Options{Frameworks: scaffold.ListOptions(scaffold.Frameworks, disabled)})
+
+	if strutil.ContainsFold(itemLabels(m.languages.Items()), "PHP") {
+		t.Error("disabled language PHP should not be listed")
+	}
+
+	m.framework = buildFrameworkList("Go", m.options, m.frameworkInfo, m.pinned, "", m.styles)
+	frameworks := itemLabels(m.framework.Items())
+	if strutil.ContainsFold(frameworks, "Cobra") {
+		t.Errorf("disabled framework Cobra should not be listed: %v", frameworks)
+	}
+	if !strutil.ContainsFold(frameworks, "Vanilla") {
+		t.Errorf("Vanilla should still be listed: %v", frameworks)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Framework type-ahead
+// ---------------------------------------------------------------------------
+
+func TestUpdateFramework_TypeAhead(t *testing.T) {
+	tests := []struct {
+		name    string
+		letter  rune
+		initial string
+		want    string
+	}{
+		{name: "c selects Cobra", letter: 'c', initial: "Vanilla", want: "Cobra"},
+		{name: "uppercase letter matches", letter: 'V', initial: "Cobra", want: "Vanilla"},
+		{name: "letter without match keeps selection", letter: 'z', initial: "Vanilla", want: "Vanilla"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Go", DefaultFramework: tt.initial})
+			m.stage = stageFramework
+			m.framework = buildFrameworkList("Go", m.options, m.frameworkInfo, m.pinned, tt.initial, m.styles)
+
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.letter}})
+			got, ok := updated.(model).framework.SelectedItem().(listItem)
+			if !ok {
+				t.Fatal("no framework selected")
+			}
+			if got.label != tt.want {
+				t.Errorf("selected %q, want %q", got.label, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Vim navigation
+// ---------------------------------------------------------------------------
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestUpdateLanguage_VimKeys(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	if m.stage != stageLanguage {
+		t.Fatalf("stage = %d, want stageLanguage", m.stage)
+	}
+	last := len(m.languages.Items()) - 1
+
+	steps := []struct {
+		key  rune
+		want int
+	}{
+		{key: 'j', want: 1},
+		{key: 'j', want: 2},
+		{key: 'k', want: 1},
+		{key: 'G', want: last},
+		{key: 'g', want: 0},
+		{key: 'b', want: 0},
+	}
+	for _, step := range steps {
+		updated, _ := m.Update(runeKey(step.key))
+		m = updated.(model)
+		if got := m.languages.Index(); got != step.want {
+			t.Fatalf("after %q index = %d, want %d", step.key, got, step.want)
+		}
+	}
+	if m.stage != stageLanguage {
+		t.Errorf("stage = %d after navigating, want stageLanguage", m.stage)
+	}
+}
+
+func TestUpdateFramework_VimKeysBeatTypeAhead(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Chi"},
+		{Language: "Go", Name: "Gin"},
+		{Language: "Go", Name: "Jet"},
+		{Language: "Go", Name: "Kit"},
+	}
+	m := newWizard(Options{Frameworks: options, DefaultLanguage: "Go", DefaultFramework: "Chi"})
+	m.stage = stageFramework
+	m.framework = buildFrameworkList("Go", m.options, m.frameworkInfo, m.pinned, "Chi", m.styles)
+	m.updateBindings()
+
+	steps := []struct {
+		key  rune
+		want string
+	}{
+		{key: 'j', want: "Gin"},
+		{key: 'G', want: "Kit"},
+		{key: 'k', want: "Jet"},
+		{key: 'g', want: "Chi"},
+		// Other letters still jump.
+		{key: 'J', want: "Jet"},
+	}
+	for _, step := range steps {
+		updated, _ := m.Update(runeKey(step.key))
+		m = updated.(model)
+		got, ok := m.framework.SelectedItem().(listItem)
+		if !ok {
+			t.Fatal("no framework selected")
+		}
+		if got.label != step.want {
+			t.Fatalf("after %q selected %q, want %q", step.key, got.label, step.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Framework row chips
+// ---------------------------------------------------------------------------
+
+func TestFrameworkRowDescription(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework string
+		info      frameworkInfo
+		want      string
+	}{
+		{"no extras", "Vanilla", frameworkInfo{}, "minimal starter"},
+		{"one library", "Vanilla", frameworkInfo{libraries: 1}, "minimal starter · 1 library"},
+		{"several libraries", "Cobra", frameworkInfo{libraries: 3}, "CLI app structure · 3 libraries"},
+		{"generator tool", "Laravel", frameworkInfo{requires: "composer"}, "PHP web framework · requires composer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := frameworkRowDescription("Go", tt.framework, tt.info)
+			if got != tt.want {
+				t.Errorf("frameworkRowDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameworkList_RecommendedBadge(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	l := buildFrameworkList("Go", m.options, m.frameworkInfo, m.pinned, "", m.styles)
+	l.SetSize(80, 20)
+
+	for i, item := range l.Items() {
+		row := item.(listItem)
+		wantBadge := row.label == "Cobra"
+		if row.recommended != wantBadge {
+			t.Errorf("%s recommended = %v, want %v", row.label, row.recommended, wantBadge)
+		}
+
+		var buf strings.Builder
+		listDelegate{styles: m.styles}.Render(&buf, l, i, item)
+		if got := strings.Contains(buf.String(), recommendedBadge); got != wantBadge {
+			t.Errorf("%s row shows badge = %v, want %v:\n%s", row.label, got, wantBadge, buf.String())
+		}
+	}
+}
+
+func TestLanguageDescription(t *testing.T) {
+	tests := []struct {
+		name       string
+		frameworks []string
+		info       map[string]frameworkInfo
+		want       string
+	}{
+		{
+			name:       "templates only",
+			frameworks: []string{"Express", "Hono", "NestJS"},
+			want:       "3 templates",
+		},
+		{
+			name:       "templates and generator",
+			frameworks: []string{"Vanilla", "Laravel"},
+			info:       map[string]frameworkInfo{optionKey("PHP", "Laravel"): {generator: true}},
+			want:       "1 template · 1 generator",
+		},
+		{
+			name:       "single generator is named",
+			frameworks: []string{"Laravel"},
+			info:       map[string]frameworkInfo{optionKey("PHP", "Laravel"): {generator: true}},
+			want:       "Laravel (generator)",
+		},
+		{
+			name:       "single template is named",
+			frameworks: []string{"FastAPI"},
+			want:       "FastAPI (template)",
+		},
+		{
+			name:       "duplicates counted once",
+			frameworks: []string{"Vanilla", "Vanilla", "Laravel"},
+			info:       map[string]frameworkInfo{optionKey("PHP", "Laravel"): {generator: true}},
+			want:       "1 template · 1 generator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := languageDescription("PHP", tt.frameworks, tt.info); got != tt.want {
+				t.Errorf("languageDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWizard_LanguageDescriptionsFromOptions(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	got := map[string]string{}
+	for _, item := range m.languages.Items() {
+		li := item.(listItem)
+		got[li.label] = li.description
+	}
+
+	want := map[string]string{
+		"Go":         "2 templates",
+		"JavaScript": "Vanilla (template)",
+		"PHP":        "1 template · 1 generator",
+	}
+	for lang, desc := range want {
+		if got[lang] != desc {
+			t.Errorf("%s description = %q, want %q", lang, got[lang], desc)
+		}
+	}
+}
+
+func TestFrameworkStage_ShowsOptionDescription(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Rust", Name: "Axum", Description: "ergonomic async web server"},
+		{Language: "Rust", Name: "Bare"},
+	}
+	m := newWizard(Options{Frameworks: options, DefaultLanguage: "Rust", DefaultFramework: "Axum"})
+	l := buildFrameworkList("Rust", m.options, m.frameworkInfo, m.pinned, "Axum", m.styles)
+
+	got := map[string]string{}
+	for _, item := range l.Items() {
+		li := item.(listItem)
+		got[li.label] = li.description
+	}
+	if got["Axum"] != "ergonomic async web server" {
+		t.Errorf("Axum description = %q, want the option's own description", got["Axum"])
+	}
+	if got["Bare"] != "Rust template" {
+		t.Errorf("Bare description = %q, want legacy fallback", got["Bare"])
+	}
+}
+
+func TestNewWizard_HidesIncompatibleLibraries(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Gin", Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}}},
+		{Language: "Go", Name: "Vanilla", Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}}},
+	}
+	m := newWizard(Options{Frameworks: options})
+
+	tests := []struct {
+		framework string
+		want      []string
+	}{
+		{framework: "Gin", want: []string{"[ ] Gorm"}},
+		{framework: "Vanilla", want: []string{"[ ] Gin", "[ ] Gorm"}},
+	}
+	for _, tt := range tests {
+		items := buildLibraryItems("Go", tt.framework, m.libOptions, m.selectedLibs, nil)
+		if got := itemLabels(items); !slices.Equal(got, tt.want) {
+			t.Errorf("%s libraries = %v, want %v", tt.framework, got, tt.want)
+		}
+	}
+	if got := m.frameworkInfo[optionKey("Go", "Gin")].libraries; got != 1 {
+		t.Errorf("Gin library count = %d, want 1", got)
+	}
+}
+
+func TestUpdateFramework_PresetsDefaultLibraries(t *testing.T) {
+	frameworks := []domain.Framework{{
+		Language:  "Go",
+		Name:      "Vanilla",
+		Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}, {Name: "Sqlc"}},
+	}}
+	m := newWizard(Options{Frameworks: frameworks, DefaultLibraries: []string{"gin", "Sqlc", "Nope"}})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	m.name.SetValue("api")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	if want := []string{"Gin", "Sqlc"}; !slices.Equal(m.result.Libraries, want) {
+		t.Errorf("libraries = %v, want %v", m.result.Libraries, want)
+	}
+}
+
+func TestUpdateLibraries_ToggleKeepsScrollOffset(t *testing.T) {
+	var libs []domain.Library
+	for i := range 30 {
+		libs = append(libs, domain.Library{Name: fmt.Sprintf("Lib%02d", i)})
+	}
+	m := newWizard(Options{Frameworks: []domain.Framework{{Language: "Go", Name: "Vanilla", Libraries: libs}}})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage != stageLibraries {
+		t.Fatalf("stage = %v, want libraries", m.stage)
+	}
+
+	for range 20 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(model)
+	}
+	// Step back up a little so the cursor is mid-window, not on a page boundary.
+	for range 3 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+		m = updated.(model)
+	}
+	offset, index := m.libOffset, m.libraries.Index()
+	if offset == 0 {
+		t.Fatalf("offset = 0 at index %d, want the list scrolled", index)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(model)
+
+	if m.libOffset != offset || m.libraries.Index() != index {
+		t.Errorf("after toggle offset, index = %d, %d; want %d, %d", m.libOffset, m.libraries.Index(), offset, index)
+	}
+	if !m.selectedLibs["Lib17"] {
+		t.Errorf("Lib17 not selected: %v", m.selectedLibs)
+	}
+	view := m.renderLibraries()
+	if !strings.Contains(view, "[x] Lib17") {
+		t.Errorf("toggled row not in view:\n%s", view)
+	}
+	if want := fmt.Sprintf("%d–%d of 30", offset+1, offset+m.libraryRows()); !strings.Contains(view, want) {
+		t.Errorf("view missing position %q:\n%s", want, view)
+	}
+}
+
+func TestUpdateLibraries_CollapseDescriptions(t *testing.T) {
+	var libs []domain.Library
+	for i := range 30 {
+		libs = append(libs, domain.Library{Name: fmt.Sprintf("Lib%02d", i)})
+	}
+	m := newWizard(Options{Frameworks: []domain.Framework{{Language: "Go", Name: "Vanilla", Libraries: libs}}})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.libraryDelegate().Height() != 2 {
+		t.Fatalf("delegate height = %d before collapsing, want 2", m.libraryDelegate().Height())
+	}
+	rows := m.libraryRows()
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(model)
+	if got := m.libraryDelegate().Height(); got != 1 {
+		t.Errorf("collapsed delegate height = %d, want 1", got)
+	}
+	if got := m.libraryRows(); got < 2*rows {
+		t.Errorf("collapsed rows = %d, want at least %d", got, 2*rows)
+	}
+	if view := m.renderLibraries(); strings.Contains(view, "optional package") {
+		t.Errorf("collapsed view still shows descriptions:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(model)
+	if view := m.renderLibraries(); !strings.Contains(view, "optional package") {
+		t.Errorf("expanded view missing descriptions:\n%s", view)
+	}
+}
+
+func TestFrameworkStage_ShowsLibraryCounts(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Go", DefaultFramework: "Vanilla"})
+	m.panelReady = true
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage != stageFramework {
+		t.Fatalf("stage = %d, want stageFramework", m.stage)
+	}
+	m.transActive = false
+
+	view := m.View()
+	if !strings.Contains(view, "CLI app structure · 8 libraries") {
+		t.Errorf("framework view should show the library count chip:\n%s", view)
+	}
+}
+
+func TestFrameworkStage_ShowsGeneratorRequirement(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "PHP", DefaultFramework: "Laravel"})
+	m.framework = buildFrameworkList("PHP", m.options, m.frameworkInfo, m.pinned, "Laravel", m.styles)
+
+	item, ok := m.framework.SelectedItem().(listItem)
+	if !ok {
+		t.Fatal("no framework selected")
+	}
+	if !strings.HasSuffix(item.description, "requires composer") {
+		t.Errorf("Laravel description = %q, want requires composer suffix", item.description)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Pinned frameworks
+// ---------------------------------------------------------------------------
+
+func TestBuildFrameworkList_PinOrder(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Node.js", Name: "Express"},
+		{Language: "Node.js", Name: "Hono"},
+		{Language: "Node.js", Name: "NestJS"},
+	}
+
+	tests := []struct {
+		name   string
+		pinned []string
+		want   []string
+	}{
+		{name: "no pins is alphabetical", pinned: nil, want: []string{"Express", "Hono", "NestJS"}},
+		{name: "pinned sorts first", pinned: []string{"Node.js/NestJS"}, want: []string{"NestJS", "Express", "Hono"}},
+		{name: "pins stay alphabetical", pinned: []string{"Node.js/NestJS", "Node.js/Hono"}, want: []string{"Hono", "NestJS", "Express"}},
+		{name: "pins match case-insensitively", pinned: []string{"node.js/hono"}, want: []string{"Hono", "Express", "NestJS"}},
+		{name: "other language pins ignored", pinned: []string{"Go/Hono"}, want: []string{"Express", "Hono", "NestJS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: options, Pinned: tt.pinned})
+			l := buildFrameworkList("Node.js", m.options, m.frameworkInfo, m.pinned, "", m.styles)
+			if got := itemLabels(l.Items()); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateFramework_TogglePin(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Node.js", DefaultFramework: "NestJS"})
+	m.stage = stageFramework
+	m.updateBindings()
+	m.framework = buildFrameworkList("Node.js", m.options, m.frameworkInfo, m.pinned, "NestJS", m.styles)
+	pin := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+
+	updated, _ := m.Update(pin)
+	m = updated.(model)
+	if got, want := itemLabels(m.framework.Items()), []string{"NestJS", "Express", "Hono"}; !slices.Equal(got, want) {
+		t.Errorf("after pin order = %v, want %v", got, want)
+	}
+	if item, _ := m.framework.SelectedItem().(listItem); item.label != "NestJS" || !item.pinned {
+		t.Errorf("selected = %+v, want pinned NestJS", item)
+	}
+	if !slices.Equal(m.result.Pinned, []string{"Node.js/NestJS"}) {
+		t.Errorf("result pins = %v", m.result.Pinned)
+	}
+
+	updated, _ = m.Update(pin)
+	m = updated.(model)
+	if got, want := itemLabels(m.framework.Items()), []string{"Express", "Hono", "NestJS"}; !slices.Equal(got, want) {
+		t.Errorf("after unpin order = %v, want %v", got, want)
+	}
+	if len(m.result.Pinned) != 0 {
+		t.Errorf("result pins = %v, want none", m.result.Pinned)
+	}
+}
+
+func TestFrameworkStage_ShowsPinMarker(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, DefaultLanguage: "Go", DefaultFramework: "Cobra", Pinned: []string{"Go/Cobra"}})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	m.transActive = false
+
+	if view := m.framework.View(); !strings.Contains(view, "★ Cobra") {
+		t.Errorf("framework view missing pin marker:\n%s", view)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Framework memory
+// ---------------------------------------------------------------------------
+
+func TestUpdateLanguage_PreselectsLastFrameworkByLanguage(t *testing.T) {
+	lastFrameworks := map[string]string{"Python": "FastAPI", "node.js": "Hono", "PHP": "Symfony"}
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "Python", want: "FastAPI"},
+		{language: "Node.js", want: "Hono"},
+		{language: "Go", want: "Cobra"},    // no memory: the default
+		{language: "PHP", want: "Laravel"}, // remembered one no longer offered
+		{language: "Bun", want: "Bun"},     // no memory, default not offered: first row
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			m := newWizard(Options{
+				Frameworks:       scaffold.Frameworks,
+				DefaultLanguage:  "Go",
+				DefaultFramework: "Cobra",
+				LastFrameworks:   lastFrameworks,
+			})
+			selectListItem(&m.languages, tt.language)
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			m = updated.(model)
+
+			if m.stage != stageFramework {
+				t.Fatalf("stage = %v, want framework", m.stage)
+			}
+			if item, _ := m.framework.SelectedItem().(listItem); item.label != tt.want {
+				t.Errorf("preselected %q, want %q", item.label, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Refreshing options
+// ---------------------------------------------------------------------------
+
+func TestRefreshOptions_AddsLateRegistrations(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Cobra"},
+		{Language: "Go", Name: "Vanilla", Libraries: []domain.Library{{Name: "Gin"}, {Name: "Gorm"}}},
+		{Language: "Python", Name: "FastAPI"},
+	}
+	m := newWizard(Options{Frameworks: options, DefaultLanguage: "Go", DefaultFramework: "Vanilla"})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage != stageFramework {
+		t.Fatalf("stage = %v, want framework", m.stage)
+	}
+
+	// Registering into the caller's slice does not reach the wizard.
+	options = append(options, domain.Framework{Language: "Go", Name: "Chi"}, domain.Framework{Language: "Rust", Name: "Axum"})
+	options[1].Libraries = append(options[1].Libraries, domain.Library{Name: "Air"})
+	if got := itemLabels(m.framework.Items()); slices.Contains(got, "Chi") {
+		t.Fatalf("frameworks = %v before refresh, want the start-up snapshot", got)
+	}
+
+	updated, _ = m.Update(RefreshOptionsMsg{Frameworks: options})
+	m = updated.(model)
+	if got, want := itemLabels(m.framework.Items()), []string{"Chi", "Cobra", "Vanilla"}; !slices.Equal(got, want) {
+		t.Errorf("frameworks = %v, want %v", got, want)
+	}
+	if item, _ := m.framework.SelectedItem().(listItem); item.label != "Vanilla" {
+		t.Errorf("selected framework = %q, want Vanilla kept", item.label)
+	}
+	if got, want := itemLabels(m.languages.Items()), []string{"Go", "Python", "Rust"}; !slices.Equal(got, want) {
+		t.Errorf("languages = %v, want %v", got, want)
+	}
+	if item, _ := m.languages.SelectedItem().(listItem); item.label != "Go" {
+		t.Errorf("selected language = %q, want Go kept", item.label)
+	}
+
+	// The library list picks up the new library, keeping the cursor and
+	// the ticked box.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(model)
+	options[1].Libraries = append(options[1].Libraries, domain.Library{Name: "Testify"})
+	updated, _ = m.Update(RefreshOptionsMsg{Frameworks: options})
+	m = updated.(model)
+	if got, want := itemLabels(m.libraries.Items()), []string{"[ ] Air", "[ ] Gin", "[x] Gorm", "[ ] Testify"}; !slices.Equal(got, want) {
+		t.Errorf("libraries = %v, want %v", got, want)
+	}
+	if item, _ := m.libraries.SelectedItem().(listItem); item.label != "[x] Gorm" {
+		t.Errorf("selected library = %q, want Gorm kept", item.label)
+	}
+}
+
+func TestRefreshOptions_LeavesEmptyStage(t *testing.T) {
+	m := newWizard(Options{})
+	if m.stage != stageEmpty {
+		t.Fatalf("stage = %v, want empty", m.stage)
+	}
+	updated, _ := m.Update(RefreshOptionsMsg{Frameworks: []domain.Framework{{Language: "Go", Name: "Vanilla"}}})
+	m = updated.(model)
+	if m.stage != stageLanguage {
+		t.Errorf("stage = %v, want language once options arrive", m.stage)
+	}
+	if got := itemLabels(m.languages.Items()); !slices.Equal(got, []string{"Go"}) {
+		t.Errorf("languages = %v, want [Go]", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Monorepo queue
+// ---------------------------------------------------------------------------
+
+// completeProject drives the wizard from the language stage to confirm.
+func completeProject(t *testing.T, m model, language string, framework string, name string) model {
+	t.Helper()
+	selectListItem(&m.languages, language)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	selectListItem(&m.framework, framework)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage == stageLibraries {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(model)
+	}
+	m.name.SetValue(name)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.stage != stageConfirm {
+		t.Fatalf("stage = %v after naming %q, want confirm (name error %q)", m.stage, name, m.nameErr)
+	}
+	return m
+}
+
+func TestMonorepo_QueuesProjects(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, Monorepo: true})
+	m = completeProject(t, m, "Go", "Vanilla", "api")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(model)
+	if m.stage != stageLanguage {
+		t.Fatalf("stage after add = %v, want language", m.stage)
+	}
+
+	m = completeProject(t, m, "Node.js", "Hono", "web")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	result, err := ResultFromModel(updated)
+	if err != nil {
+		t.Fatalf("ResultFromModel() error = %v", err)
+	}
+	want := []Project{
+		{Language: "Go", Framework: "Vanilla", Name: "api"},
+		{Language: "Node.js", Framework: "Hono", Name: "web"},
+	}
+	if len(result.Projects) != len(want) {
+		t.Fatalf("projects = %+v, want %+v", result.Projects, want)
+	}
+	for i, p := range result.Projects {
+		if p.Language != want[i].Language || p.Framework != want[i].Framework || p.Name != want[i].Name {
+			t.Errorf("project %d = %+v, want %+v", i, p, want[i])
+		}
+	}
+}
+
+func TestMonorepo_RejectsDuplicateName(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, Monorepo: true})
+	m = completeProject(t, m, "Go", "Vanilla", "api")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(model)
+
+	selectListItem(&m.languages, "Python")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	m.name.SetValue("API")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	if m.stage != stageName || m.nameErr == "" {
+		t.Errorf("stage = %v, nameErr = %q; want name stage with an error", m.stage, m.nameErr)
+	}
+}
+
+func TestResult_StepsCountsAnsweredStages(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	back := tea.KeyMsg{Type: tea.KeyLeft}
+	press := func(m model, msgs ...tea.KeyMsg) model {
+		for _, msg := range msgs {
+			updated, _ := m.Update(msg)
+			m = updated.(model)
+		}
+		return m
+	}
+	name := func(m model, value string) model {
+		m.name.SetValue(value)
+		return press(m, enter)
+	}
+
+	tests := []struct {
+		name  string
+		opts  Options
+		walk  func(t *testing.T, m model) model
+		steps int
+	}{
+		{
+			name:  "framework with libraries",
+			walk:  func(t *testing.T, m model) model { return completeProject(t, m, "Go", "Vanilla", "api") },
+			steps: 4,
+		},
+		{
+			name:  "framework without libraries",
+			walk:  func(t *testing.T, m model) model { return completeProject(t, m, "Python", "FastAPI", "api") },
+			steps: 3,
+		},
+		{
+			name: "opens on the framework stage",
+			opts: Options{DefaultLanguage: "Go", AskFramework: true},
+			walk: func(t *testing.T, m model) model {
+				selectListItem(&m.framework, "Vanilla")
+				return name(press(m, enter, enter), "api")
+			},
+			steps: 3,
+		},
+		{
+			name: "back from confirm is not counted twice",
+			walk: func(t *testing.T, m model) model {
+				m = completeProject(t, m, "Go", "Vanilla", "api")
+				return press(press(m, back), enter)
+			},
+			steps: 4,
+		},
+		{
+			name: "back to another language",
+			walk: func(t *testing.T, m model) model {
+				selectListItem(&m.languages, "Go")
+				m = press(m, enter)
+				selectListItem(&m.framework, "Vanilla")
+				m = press(m, enter, back, back)
+				if m.stage != stageLanguage {
+					t.Fatalf("stage = %v after going back, want language", m.stage)
+				}
+				selectListItem(&m.languages, "Python")
+				m = press(m, enter)
+				selectListItem(&m.framework, "FastAPI")
+				return name(press(m, enter), "api")
+			},
+			steps: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Frameworks = scaffold.Frameworks
+			m := tt.walk(t, newWizard(tt.opts))
+			if m.stage != stageConfirm {
+				t.Fatalf("stage = %v, want confirm", m.stage)
+			}
+			if m.result.Steps != tt.steps {
+				t.Errorf("Steps = %d, want %d", m.result.Steps, tt.steps)
+			}
+		})
+	}
+}
+
+func TestUpdateName_TabUsesSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		typed string
+		want  string
+	}{
+		{name: "replaces with slug", typed: "My Cool App", want: "my-cool-app"},
+		{name: "no-op when already a slug", typed: "my-cool-app", want: "my-cool-app"},
+		{name: "no-op when empty", typed: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks})
+			m.stage = stageName
+			m.updateBindings()
+			m.name.SetValue(tt.typed)
+
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			m = updated.(model)
+			if got := m.name.Value(); got != tt.want {
+				t.Errorf("name after Tab = %q, want %q", got, tt.want)
+			}
+			if m.stage != stageName {
+				t.Errorf("Tab should stay on the name stage, got %v", m.stage)
+			}
+		})
+	}
+}
+
+// fakeClipboard returns fixed clipboard text.
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+func (c fakeClipboard) ReadAll() (string, error) { return c.text, c.err }
+
+func TestClipboard_SuggestsName(t *testing.T) {
+	tests := []struct {
+		name      string
+		clipboard Clipboard
+		want      string
+	}{
+		{name: "slug", clipboard: fakeClipboard{text: "billing-api\n"}, want: "billing-api"},
+		{name: "not a slug", clipboard: fakeClipboard{text: "Billing API"}},
+		{name: "several lines", clipboard: fakeClipboard{text: "billing-api\nbilling-web"}},
+		{name: "unreadable", clipboard: fakeClipboard{err: errors.New("no clipboard utility")}},
+		{name: "disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks, Clipboard: tt.clipboard})
+			m.stage = stageName
+			m.updateBindings()
+
+			wantPlaceholder := cmp.Or(tt.want, "my-project")
+			if m.name.Placeholder != wantPlaceholder {
+				t.Errorf("Placeholder = %q, want %q", m.name.Placeholder, wantPlaceholder)
+			}
+			if view := m.renderNameInput(); tt.want != "" && !strings.Contains(view, "From the clipboard: "+tt.want) {
+				t.Errorf("missing clipboard hint:\n%s", view)
+			}
+
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			m = updated.(model)
+			if got := m.name.Value(); got != tt.want {
+				t.Errorf("name after Tab = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderNameInput_SlugHintAndCounter(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	m.stage = stageName
+
+	m.name.SetValue("My Cool App")
+	view := m.renderNameInput()
+	if !strings.Contains(view, "Will be created as my-cool-app — press Tab to use this") {
+		t.Errorf("missing slug hint:\n%s", view)
+	}
+	if strings.Contains(view, "/64") {
+		t.Errorf("counter should stay hidden far from the limit:\n%s", view)
+	}
+
+	m.name.SetValue(strings.Repeat("a", 50))
+	view = m.renderNameInput()
+	if strings.Contains(view, "Will be created as") {
+		t.Errorf("no hint expected for a valid slug:\n%s", view)
+	}
+	if !strings.Contains(view, "50/64") {
+		t.Errorf("counter should show near the limit:\n%s", view)
+	}
+}
+
+func TestNameInput_HistoryRecall(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, NameHistory: []string{"billing-api", "web"}})
+	m.stage = stageName
+	m.updateBindings()
+	m.name.SetValue("draft")
+
+	press := func(keyType tea.KeyType) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: keyType})
+		m = updated.(model)
+	}
+
+	steps := []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyUp, "billing-api"},
+		{tea.KeyUp, "web"},
+		{tea.KeyUp, "web"}, // oldest entry stays put
+		{tea.KeyDown, "billing-api"},
+		{tea.KeyDown, "draft"}, // back to what was typed
+		{tea.KeyDown, "draft"},
+	}
+	for i, step := range steps {
+		press(step.key)
+		if got := m.name.Value(); got != step.want {
+			t.Fatalf("step %d: name = %q, want %q", i, got, step.want)
+		}
+	}
+
+	press(tea.KeyUp)
+	press(tea.KeyEnter)
+	if m.result.Name != "billing-api" {
+		t.Errorf("result name = %q, want the recalled billing-api", m.result.Name)
+	}
+}
+
+func TestNameInput_NoHistory(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	m.stage = stageName
+	m.updateBindings()
+	m.name.SetValue("api")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(model)
+	if got := m.name.Value(); got != "api" {
+		t.Errorf("name = %q, want it unchanged without history", got)
+	}
+	if status := ansi.Strip(m.renderStatus(m.stepLabel(), 160)); strings.Contains(status, "recent names") {
+		t.Errorf("status = %q, want no history hint without history", status)
+	}
+}
+
+func TestConfirm_AddIgnoredOutsideMonorepo(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	m = completeProject(t, m, "Go", "Vanilla", "api")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got := updated.(model).stage; got != stageConfirm {
+		t.Errorf("stage = %v, want confirm", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Plan summary
+// ---------------------------------------------------------------------------
+
+func fakePlan(sizes map[string]int) domain.Plan {
+	plan := domain.Plan{ProjectDir: "/tmp/demo"}
+	for path, size := range sizes {
+		plan.Actions = append(plan.Actions, domain.Action{Path: "/tmp/demo/" + path, Content: strings.Repeat("x", size)})
+	}
+	return plan
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		bytes int
+		want  string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1.0 KB"},
+		{8397, "8.2 KB"},
+		{3 * 1024 * 1024 / 2, "1.5 MB"},
+	}
+
+	for _, tt := range tests {
+		if got := humanSize(tt.bytes); got != tt.want {
+			t.Errorf("humanSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizePlan(t *testing.T) {
+	plan := fakePlan(map[string]int{
+		"go.mod":          20,
+		"main.go":         300,
+		"vendor/huge.js":  6000,
+		"README.md":       100,
+		"internal/app.go": 2000,
+	})
+
+	got := summarizePlan(plan, 3)
+	if got.files != 5 || got.bytes != 8420 {
+		t.Errorf("summary = %d files, %d bytes; want 5 files, 8420 bytes", got.files, got.bytes)
+	}
+	want := []fileSize{{"vendor/huge.js", 6000}, {"internal/app.go", 2000}, {"main.go", 300}}
+	if !slices.Equal(got.largest, want) {
+		t.Errorf("largest = %v, want %v", got.largest, want)
+	}
+	if headline := got.headline(); headline != "will create 5 files (8.2 KB)" {
+		t.Errorf("headline = %q", headline)
+	}
+}
+
+func TestConfirm_ShowsPlanSummary(t *testing.T) {
+	var previewed []string
+	m := newWizard(Options{
+		Frameworks: scaffold.Frameworks,
+		Preview: func(r Result) (domain.Plan, error) {
+			previewed = append(previewed, strings.Join(r.Libraries, ","))
+			sizes := map[string]int{"main.go": 1500, "go.mod": 40}
+			for _, lib := range r.Libraries {
+				sizes[strings.ToLower(lib)+".go"] = 700
+			}
+			return fakePlan(sizes), nil
+		},
+	})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	m = completeProject(t, updated.(model), "Go", "Vanilla", "demo")
+
+	view := m.renderConfirmation()
+	for _, want := range []string{"will create 2 files (1.5 KB)", "main.go (1.5 KB)", "go.mod (40 B)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirm view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Going back to add a library refreshes the summary.
+	m.stage = stageLibraries
+	m.updateBindings()
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	if m.stage != stageConfirm {
+		t.Fatalf("stage = %v, want confirm", m.stage)
+	}
+	if view := m.renderConfirmation(); !strings.Contains(view, "will create 3 files") {
+		t.Errorf("summary not refreshed after changing libraries:\n%s", view)
+	}
+	if len(previewed) != 2 {
+		t.Errorf("preview ran %d times, want 2", len(previewed))
+	}
+}
+
+func TestConfirm_PlanErrorOffersRetry(t *testing.T) {
+	planErr := errors.New("mkdir /srv/demo: permission denied")
+	m := newWizard(Options{
+		Frameworks: scaffold.Frameworks,
+		Preview: func(Result) (domain.Plan, error) {
+			return domain.Plan{}, planErr
+		},
+	})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	m = completeProject(t, updated.(model), "Go", "Vanilla", "demo")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if !m.planning || cmd == nil {
+		t.Fatalf("Enter should start planning, planning = %v", m.planning)
+	}
+	if view := m.renderConfirmation(); !strings.Contains(view, "Planning…") {
+		t.Errorf("confirm view should show planning:\n%s", view)
+	}
+	ascii := m
+	ascii.ascii = true
+	if view := ascii.renderConfirmation(); !strings.Contains(view, "Planning...") || strings.Contains(view, "…") {
+		t.Errorf("ASCII confirm view should show planning without an ellipsis glyph:\n%s", view)
+	}
+
+	updated, cmd = m.Update(checkPlans(m.preview, m.planSeq, m.result.Projects)())
+	m = updated.(model)
+	if m.stage != stageConfirm {
+		t.Fatalf("stage = %v, want confirm", m.stage)
+	}
+	if !errors.Is(m.planErr, planErr) {
+		t.Errorf("planErr = %v, want %v", m.planErr, planErr)
+	}
+	if cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Fatal("a plan error should not quit the wizard")
+		}
+	}
+	view := m.renderConfirmation()
+	for _, want := range []string{"permission denied", "Press Enter to retry"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirm view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Once planning succeeds, Enter creates the project.
+	m.preview = func(Result) (domain.Plan, error) { return domain.Plan{}, nil }
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	updated, cmd = m.Update(checkPlans(m.preview, m.planSeq, m.result.Projects)())
+	m = updated.(model)
+	if m.stage != stageDone || m.planErr != nil {
+		t.Fatalf("stage = %v, planErr = %v; want done with no error", m.stage, m.planErr)
+	}
+	if cmd == nil {
+		t.Fatal("expected a quit command")
+	}
+	if _, quit := cmd().(tea.QuitMsg); !quit {
+		t.Error("a successful retry should quit the wizard")
+	}
+}
+
+func TestConfirm_BackDiscardsPendingPlan(t *testing.T) {
+	m := newWizard(Options{
+		Frameworks: scaffold.Frameworks,
+		Preview: func(Result) (domain.Plan, error) {
+			return domain.Plan{}, errors.New("unwritable")
+		},
+	})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	m = completeProject(t, updated.(model), "Go", "Vanilla", "demo")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	msg := checkPlans(m.preview, m.planSeq, m.result.Projects)()
+	m = m.back()
+	m.stage = stageConfirm
+	updated, _ = m.Update(msg)
+	if m := updated.(model); m.planErr != nil {
+		t.Errorf("a result from an abandoned attempt set planErr = %v", m.planErr)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Config files in the status bar
+// ---------------------------------------------------------------------------
+
+func TestRenderStatus_ConfigSegment(t *testing.T) {
+	files := []string{"/home/me/.project-initiator.json", "/srv/team.json"}
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{name: "wide panel shows every name", width: 120, want: ".project-initiator.json + team.json"},
+		{name: "narrow panel truncates", width: 82, want: ".project-initia…"},
+		{name: "too narrow drops it", width: 70, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks, ConfigFiles: files})
+			m.updateBindings()
+			status := ansi.Strip(m.renderStatus(m.stepLabel(), tt.width))
+
+			if tt.want == "" {
+				if strings.Contains(status, ".json") || strings.Contains(status, "…") {
+					t.Errorf("status = %q, want no config segment", status)
+				}
+				return
+			}
+			if !strings.HasSuffix(status, "  "+tt.want) {
+				t.Errorf("status = %q, want it to end in %q", status, tt.want)
+			}
+			if got := lipgloss.Width(status); got != tt.width {
+				t.Errorf("status width = %d, want %d (right-aligned)", got, tt.width)
+			}
+		})
+	}
+}
+
+func TestRenderStatus_StageHelp(t *testing.T) {
+	tests := []struct {
+		stage   stage
+		want    []string
+		notWant []string
+	}{
+		{stage: stageLanguage, want: []string{"enter continue", "esc cancel"}, notWant: []string{"space toggle", "b back", "p pin", "tab"}},
+		{stage: stageFramework, want: []string{"p pin", "b back"}, notWant: []string{"space toggle"}},
+		{stage: stageLibraries, want: []string{"space toggle", "b back"}, notWant: []string{"p pin"}},
+		{stage: stageName, want: []string{"tab use suggested name"}, notWant: []string{"space toggle", "b back"}},
+		{stage: stageConfirm, want: []string{"enter continue", "b back"}, notWant: []string{"space toggle", "a add another"}},
+	}
+	for _, tt := range tests {
+		t.Run(stageTitle(tt.stage), func(t *testing.T) {
+			m := newWizard(Options{Frameworks: scaffold.Frameworks})
+			m.stage = tt.stage
+			m.updateBindings()
+			status := ansi.Strip(m.renderStatus(m.stepLabel(), 160))
+
+			for _, want := range tt.want {
+				if !strings.Contains(status, want) {
+					t.Errorf("status = %q, want %q", status, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(status, notWant) {
+					t.Errorf("status = %q, should not show %q", status, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderStatus_TruncatesNarrowHelp(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks})
+	m.stage = stageLibraries
+	m.updateBindings()
+
+	for _, width := range []int{60, 45, 30} {
+		status := ansi.Strip(m.renderStatus(m.stepLabel(), width))
+		if strings.Contains(status, "\n") {
+			t.Errorf("width %d: status wrapped:\n%s", width, status)
+		}
+		if got := lipgloss.Width(status); got > width {
+			t.Errorf("width %d: status is %d columns: %q", width, got, status)
+		}
+		if !strings.HasPrefix(status, m.stepLabel()) {
+			t.Errorf("width %d: status = %q, want the step label kept", width, status)
+		}
+	}
+}
+
+func TestHelpKey_ShowsConfigPaths(t *testing.T) {
+	files := []string{"/home/me/.project-initiator.json", "/srv/team.json"}
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, ConfigFiles: files})
+	m.updateBindings()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 48})
+	m = updated.(model)
+	height := m.languages.Height()
+
+	updated, _ = m.Update(runeKey('?'))
+	m = updated.(model)
+
+	status := ansi.Strip(m.renderStatus(m.stepLabel(), 82))
+	if want := "Config: " + strings.Join(files, ", "); !strings.Contains(status, want) {
+		t.Errorf("full help missing %q:\n%s", want, status)
+	}
+	if m.languages.Height() >= height {
+		t.Errorf("list height = %d with full help, want less than %d", m.languages.Height(), height)
+	}
+
+	m.stage = stageName
+	m.updateBindings()
+	updated, _ = m.Update(runeKey('?'))
+	m = updated.(model)
+	if !m.help.ShowAll {
+		t.Error("? in the name stage toggled help, want it typed")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Plain banner fallback
+// ---------------------------------------------------------------------------
+
+func TestRenderPlainTitle_NoBlockGlyphs(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, ASCII: true})
+	title := m.renderAnimatedTitle(60)
+
+	if !strings.Contains(title, plainBanner) {
+		t.Errorf("plain title missing banner:\n%s", title)
+	}
+	for _, r := range title {
+		// Box drawing (U+2500–U+257F) and block elements (U+2580–U+259F).
+		if r >= 0x2500 && r <= 0x259F {
+			t.Fatalf("plain title contains non-ASCII glyph %q:\n%s", r, title)
+		}
+	}
+}
+
+func TestRenderPlainTitle_NarrowWidth(t *testing.T) {
+	m := newWizard(Options{Frameworks: scaffold.Frameworks, ASCII: true})
+	if title := m.renderAnimatedTitle(5); strings.TrimSpace(title) == "" {
+		t.Error("narrow plain title should not be empty")
+	}
+}
+
+func TestBlockGlyphsSupported(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "utf-8 lang", env: map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"}, want: true},
+		{name: "utf8 spelling", env: map[string]string{"LANG": "C.utf8"}, want: true},
+		{name: "LC_ALL overrides LANG", env: map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, want: false},
+		{name: "no locale", env: map[string]string{"TERM": "xterm"}, want: false},
+		{name: "linux console", env: map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"}, want: false},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb", "LANG": "en_US.UTF-8"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := blockGlyphsSupported(getenv); got != tt.want {
+				t.Errorf("blockGlyphsSupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List height
+// ---------------------------------------------------------------------------
+
+func TestListHeight_FollowsHeaderComposition(t *testing.T) {
+	size := tea.WindowSizeMsg{Width: 120, Height: 48}
+	newSized := func(ascii bool) model {
+		updated, _ := newWizard(Options{Frameworks: scaffold.Frameworks, ASCII: ascii}).Update(size)
+		return updated.(model)
+	}
+
+	block := newSized(false)
+	plain := newSized(true)
+	blockTitle := lipgloss.Height(block.renderAnimatedTitle(block.panelW - 6))
+	plainTitle := lipgloss.Height(plain.renderAnimatedTitle(plain.panelW - 6))
+	if blockTitle == plainTitle {
+		t.Fatalf("title heights are both %d; the test needs different header compositions", blockTitle)
+	}
+
+	if got, want := block.reservedRows(), panelChromeRows+blockTitle+3; got != want {
+		t.Errorf("reservedRows() = %d, want %d", got, want)
+	}
+	if got, want := plain.listHeightFixed()-block.listHeightFixed(), blockTitle-plainTitle; got != want {
+		t.Errorf("plain title gained %d list rows, want %d", got, want)
+	}
+
+	// The status bar is the last body row, so it is only visible if the
+	// list leaves room for everything else.
+	block.panelReady = true
+	if view := block.View(); !strings.Contains(view, block.stepLabel()) {
+		t.Errorf("status bar clipped from view:\n%s", view)
+	}
+}
+
+func TestNewWizard_DeprecatedListedLast(t *testing.T) {
+	options := []domain.Framework{
+		{Language: "Go", Name: "Alpha", Deprecated: "use Beta", ReplacedBy: "Beta"},
+		{Language: "Go", Name: "Beta", Libraries: []domain.Library{{Name: "Aorm", ReplacedBy: "Gorm"}, {Name: "Gorm"}}},
+		{Language: "Go", Name: "Gamma"},
+	}
+	m := newWizard(Options{Frameworks: options})
+
+	l := buildFrameworkList("Go", m.options, m.frameworkInfo, m.pinned, "", m.styles)
+	if got, want := itemLabels(l.Items()), []string{"Beta", "Gamma", "Alpha"}; !slices.Equal(got, want) {
+		t.Errorf("frameworks = %v, want %v", got, want)
+	}
+	var b strings.Builder
+	listDelegate{styles: m.styles}.Render(&b, l, 0, l.Items()[2])
+	if !strings.Contains(b.String(), "(deprecated)") {
+		t.Errorf("deprecated row = %q, want a (deprecated) suffix", b.String())
+	}
+
+	deprecated := m.frameworkInfo[optionKey("Go", "Beta")].deprecatedLibraries
+	items := buildLibraryItems("Go", "Beta", m.libOptions, m.selectedLibs, deprecated)
+	if got, want := itemLabels(items), []string{"[ ] Gorm", "[ ] Aorm"}; !slices.Equal(got, want) {
+		t.Errorf("libraries = %v, want %v", got, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// shiftHorizontal
+// ---------------------------------------------------------------------------
+
+func FuzzShiftHorizontal(f *testing.F) {
+	styled := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Render("Choose a language")
+	f.Add("hello\nworld", 3, 10)
+	f.Add("hello\nworld", -3, 10)
+	f.Add(styled+"\n"+styled, 5, 12)
+	f.Add(styled, -40, 12)
+	f.Add("日本語のテキスト", 1, 5)
+	f.Add("", 0, 1)
+	f.Add("tab\there", 2, 4)
+	f.Add("wide", 1<<40, 3)
+	f.Add("wide", -1<<62, 3)
+	f.Fuzz(func(t *testing.T, text string, offset int, maxWidth int) {
+		if maxWidth < 1 || maxWidth > 500 {
+			t.Skip("the wizard always passes a content width of at least 1")
+		}
+		got := shiftHorizontal(text, offset, maxWidth)
+		for _, line := range strings.Split(got, "\n") {
+			if width := ansi.StringWidth(line); width > maxWidth {
+				t.Errorf("shiftHorizontal(%q, %d, %d) has a line %d wide: %q", text, offset, maxWidth, width, line)
+			}
+		}
+	})
+}